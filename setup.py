@@ -33,6 +33,7 @@ OPTIONS = {
         "rumps",
         "httpx",
         "yaml",
+        "jinja2",
         "dotenv",
         "pydantic",
         "pydantic_settings",
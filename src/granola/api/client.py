@@ -1,17 +1,45 @@
 """Granola API client."""
 
+import logging
 import ssl
+import time
+from dataclasses import dataclass
+from typing import Any, Callable, Iterable
+from urllib.parse import urlsplit
 
 import certifi
 import httpx
 
-from granola.api.models import Document, DocumentList, DocumentListsResponse, GranolaResponse
+from granola.api.models import (
+    Document,
+    DocumentList,
+    DocumentListsResponse,
+    GranolaResponse,
+    LastViewedPanel,
+    Person,
+    Workspace,
+)
+from granola.cache.reader import TranscriptSegment, parse_transcript_segments
 
 # Constants matching the Go implementation
 USER_AGENT = "Granola/5.354.0"
 X_CLIENT_VERSION = "5.354.0"
 API_URL = "https://api.granola.ai/v2/get-documents"
 DOCUMENT_LISTS_URL = "https://api.granola.ai/v2/get-document-lists"
+TRANSCRIPT_URL = "https://api.granola.ai/v1/get-document-transcript"
+WORKSPACES_URL = "https://api.granola.ai/v1/get-workspaces"
+DOCUMENTS_BATCH_URL = "https://api.granola.ai/v1/get-documents-batch"
+PEOPLE_URL = "https://api.granola.ai/v1/get-people"
+PANELS_URL = "https://api.granola.ai/v1/get-document-panels"
+UPDATE_DOCUMENT_URL = "https://api.granola.ai/v1/update-document"
+
+# Endpoints that can be fetched unparsed (name -> URL)
+ENDPOINTS = {
+    "get-documents": API_URL,
+    "get-document-lists": DOCUMENT_LISTS_URL,
+    "get-workspaces": WORKSPACES_URL,
+    "get-people": PEOPLE_URL,
+}
 
 
 def _get_ssl_context() -> ssl.SSLContext:
@@ -23,21 +51,52 @@ def _get_ssl_context() -> ssl.SSLContext:
 class APIError(Exception):
     """Raised when an API request fails."""
 
-    pass
+    def __init__(self, message: str, status_code: int | None = None):
+        super().__init__(message)
+        self.status_code = status_code
+
+
+@dataclass
+class RequestStats:
+    """Requests made to one API endpoint (one per page of paginated endpoints)."""
+
+    requests: int = 0
+    # Response bytes received
+    bytes: int = 0
+    # Time from sending each request to reading its whole response
+    seconds: float = 0.0
 
 
 class GranolaClient:
     """Client for the Granola API."""
 
-    def __init__(self, access_token: str, timeout: int = 120):
+    def __init__(
+        self,
+        access_token: str,
+        timeout: int = 120,
+        transport: httpx.BaseTransport | None = None,
+        on_response: Callable[[str, float, int], None] | None = None,
+        logger: logging.Logger | None = None,
+    ):
         """Initialize the client.
 
         Args:
             access_token: Bearer token for authentication.
             timeout: Request timeout in seconds.
+            transport: Optional httpx transport requests are sent through (e.g. to
+                record or replay API traffic). Defaults to HTTPS.
+            on_response: Optional function called after each response with the
+                request URL, the seconds it took and the status code.
+            logger: Optional logger; each response is logged at debug level with
+                its time and size.
         """
         self.access_token = access_token
         self.timeout = timeout
+        self.transport = transport
+        self.on_response = on_response
+        self.logger = logger or logging.getLogger(__name__)
+        # Requests made so far per endpoint path, e.g. /v2/get-documents
+        self.request_stats: dict[str, RequestStats] = {}
         self.headers = {
             "Authorization": f"Bearer {access_token}",
             "User-Agent": USER_AGENT,
@@ -46,6 +105,78 @@ class GranolaClient:
             "Accept": "*/*",
         }
 
+    def _http_client(self) -> httpx.Client:
+        """Create an HTTP client for a batch of requests."""
+        event_hooks = self._timing_hooks()
+        if self.transport is not None:
+            return httpx.Client(
+                timeout=self.timeout, transport=self.transport, event_hooks=event_hooks
+            )
+        return httpx.Client(
+            timeout=self.timeout, verify=_get_ssl_context(), event_hooks=event_hooks
+        )
+
+    def _timing_hooks(self) -> dict[str, list[Callable[..., None]]]:
+        """Return httpx event hooks that time and size each request (request_stats)."""
+        started: dict[int, float] = {}
+
+        def on_request(request: httpx.Request) -> None:
+            started[id(request)] = time.perf_counter()
+
+        def on_response(response: httpx.Response) -> None:
+            start = started.pop(id(response.request), None)
+            if start is None:
+                return
+            # Read the body here, so the time includes downloading it
+            response.read()
+            seconds = time.perf_counter() - start
+            url = str(response.request.url)
+            path = urlsplit(url).path
+            size = len(response.content)
+            stats = self.request_stats.setdefault(path, RequestStats())
+            stats.requests += 1
+            stats.bytes += size
+            stats.seconds += seconds
+            self.logger.debug(
+                f"{response.request.method} {path}: {response.status_code}, "
+                f"{size:,} bytes in {seconds:.2f}s"
+            )
+            if self.on_response:
+                self.on_response(url, seconds, response.status_code)
+
+        return {"request": [on_request], "response": [on_response]}
+
+    def _post(self, client: httpx.Client, url: str, json: dict[str, Any]) -> httpx.Response:
+        """Send a POST request to an API endpoint.
+
+        Args:
+            client: HTTP client from _http_client().
+            url: Endpoint URL.
+            json: Request body.
+
+        Returns:
+            The response, if its status is a success.
+
+        Raises:
+            APIError: If the request fails or gets an error status (kept as
+                status_code).
+        """
+        try:
+            response = client.post(url, headers=self.headers, json=json)
+            response.raise_for_status()
+
+        except httpx.HTTPStatusError as e:
+            body_preview = e.response.text[:200] if e.response.text else ""
+            raise APIError(
+                f"API request failed: status={e.response.status_code}, body={body_preview}",
+                status_code=e.response.status_code,
+            ) from e
+
+        except httpx.RequestError as e:
+            raise APIError(f"API request failed: {e}") from e
+
+        return response
+
     def get_documents(self) -> list[Document]:
         """Fetch all documents from the API with pagination.
 
@@ -59,28 +190,13 @@ class GranolaClient:
         offset = 0
         limit = 100
 
-        with httpx.Client(timeout=self.timeout, verify=_get_ssl_context()) as client:
+        with self._http_client() as client:
             while True:
-                try:
-                    response = client.post(
-                        API_URL,
-                        headers=self.headers,
-                        json={
-                            "limit": limit,
-                            "offset": offset,
-                            "include_last_viewed_panel": True,
-                        },
-                    )
-                    response.raise_for_status()
-
-                except httpx.HTTPStatusError as e:
-                    body_preview = e.response.text[:200] if e.response.text else ""
-                    raise APIError(
-                        f"API request failed: status={e.response.status_code}, body={body_preview}"
-                    ) from e
-
-                except httpx.RequestError as e:
-                    raise APIError(f"API request failed: {e}") from e
+                response = self._post(
+                    client,
+                    API_URL,
+                    {"limit": limit, "offset": offset, "include_last_viewed_panel": True},
+                )
 
                 # Parse response
                 try:
@@ -100,32 +216,87 @@ class GranolaClient:
 
         return documents
 
-    def get_document_lists(self) -> list[DocumentList]:
-        """Fetch all document lists (folders) from the API.
+    def get_documents_batch(self, doc_ids: Iterable[str]) -> list[Document]:
+        """Fetch specific documents by ID, including ones shared with the user.
+
+        get_documents() only returns the user's own documents; this also returns
+        documents teammates have shared (e.g. through a shared folder).
+
+        Args:
+            doc_ids: IDs of the documents to fetch.
 
         Returns:
-            List of document lists with their document IDs.
+            The documents that were found.
 
         Raises:
             APIError: If the API request fails.
         """
-        with httpx.Client(timeout=self.timeout, verify=_get_ssl_context()) as client:
-            try:
-                response = client.post(
-                    DOCUMENT_LISTS_URL,
-                    headers=self.headers,
-                    json={},
+        ids = list(doc_ids)
+        documents: list[Document] = []
+        batch_size = 100
+
+        with self._http_client() as client:
+            for start in range(0, len(ids), batch_size):
+                response = self._post(
+                    client,
+                    DOCUMENTS_BATCH_URL,
+                    {
+                        "document_ids": ids[start : start + batch_size],
+                        "include_last_viewed_panel": True,
+                    },
                 )
-                response.raise_for_status()
 
-            except httpx.HTTPStatusError as e:
-                body_preview = e.response.text[:200] if e.response.text else ""
-                raise APIError(
-                    f"API request failed: status={e.response.status_code}, body={body_preview}"
-                ) from e
+                try:
+                    data = response.json()
+                    granola_response = GranolaResponse.model_validate(data)
+                except Exception as e:
+                    raise APIError(f"Failed to parse documents batch response: {e}") from e
+
+                documents.extend(granola_response.docs)
+
+        return documents
+
+    def update_document_notes(
+        self,
+        doc_id: str,
+        notes: dict[str, Any],
+        notes_markdown: str,
+        notes_plain: str,
+    ) -> None:
+        """Replace a document's notes.
 
-            except httpx.RequestError as e:
-                raise APIError(f"API request failed: {e}") from e
+        Args:
+            doc_id: ID of the document to update.
+            notes: New notes as ProseMirror JSON.
+            notes_markdown: The same notes as Markdown.
+            notes_plain: The same notes as plain text.
+
+        Raises:
+            APIError: If the API request fails.
+        """
+        with self._http_client() as client:
+            self._post(
+                client,
+                UPDATE_DOCUMENT_URL,
+                {
+                    "id": doc_id,
+                    "notes": notes,
+                    "notes_markdown": notes_markdown,
+                    "notes_plain": notes_plain,
+                },
+            )
+
+    def get_document_lists(self) -> list[DocumentList]:
+        """Fetch all document lists (folders) from the API.
+
+        Returns:
+            List of document lists with their document IDs.
+
+        Raises:
+            APIError: If the API request fails.
+        """
+        with self._http_client() as client:
+            response = self._post(client, DOCUMENT_LISTS_URL, {})
 
             # Parse response
             try:
@@ -163,3 +334,194 @@ class GranolaClient:
                     doc_folders[doc_id].append(folder_title)
 
         return folders, doc_folders
+
+    def get_workspaces(self) -> list[Workspace]:
+        """Fetch the workspaces the user belongs to.
+
+        Returns:
+            List of workspaces.
+
+        Raises:
+            APIError: If the API request fails.
+        """
+        with self._http_client() as client:
+            response = self._post(client, WORKSPACES_URL, {})
+
+            try:
+                data = response.json()
+            except ValueError as e:
+                raise APIError(f"Failed to parse workspaces response: {e}") from e
+
+        # Entries are {"workspace": {...}, "role": ...}; accept bare workspaces too
+        entries = data.get("workspaces", []) if isinstance(data, dict) else data
+        workspaces: list[Workspace] = []
+        for entry in entries if isinstance(entries, list) else []:
+            if not isinstance(entry, dict):
+                continue
+            info = entry.get("workspace", entry)
+            if not isinstance(info, dict):
+                continue
+            workspace_id = info.get("workspace_id") or info.get("id")
+            if not workspace_id:
+                continue
+            workspaces.append(
+                Workspace(
+                    id=str(workspace_id),
+                    name=str(info.get("display_name") or info.get("name") or ""),
+                    slug=str(info.get("slug") or ""),
+                )
+            )
+        return workspaces
+
+    def get_people(self) -> list[Person]:
+        """Fetch the user's people directory (everyone they've met with).
+
+        Granola enriches these with names, companies and job titles, which the
+        attendee lists of documents (and the local cache) may lack.
+
+        Returns:
+            List of people.
+
+        Raises:
+            APIError: If the API request fails.
+        """
+        with self._http_client() as client:
+            response = self._post(client, PEOPLE_URL, {})
+
+            try:
+                data = response.json()
+            except ValueError as e:
+                raise APIError(f"Failed to parse people response: {e}") from e
+
+        # A bare list of people; accept {"people": [...]} too
+        entries = data.get("people", []) if isinstance(data, dict) else data
+        return [
+            Person.from_api(entry)
+            for entry in (entries if isinstance(entries, list) else [])
+            if isinstance(entry, dict)
+        ]
+
+    def get_transcripts(self, doc_ids: Iterable[str]) -> dict[str, list[TranscriptSegment]]:
+        """Fetch the transcripts of documents from the API.
+
+        Makes one request per document, so prefer the local cache when it's
+        available.
+
+        Args:
+            doc_ids: IDs of the documents to fetch transcripts for.
+
+        Returns:
+            Dict mapping doc_id -> transcript segments. Documents without a
+            transcript are omitted, as are documents whose request fails (logged
+            as a warning), so one failure doesn't lose the other transcripts.
+        """
+        transcripts: dict[str, list[TranscriptSegment]] = {}
+
+        with self._http_client() as client:
+            for doc_id in doc_ids:
+                try:
+                    response = self._post(client, TRANSCRIPT_URL, {"document_id": doc_id})
+                    data = response.json()
+                except APIError as e:
+                    # A 404 means no transcript was recorded for this document
+                    if e.status_code != 404:
+                        self.logger.warning(f"Failed to fetch transcript of {doc_id}: {e}")
+                    continue
+                except ValueError as e:
+                    self.logger.warning(f"Failed to parse transcript response of {doc_id}: {e}")
+                    continue
+
+                segments = parse_transcript_segments(data if isinstance(data, list) else [], doc_id)
+                if segments:
+                    transcripts[doc_id] = segments
+
+        return transcripts
+
+    def get_document_panels(self, doc_ids: Iterable[str]) -> dict[str, list[LastViewedPanel]]:
+        """Fetch every panel (AI notes generated with each template) of documents.
+
+        get_documents() only includes the last viewed panel, which can be stale or
+        missing. Makes one request per document.
+
+        Args:
+            doc_ids: IDs of the documents to fetch panels for.
+
+        Returns:
+            Dict mapping doc_id -> panels, oldest first. Deleted panels and
+            documents without panels are omitted, as are documents whose request
+            fails (logged as a warning).
+        """
+        panels: dict[str, list[LastViewedPanel]] = {}
+
+        with self._http_client() as client:
+            for doc_id in doc_ids:
+                try:
+                    response = self._post(client, PANELS_URL, {"document_id": doc_id})
+                except APIError as e:
+                    # A 404 means the document has no panels
+                    if e.status_code != 404:
+                        self.logger.warning(f"Failed to fetch panels of {doc_id}: {e}")
+                    continue
+
+                try:
+                    data = response.json()
+                    entries = data.get("panels", []) if isinstance(data, dict) else data
+                    doc_panels = [
+                        LastViewedPanel.model_validate(entry)
+                        for entry in (entries if isinstance(entries, list) else [])
+                    ]
+                except Exception as e:
+                    self.logger.warning(f"Failed to parse panels response of {doc_id}: {e}")
+                    continue
+
+                doc_panels = [p for p in doc_panels if not p.deleted_at]
+                doc_panels.sort(key=lambda p: p.created_at or "")
+                if doc_panels:
+                    panels[doc_id] = doc_panels
+
+        return panels
+
+    def get_raw_responses(self, endpoint: str) -> list[Any]:
+        """Fetch the unparsed JSON responses of an endpoint, following pagination.
+
+        Used to capture real payloads for bug reports; nothing is validated.
+
+        Args:
+            endpoint: Endpoint name (a key of ENDPOINTS).
+
+        Returns:
+            One decoded JSON body per request made.
+
+        Raises:
+            ValueError: If the endpoint is unknown.
+            APIError: If a request fails or doesn't return JSON.
+        """
+        if endpoint not in ENDPOINTS:
+            raise ValueError(
+                f"Unknown endpoint '{endpoint}'. Choose one of: {', '.join(ENDPOINTS)}"
+            )
+        paginated = endpoint == "get-documents"
+        limit = 100
+        offset = 0
+        bodies: list[Any] = []
+
+        with self._http_client() as client:
+            while True:
+                payload: dict[str, Any] = {}
+                if paginated:
+                    payload = {"limit": limit, "offset": offset, "include_last_viewed_panel": True}
+                response = self._post(client, ENDPOINTS[endpoint], payload)
+
+                try:
+                    body = response.json()
+                except ValueError as e:
+                    raise APIError(f"API returned invalid JSON: {e}") from e
+                bodies.append(body)
+
+                # Same stop condition as get_documents()
+                docs = body.get("docs") if isinstance(body, dict) else None
+                if not paginated or not isinstance(docs, list) or len(docs) < limit:
+                    break
+                offset += limit
+
+        return bodies
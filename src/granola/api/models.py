@@ -3,10 +3,24 @@
 from __future__ import annotations
 
 import json
+from datetime import datetime
 from typing import Any, Optional
 
 from pydantic import BaseModel, Field, field_validator
 
+from granola.cache.reader import conference_url, creator_name, event_start, event_uid
+from granola.utils.dates import latest_timestamp, parse_timestamp
+
+# Documents shared by link are viewable at <base>/<document id>
+SHARE_URL_BASE = "https://notes.granola.ai/d"
+
+# Links that open a document in Granola (--deep-link): the desktop app, which has
+# audio playback, or the web app
+DEEP_LINKS = {
+    "app": "granola://document/{id}",
+    "web": "https://notes.granola.ai/d/{id}",
+}
+
 
 class ProseMirrorNode(BaseModel):
     """A node in the ProseMirror document structure."""
@@ -24,6 +38,20 @@ class ProseMirrorDoc(BaseModel):
     content: list[ProseMirrorNode] = Field(default_factory=list)
 
 
+class SuggestedQuestion(BaseModel):
+    """A question Granola suggests asking about a meeting, with its answer once asked."""
+
+    question: str
+    answer: Optional[str] = None
+
+
+class GeneratedLine(BaseModel):
+    """A line of an answer Granola's AI chat generated, with the question it answers."""
+
+    text: str
+    question: Optional[str] = None
+
+
 class LastViewedPanel(BaseModel):
     """Contains ProseMirror content and metadata for a document panel."""
 
@@ -39,8 +67,20 @@ class LastViewedPanel(BaseModel):
     template_slug: Optional[str] = None
     last_viewed_at: Optional[str] = None
     affinity_note_id: Optional[str] = None
-    suggested_questions: Any = None
-    generated_lines: Optional[list[Any]] = None
+    suggested_questions: list[SuggestedQuestion] = Field(default_factory=list)
+    generated_lines: list[GeneratedLine] = Field(default_factory=list)
+
+    @field_validator("suggested_questions", mode="before")
+    @classmethod
+    def parse_suggested_questions(cls, v: Any) -> list[SuggestedQuestion]:
+        """Handle suggested questions in any of the shapes the API returns them in."""
+        return parse_suggested_questions(v)
+
+    @field_validator("generated_lines", mode="before")
+    @classmethod
+    def parse_generated_lines(cls, v: Any) -> list[GeneratedLine]:
+        """Handle generated lines in any of the shapes the API returns them in."""
+        return parse_generated_lines(v)
 
     @field_validator("content", mode="before")
     @classmethod
@@ -86,6 +126,70 @@ class Document(BaseModel):
     last_viewed_panel: Optional[LastViewedPanel] = None
     notes: Optional[ProseMirrorDoc] = None
     notes_plain: Optional[str] = None
+    workspace_id: Optional[str] = None
+    people: Optional[dict[str, Any]] = None
+    public: Optional[bool] = None
+    sharing_link_visibility: Optional[str] = None
+    public_url: Optional[str] = None
+    google_calendar_event: Optional[dict[str, Any]] = None
+
+    @property
+    def link(self) -> Optional[str]:
+        """URL colleagues can open the document at, if it's shared by link."""
+        if self.public_url:
+            return self.public_url
+        if self.public or self.sharing_link_visibility in ("public", "workspace"):
+            return f"{SHARE_URL_BASE}/{self.id}"
+        return None
+
+    @property
+    def content_updated_at(self) -> str:
+        """When the document or its notes last changed.
+
+        AI notes can be regenerated after the document's updated_at, so this is
+        the latest of updated_at and the last viewed panel's timestamps.
+        """
+        panel = self.last_viewed_panel
+        if panel is None:
+            return self.updated_at
+        return (
+            latest_timestamp(self.updated_at, panel.updated_at, panel.content_updated_at)
+            or self.updated_at
+        )
+
+    @property
+    def owner(self) -> Optional[str]:
+        """Name (or email) of the document's creator, if known."""
+        return creator_name(self.people)
+
+    @property
+    def attendees(self) -> list[Person]:
+        """People invited to the meeting (from the calendar event), if known."""
+        return parse_attendees(self.people)
+
+    @property
+    def meeting_url(self) -> Optional[str]:
+        """Zoom/Meet/Teams link of the meeting's calendar event, if known."""
+        return conference_url(self.google_calendar_event)
+
+    @property
+    def event_uid(self) -> Optional[str]:
+        """iCalendar UID of the meeting's calendar event, if known."""
+        return event_uid(self.google_calendar_event)
+
+    @property
+    def event_start(self) -> Optional[str]:
+        """Start of the meeting's calendar event, in the event's timezone, if known."""
+        return event_start(self.google_calendar_event)
+
+    @property
+    def local_start(self) -> Optional[datetime]:
+        """When the meeting started in local time (see ExportDoc.local_start), if known."""
+        started = parse_timestamp(self.event_start)
+        if started is not None:
+            return started
+        created = parse_timestamp(self.created_at)
+        return created.astimezone() if created else None
 
     @field_validator("notes", mode="before")
     @classmethod
@@ -135,3 +239,128 @@ class DocumentListsResponse(BaseModel):
     """API response containing document lists."""
 
     lists: list[DocumentList] = Field(default_factory=list)
+
+
+class Person(BaseModel):
+    """A meeting attendee or contact."""
+
+    name: str = ""
+    email: str = ""
+    company: str = ""
+    job_title: str = ""
+
+    @classmethod
+    def from_api(cls, data: dict[str, Any]) -> Person:
+        """Parse a person from a document's attendees or the people endpoint.
+
+        Both put enrichment data (full name, company, title) under "details" when
+        Granola has it; the people endpoint also has flat company_name/job_title.
+        """
+        details = _as_dict(data.get("details"))
+        person = _as_dict(details.get("person"))
+        company = _as_dict(details.get("company"))
+        return cls(
+            name=str(data.get("name") or _as_dict(person.get("name")).get("fullName") or ""),
+            email=str(data.get("email") or ""),
+            company=str(data.get("company_name") or company.get("name") or ""),
+            job_title=str(
+                data.get("job_title") or _as_dict(person.get("employment")).get("title") or ""
+            ),
+        )
+
+    def merge(self, other: Person) -> Person:
+        """Return this person with empty fields filled in from another record."""
+        return Person(
+            name=self.name or other.name,
+            email=self.email or other.email,
+            company=self.company or other.company,
+            job_title=self.job_title or other.job_title,
+        )
+
+    def to_metadata(self) -> dict[str, str]:
+        """Return the non-empty fields, for frontmatter."""
+        return {k: v for k, v in self.model_dump().items() if v}
+
+
+def parse_attendees(people: Any) -> list[Person]:
+    """Parse the attendees from a document's people data."""
+    if not isinstance(people, dict) or not isinstance(people.get("attendees"), list):
+        return []
+    return [Person.from_api(a) for a in people["attendees"] if isinstance(a, dict)]
+
+
+def parse_suggested_questions(value: Any) -> list[SuggestedQuestion]:
+    """Parse a panel's suggested_questions, skipping entries that aren't questions.
+
+    The API returns plain strings, objects with the question (and answer once
+    asked) under one of several keys, or either of those wrapped in an object or
+    double-encoded as a JSON string.
+    """
+    value = _decode(value)
+    if isinstance(value, dict):
+        value = value.get("questions", value.get("suggested_questions"))
+    questions: list[SuggestedQuestion] = []
+    for item in value if isinstance(value, list) else []:
+        if isinstance(item, dict):
+            question = _first_text(item, "question", "text", "query", "title")
+            answer = _first_text(item, "answer", "response")
+        elif isinstance(item, str):
+            question, answer = item, None
+        else:
+            continue
+        if question.strip():
+            questions.append(SuggestedQuestion(question=question.strip(), answer=answer or None))
+    return questions
+
+
+def parse_generated_lines(value: Any) -> list[GeneratedLine]:
+    """Parse a panel's generated_lines (strings or objects), skipping empty lines."""
+    value = _decode(value)
+    lines: list[GeneratedLine] = []
+    for item in value if isinstance(value, list) else []:
+        if isinstance(item, dict):
+            text = _first_text(item, "text", "line", "content")
+            question = _first_text(item, "question", "prompt", "query")
+        elif isinstance(item, str):
+            text, question = item, ""
+        else:
+            continue
+        if text.strip():
+            lines.append(GeneratedLine(text=text.rstrip(), question=question.strip() or None))
+    return lines
+
+
+def _decode(value: Any) -> Any:
+    """Return a double-encoded JSON value decoded (other values as they are)."""
+    if isinstance(value, str) and value.strip()[:1] in ("[", "{"):
+        try:
+            return json.loads(value)
+        except json.JSONDecodeError:
+            return None
+    return value
+
+
+def _first_text(data: dict[str, Any], *keys: str) -> str:
+    """Return the first of keys in data whose value is a string, or ""."""
+    for key in keys:
+        if isinstance(data.get(key), str):
+            return data[key]
+    return ""
+
+
+def _as_dict(value: Any) -> dict[str, Any]:
+    """Return value if it's a dict, else an empty one (for optional nested data)."""
+    return value if isinstance(value, dict) else {}
+
+
+class Workspace(BaseModel):
+    """A Granola workspace (team or personal) the user belongs to."""
+
+    id: str
+    name: str = ""
+    slug: str = ""
+
+    def matches(self, value: str) -> bool:
+        """Return True if value is this workspace's ID, name, or slug (case-insensitive)."""
+        value = value.strip().lower()
+        return value in (self.id.lower(), self.name.lower(), self.slug.lower())
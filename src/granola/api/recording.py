@@ -0,0 +1,114 @@
+"""Record and replay API traffic for reproducible debugging.
+
+RecordingTransport saves every API response to a fixtures directory;
+ReplayTransport answers requests from such a directory without touching the
+network. A user can record a failing export and share the fixtures, and a
+maintainer can replay them to reproduce it.
+"""
+
+import hashlib
+import json
+import re
+from pathlib import Path
+from typing import Any
+
+import httpx
+
+from granola.api.client import _get_ssl_context
+from granola.api.redact import redact
+
+
+class RecordingTransport(httpx.BaseTransport):
+    """Forwards requests to the network and saves each response as a fixture."""
+
+    def __init__(self, directory: Path, transport: httpx.BaseTransport | None = None):
+        """Initialize the transport.
+
+        Args:
+            directory: Directory to write fixtures to (created if missing).
+            transport: Transport that actually sends the requests (default: HTTPS
+                with certifi's CA bundle, like GranolaClient).
+        """
+        self.directory = directory
+        self.transport = transport or httpx.HTTPTransport(verify=_get_ssl_context())
+
+    def handle_request(self, request: httpx.Request) -> httpx.Response:
+        response = self.transport.handle_request(request)
+        response.read()
+
+        self.directory.mkdir(parents=True, exist_ok=True)
+        fixture = {
+            "request": {
+                "method": request.method,
+                "url": str(request.url),
+                "body": _decode(request.content),
+            },
+            "response": {
+                "status_code": response.status_code,
+                "content_type": response.headers.get("content-type", ""),
+                # Request headers (with the bearer token) are never saved
+                "body": redact(_decode(response.content)),
+            },
+        }
+        (self.directory / fixture_name(request)).write_text(
+            json.dumps(fixture, indent=2, ensure_ascii=False), encoding="utf-8"
+        )
+        return response
+
+    def close(self) -> None:
+        # GranolaClient opens and closes an httpx.Client per call, all sharing this
+        # transport, so the underlying connections must outlive each of them
+        pass
+
+
+class ReplayTransport(httpx.BaseTransport):
+    """Answers requests from recorded fixtures instead of the network."""
+
+    def __init__(self, directory: Path):
+        """Initialize the transport.
+
+        Args:
+            directory: Directory of fixtures written by RecordingTransport.
+        """
+        self.directory = directory
+
+    def handle_request(self, request: httpx.Request) -> httpx.Response:
+        path = self.directory / fixture_name(request)
+        if not path.is_file():
+            raise httpx.ConnectError(
+                f"No recorded response for {request.method} {request.url} in {self.directory}",
+                request=request,
+            )
+        try:
+            recorded = json.loads(path.read_text(encoding="utf-8"))["response"]
+        except (OSError, json.JSONDecodeError, KeyError, TypeError) as e:
+            raise httpx.ConnectError(f"Invalid fixture {path}: {e}", request=request) from e
+
+        body = recorded.get("body")
+        content = body if isinstance(body, str) else json.dumps(body)
+        return httpx.Response(
+            status_code=int(recorded.get("status_code", 200)),
+            headers={"content-type": recorded.get("content_type") or "application/json"},
+            content=content.encode("utf-8"),
+            request=request,
+        )
+
+
+def fixture_name(request: httpx.Request) -> str:
+    """Return the fixture file name for a request.
+
+    Requests to the same endpoint with different bodies (e.g. pagination
+    offsets) get different fixtures.
+    """
+    endpoint = re.sub(r"[^a-z0-9]+", "-", request.url.path.lower()).strip("-")
+    digest = hashlib.sha1(request.content).hexdigest()[:12]
+    return f"{request.method.lower()}-{endpoint}-{digest}.json"
+
+
+def _decode(content: bytes) -> Any:
+    """Decode a body as JSON, falling back to text."""
+    text = content.decode("utf-8", errors="replace")
+    try:
+        return json.loads(text)
+    except json.JSONDecodeError:
+        return text
@@ -0,0 +1,32 @@
+"""Redaction of credentials from API payloads before they are shared."""
+
+import re
+from typing import Any
+
+REDACTED = "[REDACTED]"
+
+# Keys whose values are credentials
+_SENSITIVE_KEY = re.compile(
+    r"token|secret|password|authorization|cookie|api_?key", re.IGNORECASE
+)
+
+# JSON Web Tokens embedded in other values
+_JWT = re.compile(r"eyJ[\w-]+\.[\w-]+\.[\w-]+")
+
+
+def redact(value: Any) -> Any:
+    """Return a copy of a decoded JSON value with credentials replaced.
+
+    Values of keys that look like credentials (access_token, api_key, ...) are
+    replaced entirely; JWTs found in any other string are replaced in place.
+    """
+    if isinstance(value, dict):
+        return {
+            key: REDACTED if _SENSITIVE_KEY.search(str(key)) and item else redact(item)
+            for key, item in value.items()
+        }
+    if isinstance(value, list):
+        return [redact(item) for item in value]
+    if isinstance(value, str):
+        return _JWT.sub(REDACTED, value)
+    return value
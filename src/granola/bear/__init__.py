@@ -0,0 +1,9 @@
+"""Bear (macOS notes app) import support for Granola CLI."""
+
+from granola.bear.client import BearClient, BearError, BearNote
+
+__all__ = [
+    "BearClient",
+    "BearError",
+    "BearNote",
+]
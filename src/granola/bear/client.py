@@ -0,0 +1,134 @@
+"""Bear client: notes are written through Bear's x-callback-url API.
+
+Bear's URL API can create and replace notes but only reports note IDs to a
+callback URL, so existing notes are found in Bear's database (read-only)
+instead, by the tag carrying their Granola document ID.
+"""
+
+import logging
+import re
+import sqlite3
+import subprocess
+from dataclasses import dataclass
+from pathlib import Path
+from urllib.parse import quote
+
+# Bear's note database (Bear 2)
+DEFAULT_DATABASE = (
+    Path.home()
+    / "Library"
+    / "Group Containers"
+    / "9K33E3U3T4.net.shinyfrog.bear"
+    / "Application Data"
+    / "database.sqlite"
+)
+
+# Tag prefix used to key notes by Granola document ID (#granola/<doc id>)
+DOC_TAG_PREFIX = "granola/"
+
+_DOC_TAG = re.compile(rf"#{re.escape(DOC_TAG_PREFIX)}([0-9A-Za-z-]+)")
+
+
+class BearError(Exception):
+    """Raised when Bear can't be read or written."""
+
+
+@dataclass
+class BearNote:
+    """A note in Bear's database."""
+
+    id: str  # Bear's unique identifier
+    text: str
+
+
+def doc_tag(doc_id: str) -> str:
+    """Return the tag that identifies the note for a Granola document."""
+    return f"#{DOC_TAG_PREFIX}{doc_id}"
+
+
+def bear_tag(name: str) -> str:
+    """Return a Bear tag for a name; tags with spaces are closed with a second #.
+
+    Returns an empty string if nothing of the name is left for a tag.
+    """
+    name = " ".join(name.replace("#", "").split())
+    if not name:
+        return ""
+    return f"#{name}#" if " " in name else f"#{name}"
+
+
+class BearClient:
+    """Creates and updates Bear notes, in the background (Bear is not brought forward)."""
+
+    def __init__(self, database: Path = DEFAULT_DATABASE, logger: logging.Logger | None = None):
+        """Initialize the client.
+
+        Args:
+            database: Path of Bear's database.sqlite.
+            logger: Optional logger for debug output.
+        """
+        self.database = database
+        self.logger = logger or logging.getLogger(__name__)
+
+    def find_doc_notes(self) -> dict[str, BearNote]:
+        """Return Granola document ID -> its note, for notes not in the trash.
+
+        Raises:
+            BearError: If Bear's database can't be read.
+        """
+        if not self.database.is_file():
+            raise BearError(f"Bear database not found at {self.database}; is Bear installed?")
+        try:
+            connection = sqlite3.connect(f"{self.database.as_uri()}?mode=ro", uri=True)
+            try:
+                rows = connection.execute(
+                    "SELECT ZUNIQUEIDENTIFIER, ZTEXT FROM ZSFNOTE "
+                    "WHERE ZTRASHED = 0 AND ZTEXT LIKE ?",
+                    (f"%#{DOC_TAG_PREFIX}%",),
+                ).fetchall()
+            finally:
+                connection.close()
+        except sqlite3.Error as e:
+            raise BearError(f"Failed to read Bear database {self.database}: {e}") from e
+
+        notes: dict[str, BearNote] = {}
+        for note_id, text in rows:
+            match = _DOC_TAG.search(text or "")
+            if match:
+                notes.setdefault(match.group(1), BearNote(id=note_id, text=text))
+        return notes
+
+    def create_note(self, text: str) -> None:
+        """Create a note (its title is the text's first heading).
+
+        Raises:
+            BearError: If Bear can't be opened.
+        """
+        self._open("create", {"text": text, "open_note": "no", "show_window": "no"})
+
+    def replace_note(self, note_id: str, text: str) -> None:
+        """Replace the text (title included) of an existing note.
+
+        Raises:
+            BearError: If Bear can't be opened.
+        """
+        self._open(
+            "add-text",
+            {
+                "id": note_id,
+                "mode": "replace_all",
+                "text": text,
+                "open_note": "no",
+                "show_window": "no",
+            },
+        )
+
+    def _open(self, action: str, params: dict[str, str]) -> None:
+        """Open a Bear x-callback-url in the background."""
+        query = "&".join(f"{key}={quote(value, safe='')}" for key, value in params.items())
+        url = f"bear://x-callback-url/{action}?{query}"
+        self.logger.debug(f"Opening bear://x-callback-url/{action} ({len(url)} characters)")
+        try:
+            subprocess.run(["open", "-g", url], check=True, capture_output=True)
+        except (OSError, subprocess.CalledProcessError) as e:
+            raise BearError(f"Failed to open Bear: {e}") from e
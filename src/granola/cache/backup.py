@@ -0,0 +1,79 @@
+"""Timestamped backups of the Granola cache file.
+
+Granola prunes old transcripts from its cache, and the cache is the only local
+copy of them. Backups are named after the file they copy plus the time they were
+taken (cache-v3-20240612-153000.json), so they sort oldest first.
+"""
+
+import json
+import re
+import shutil
+from datetime import datetime
+from pathlib import Path
+
+from granola.api.redact import redact
+
+# Timestamp format of backup file names
+STAMP_FORMAT = "%Y%m%d-%H%M%S"
+
+
+def backup_name(source: Path, taken_at: datetime) -> str:
+    """Return the file name of a backup of source taken at taken_at."""
+    return f"{source.stem}-{taken_at.strftime(STAMP_FORMAT)}{source.suffix}"
+
+
+def backup_file(source: Path, output_dir: Path, taken_at: datetime) -> Path:
+    """Copy a file into output_dir under its timestamped backup name.
+
+    Raises:
+        OSError: If the file can't be copied.
+    """
+    target = output_dir / backup_name(source, taken_at)
+    output_dir.mkdir(parents=True, exist_ok=True)
+    shutil.copy2(source, target)
+    return target
+
+
+def backup_redacted(source: Path, output_dir: Path, taken_at: datetime) -> Path:
+    """Write a copy of a JSON file with its credentials redacted into output_dir.
+
+    supabase.json keeps its tokens in JSON strings nested in the file; those
+    keys are redacted as a whole.
+
+    Raises:
+        OSError: If the file can't be read or written.
+        ValueError: If the file isn't valid JSON.
+    """
+    data = json.loads(source.read_text(encoding="utf-8"))
+    target = output_dir / backup_name(source, taken_at)
+    output_dir.mkdir(parents=True, exist_ok=True)
+    target.write_text(json.dumps(redact(data), indent=2, ensure_ascii=False), encoding="utf-8")
+    return target
+
+
+def list_backups(source: Path, output_dir: Path) -> list[Path]:
+    """Return the backups of source in output_dir, oldest first."""
+    pattern = re.compile(
+        rf"{re.escape(source.stem)}-\d{{8}}-\d{{6}}{re.escape(source.suffix)}"
+    )
+    if not output_dir.is_dir():
+        return []
+    return sorted(
+        path for path in output_dir.iterdir() if path.is_file() and pattern.fullmatch(path.name)
+    )
+
+
+def prune_backups(source: Path, output_dir: Path, keep: int) -> list[Path]:
+    """Delete all but the keep newest backups of source in output_dir.
+
+    Raises:
+        OSError: If a backup can't be deleted.
+
+    Returns:
+        The deleted backups.
+    """
+    backups = list_backups(source, output_dir)
+    stale = backups[: max(len(backups) - keep, 0)]
+    for path in stale:
+        path.unlink()
+    return stale
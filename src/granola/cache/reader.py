@@ -1,9 +1,14 @@
 """Cache file reader for Granola local cache."""
 
 import json
+import re
 from dataclasses import dataclass, field
 from pathlib import Path
-from typing import Optional
+from typing import Any, Optional
+
+
+# Document fields that may hold the path of a local audio recording
+AUDIO_KEYS = ("audio_file_path", "audio_path", "recording_path")
 
 
 @dataclass
@@ -15,8 +20,20 @@ class TranscriptSegment:
     start_timestamp: str
     end_timestamp: str
     text: str
-    source: str  # "system" or "microphone"
+    source: str  # "system" or "microphone" ("notes" for notes in an interleaved layout)
     is_final: bool
+    speaker: Optional[str] = None  # Real name of the speaker, if mapped (see config.speakers)
+    heading: Optional[str] = None  # Heading of the section this segment starts, if any
+    summary: Optional[str] = None  # One-line summary of that section, if any
+
+    @property
+    def speaker_name(self) -> str:
+        """Return the name to show for the speaker ("You" or "System" unless mapped)."""
+        if self.speaker:
+            return self.speaker
+        if self.source == "notes":
+            return "Notes"  # typed notes merged into the transcript (interleaved layout)
+        return "You" if self.source == "microphone" else "System"
 
 
 @dataclass
@@ -27,6 +44,101 @@ class CacheDocument:
     title: str
     created_at: str
     updated_at: str
+    calendar_event: Optional[dict] = None  # Raw google_calendar_event data
+    notes_markdown: Optional[str] = None  # AI-generated notes in markdown
+    audio_path: Optional[str] = None  # Local audio recording, if the cache references one
+    tags: list[str] = field(default_factory=list)
+
+    @property
+    def event_start(self) -> Optional[str]:
+        """Return the calendar event start timestamp, if known."""
+        return event_start(self.calendar_event)
+
+    @property
+    def event_end(self) -> Optional[str]:
+        """Return the calendar event end timestamp, if known."""
+        return _event_time(self.calendar_event, "end")
+
+    @property
+    def meeting_url(self) -> Optional[str]:
+        """Return the Zoom/Meet/Teams link of the calendar event, if known."""
+        return conference_url(self.calendar_event)
+
+    @property
+    def event_uid(self) -> Optional[str]:
+        """Return the iCalendar UID of the calendar event, if known."""
+        return event_uid(self.calendar_event)
+
+    @property
+    def attendee_emails(self) -> list[str]:
+        """Return the emails of the people invited to the calendar event."""
+        attendees = (self.calendar_event or {}).get("attendees") or []
+        return [a["email"] for a in attendees if isinstance(a, dict) and a.get("email")]
+
+
+def _event_time(event: Optional[dict], key: str) -> Optional[str]:
+    """Extract a dateTime (or all-day date) from a calendar event start/end block."""
+    if not event:
+        return None
+    value = event.get(key)
+    if isinstance(value, dict):
+        return value.get("dateTime") or value.get("date")
+    if isinstance(value, str):
+        return value
+    return None
+
+
+# Video call links in free text (event location or description)
+_CONFERENCE_URL = re.compile(
+    r"https://(?:[\w-]+\.)?"
+    r"(?:zoom\.us/[jwms]/|meet\.google\.com/|teams\.microsoft\.com/l/meetup-join/"
+    r"|teams\.live\.com/meet/|[\w-]+\.webex\.com/)[^\s<>\"')]+"
+)
+
+
+def conference_url(event: Optional[dict]) -> Optional[str]:
+    """Extract the video call link of a Google Calendar event.
+
+    Uses the event's conference data (Meet, or Zoom/Teams add-ons) if present,
+    and otherwise the first call link in its location or description.
+    """
+    if not event:
+        return None
+    if isinstance(event.get("hangoutLink"), str):
+        return event["hangoutLink"]
+    conference = event.get("conferenceData")
+    if isinstance(conference, dict):
+        for entry in conference.get("entryPoints") or []:
+            if isinstance(entry, dict) and entry.get("entryPointType") == "video":
+                if isinstance(entry.get("uri"), str):
+                    return entry["uri"]
+    for key in ("location", "description"):
+        text = event.get(key)
+        if isinstance(text, str):
+            match = _CONFERENCE_URL.search(text)
+            if match:
+                return match.group()
+    return None
+
+
+def event_uid(event: Optional[dict]) -> Optional[str]:
+    """Return the iCalendar UID of a Google Calendar event (its iCalUID), if known.
+
+    The UID is what the event is identified by in calendar exports (.ics files)
+    and other calendar apps; recurring events share one UID across instances.
+    """
+    if not event:
+        return None
+    uid = event.get("iCalUID")
+    return uid if isinstance(uid, str) and uid else None
+
+
+def event_start(event: Optional[dict]) -> Optional[str]:
+    """Return the start of a Google Calendar event, with its timezone offset, if known.
+
+    All-day events start at a date without a time.
+    """
+    return _event_time(event, "start")
 
 
 @dataclass
@@ -48,6 +160,21 @@ class SharedDocument:
     updated_at: str
     notes_markdown: Optional[str] = None  # AI-generated notes in markdown
     last_viewed_panel: Optional[dict] = None  # Raw last_viewed_panel data
+    owner: Optional[str] = None  # Name (or email) of the teammate who created it
+    people: Optional[dict] = None  # Raw people data (creator, attendees)
+
+
+@dataclass
+class SkippedEntry:
+    """A cache entry that couldn't be parsed and was left out."""
+
+    section: str  # e.g. "documents", "transcripts"
+    key: str  # ID of the entry (empty for a whole section)
+    reason: str
+
+
+class CacheError(Exception):
+    """Raised when a cache file is partially corrupt and strict parsing is on."""
 
 
 @dataclass
@@ -59,6 +186,7 @@ class CacheData:
     folders: dict[str, Folder] = field(default_factory=dict)
     doc_folders: dict[str, list[str]] = field(default_factory=dict)  # doc_id -> [folder_id]
     shared_documents: dict[str, SharedDocument] = field(default_factory=dict)
+    skipped: list[SkippedEntry] = field(default_factory=list)  # entries that failed to parse
 
     def get_folder_names(self, doc_id: str) -> list[str]:
         """Get folder names for a given document ID.
@@ -77,16 +205,33 @@ class CacheData:
                 names.append(folder.title)
         return names
 
+    def get_folder_parents(self) -> dict[str, str]:
+        """Get the parent folder name of each nested folder.
 
-def read_cache(cache_path: Path) -> CacheData:
+        Returns:
+            Dict mapping folder name -> parent folder name (top-level folders omitted).
+        """
+        parents: dict[str, str] = {}
+        for folder in self.folders.values():
+            parent = self.folders.get(folder.parent_id) if folder.parent_id else None
+            if folder.title and parent and parent.title:
+                parents[folder.title] = parent.title
+        return parents
+
+
+def read_cache(cache_path: Path, strict: bool = False) -> CacheData:
     """Read and parse the Granola cache file.
 
     The cache file is double-JSON encoded:
     - Outer JSON: {"cache": "<json-string>"}
     - Inner JSON: Contains state.documents, state.transcripts, etc.
 
+    Entries that aren't shaped as expected (e.g. a document that isn't an object)
+    are left out and listed in the result's skipped, unless strict is set.
+
     Args:
         cache_path: Path to the cache-v3.json file.
+        strict: Raise CacheError instead of skipping entries that fail to parse.
 
     Returns:
         Parsed CacheData object.
@@ -94,6 +239,7 @@ def read_cache(cache_path: Path) -> CacheData:
     Raises:
         FileNotFoundError: If the cache file doesn't exist.
         json.JSONDecodeError: If the JSON is invalid.
+        CacheError: If strict is set and any entry failed to parse.
     """
     content = cache_path.read_text(encoding="utf-8")
 
@@ -105,68 +251,93 @@ def read_cache(cache_path: Path) -> CacheData:
     inner = json.loads(cache_str)
     state = inner.get("state", {})
 
+    skipped: list[SkippedEntry] = []
+
+    def section(name: str) -> dict[str, Any]:
+        value = state.get(name, {})
+        if not isinstance(value, dict):
+            skipped.append(SkippedEntry(name, "", f"expected an object, got {_kind(value)}"))
+            return {}
+        return value
+
+    def skip(name: str, key: str, value: Any, expected: str = "an object") -> None:
+        skipped.append(SkippedEntry(name, key, f"expected {expected}, got {_kind(value)}"))
+
     # Parse documents
     documents: dict[str, CacheDocument] = {}
-    for doc_id, doc_data in state.get("documents", {}).items():
-        if isinstance(doc_data, dict):
-            documents[doc_id] = CacheDocument(
-                id=doc_id,
-                title=doc_data.get("title", ""),
-                created_at=doc_data.get("created_at", ""),
-                updated_at=doc_data.get("updated_at", ""),
-            )
+    for doc_id, doc_data in section("documents").items():
+        if not isinstance(doc_data, dict):
+            skip("documents", doc_id, doc_data)
+            continue
+        calendar_event = doc_data.get("google_calendar_event")
+        documents[doc_id] = CacheDocument(
+            id=doc_id,
+            title=doc_data.get("title", ""),
+            created_at=doc_data.get("created_at", ""),
+            updated_at=doc_data.get("updated_at", ""),
+            calendar_event=calendar_event if isinstance(calendar_event, dict) else None,
+            notes_markdown=doc_data.get("notes_markdown"),
+            audio_path=_audio_path(doc_data),
+            tags=[tag for tag in doc_data.get("tags") or [] if isinstance(tag, str)],
+        )
 
     # Parse transcripts
     transcripts: dict[str, list[TranscriptSegment]] = {}
-    for doc_id, segments_data in state.get("transcripts", {}).items():
-        if isinstance(segments_data, list):
-            segments = []
-            for seg in segments_data:
-                if isinstance(seg, dict):
-                    segments.append(
-                        TranscriptSegment(
-                            id=seg.get("id", ""),
-                            document_id=seg.get("document_id", doc_id),
-                            start_timestamp=seg.get("start_timestamp", ""),
-                            end_timestamp=seg.get("end_timestamp", ""),
-                            text=seg.get("text", ""),
-                            source=seg.get("source", ""),
-                            is_final=seg.get("is_final", False),
-                        )
-                    )
-            transcripts[doc_id] = segments
+    for doc_id, segments_data in section("transcripts").items():
+        if not isinstance(segments_data, list):
+            skip("transcripts", doc_id, segments_data, "a list of segments")
+            continue
+        bad = sum(not isinstance(seg, dict) for seg in segments_data)
+        if bad:
+            skipped.append(
+                SkippedEntry("transcripts", doc_id, f"{bad} segment(s) aren't objects")
+            )
+        transcripts[doc_id] = parse_transcript_segments(segments_data, doc_id)
 
     # Parse folders (documentListsMetadata)
     folders: dict[str, Folder] = {}
-    for folder_id, folder_data in state.get("documentListsMetadata", {}).items():
-        if isinstance(folder_data, dict):
-            folders[folder_id] = Folder(
-                id=folder_id,
-                title=folder_data.get("title", ""),
-                parent_id=folder_data.get("parent_document_list_id"),
-            )
+    for folder_id, folder_data in section("documentListsMetadata").items():
+        if not isinstance(folder_data, dict):
+            skip("documentListsMetadata", folder_id, folder_data)
+            continue
+        folders[folder_id] = Folder(
+            id=folder_id,
+            title=folder_data.get("title", ""),
+            parent_id=folder_data.get("parent_document_list_id"),
+        )
 
     # Build doc -> folders mapping by inverting documentLists (folder_id -> [doc_id])
     doc_folders: dict[str, list[str]] = {}
-    for folder_id, doc_ids in state.get("documentLists", {}).items():
-        if isinstance(doc_ids, list):
-            for doc_id in doc_ids:
-                if doc_id not in doc_folders:
-                    doc_folders[doc_id] = []
-                doc_folders[doc_id].append(folder_id)
+    for folder_id, doc_ids in section("documentLists").items():
+        if not isinstance(doc_ids, list):
+            skip("documentLists", folder_id, doc_ids, "a list of document IDs")
+            continue
+        for doc_id in doc_ids:
+            if doc_id not in doc_folders:
+                doc_folders[doc_id] = []
+            doc_folders[doc_id].append(folder_id)
 
     # Parse shared documents
     shared_documents: dict[str, SharedDocument] = {}
-    for doc_id, doc_data in state.get("sharedDocuments", {}).items():
-        if isinstance(doc_data, dict):
-            shared_documents[doc_id] = SharedDocument(
-                id=doc_id,
-                title=doc_data.get("title", ""),
-                created_at=doc_data.get("created_at", ""),
-                updated_at=doc_data.get("updated_at", ""),
-                notes_markdown=doc_data.get("notes_markdown"),
-                last_viewed_panel=doc_data.get("last_viewed_panel"),
-            )
+    for doc_id, doc_data in section("sharedDocuments").items():
+        if not isinstance(doc_data, dict):
+            skip("sharedDocuments", doc_id, doc_data)
+            continue
+        shared_documents[doc_id] = SharedDocument(
+            id=doc_id,
+            title=doc_data.get("title", ""),
+            created_at=doc_data.get("created_at", ""),
+            updated_at=doc_data.get("updated_at", ""),
+            notes_markdown=doc_data.get("notes_markdown"),
+            last_viewed_panel=doc_data.get("last_viewed_panel"),
+            owner=creator_name(doc_data.get("people")),
+            people=doc_data.get("people"),
+        )
+
+    if strict and skipped:
+        raise CacheError(
+            f"Cache file {cache_path} is partially corrupt: {skipped_summary(skipped)}"
+        )
 
     return CacheData(
         documents=documents,
@@ -174,9 +345,79 @@ def read_cache(cache_path: Path) -> CacheData:
         folders=folders,
         doc_folders=doc_folders,
         shared_documents=shared_documents,
+        skipped=skipped,
     )
 
 
+def skipped_summary(skipped: list[SkippedEntry]) -> str:
+    """Summarize skipped cache entries by section ("2 documents, 1 transcripts")."""
+    counts: dict[str, int] = {}
+    for entry in skipped:
+        counts[entry.section] = counts.get(entry.section, 0) + 1
+    return ", ".join(f"{count} {name}" for name, count in counts.items())
+
+
+def _kind(value: Any) -> str:
+    """Name the JSON type of a value, for parse errors."""
+    if value is None:
+        return "null"
+    if isinstance(value, bool):
+        return "a boolean"
+    if isinstance(value, (int, float)):
+        return "a number"
+    if isinstance(value, str):
+        return "a string"
+    if isinstance(value, list):
+        return "a list"
+    return "an object"
+
+
+def creator_name(people: Any) -> Optional[str]:
+    """Return the name (or email) of a document's creator from its people data."""
+    if not isinstance(people, dict):
+        return None
+    creator = people.get("creator")
+    if not isinstance(creator, dict):
+        return None
+    return creator.get("name") or creator.get("email") or None
+
+
+def _audio_path(doc_data: dict[str, Any]) -> Optional[str]:
+    """Return the local audio recording a cached document references, if any."""
+    for key in AUDIO_KEYS:
+        value = doc_data.get(key)
+        if isinstance(value, str) and value:
+            return value
+    return None
+
+
+def parse_transcript_segments(segments_data: list, doc_id: str) -> list[TranscriptSegment]:
+    """Parse raw transcript segments (as stored in the cache and returned by the API).
+
+    Args:
+        segments_data: List of segment objects; anything that isn't a dict is skipped.
+        doc_id: Document the transcript belongs to (used if a segment lacks one).
+
+    Returns:
+        Parsed segments.
+    """
+    segments = []
+    for seg in segments_data:
+        if isinstance(seg, dict):
+            segments.append(
+                TranscriptSegment(
+                    id=seg.get("id", ""),
+                    document_id=seg.get("document_id", doc_id),
+                    start_timestamp=seg.get("start_timestamp", ""),
+                    end_timestamp=seg.get("end_timestamp", ""),
+                    text=seg.get("text", ""),
+                    source=seg.get("source", ""),
+                    is_final=seg.get("is_final", False),
+                )
+            )
+    return segments
+
+
 def get_default_cache_path() -> Path:
     """Return the default cache file path for macOS.
 
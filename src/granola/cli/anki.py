@@ -0,0 +1,142 @@
+"""Anki command: turn decisions and action items into flashcards."""
+
+import html
+from datetime import datetime
+from typing import Annotated, Optional
+
+import typer
+from rich.console import Console
+
+from granola.api.models import Document
+from granola.cli.book import period_option
+from granola.cli.completion import complete_folders
+from granola.cli.documents import fetch_documents_with_folders
+from granola.cli.exit_codes import ExitCode
+from granola.formatters.anki import Card, card_guid, format_anki_tsv, format_apkg
+from granola.source import get_notes_content, is_filtered_out
+from granola.utils.dates import parse_timestamp
+from granola.utils.filename import slugify
+from granola.utils.outcomes import extract_outcomes
+
+console = Console()
+
+# Output file extensions for a deck package and an import text file
+APKG_EXTENSION = ".apkg"
+TSV_EXTENSIONS = (".tsv", ".txt")
+
+
+def anki_cmd(
+    output: Annotated[
+        str,
+        typer.Option("--output", help="Path of the .apkg deck (or .tsv/.txt file) to write"),
+    ],
+    since: Annotated[
+        Optional[str],
+        typer.Option("--since", help="Only meetings from this year, month or day on (2024-01)"),
+    ] = None,
+    until: Annotated[
+        Optional[str],
+        typer.Option("--until", help="Only meetings up to the end of this year, month or day"),
+    ] = None,
+    folder: Annotated[
+        Optional[list[str]],
+        typer.Option(
+            "--folder",
+            help="Only meetings in this folder (can be used multiple times)",
+            autocompletion=complete_folders,
+        ),
+    ] = None,
+    deck: Annotated[
+        str,
+        typer.Option("--deck", help="Name of the Anki deck ('::' nests decks)"),
+    ] = "Granola",
+    timeout: Annotated[
+        int,
+        typer.Option("--timeout", help="HTTP timeout in seconds"),
+    ] = 120,
+) -> None:
+    """Turn the decisions and action items in meeting notes into Anki flashcards.
+
+    Each meeting with decisions gets a card asking what was decided in it ("What
+    did we decide about Q3 planning on 2024-06-12?"), and each meeting with action
+    items one asking for those. Decisions and action items are list items under a
+    heading like "Decisions" or "Next steps", lines starting with "Decision:" or
+    "TODO:", and task checkboxes.
+
+    A .apkg output is a deck package (File > Import in Anki); .tsv or .txt writes a
+    text file to import instead. Cards are tagged with their Granola folders, and
+    importing a newer export updates the cards already imported.
+    """
+    from granola.cli.main import state, resolve_path
+
+    start = period_option("--since", since)[0] if since else None
+    end = period_option("--until", until)[1] if until else None
+
+    output_path = resolve_path(output)
+    if output_path is None:
+        console.print("[red]Error:[/red] --output must be a file path")
+        raise typer.Exit(ExitCode.ERROR)
+    extension = output_path.suffix.lower()
+    if extension != APKG_EXTENSION and extension not in TSV_EXTENSIONS:
+        console.print(
+            "[red]Error:[/red] --output must end in .apkg (a deck package) "
+            "or .tsv/.txt (a text file to import)"
+        )
+        raise typer.Exit(ExitCode.ERROR)
+
+    documents, doc_folders, folder_parents = fetch_documents_with_folders(timeout)
+    wanted_folders = set(folder or [])
+
+    cards: list[Card] = []
+    meetings = 0
+    for doc in sorted(documents, key=lambda d: d.created_at):
+        created = parse_timestamp(doc.created_at)
+        if created is None or (start and created < start) or (end and created >= end):
+            continue
+        folders = doc_folders.get(doc.id, [])
+        if is_filtered_out(folders, set(), wanted_folders, folder_parents):
+            continue
+        doc_cards = _cards(doc, created, folders)
+        if doc_cards:
+            meetings += 1
+            cards.extend(doc_cards)
+
+    if not cards:
+        console.print(
+            "[yellow]Warning:[/yellow] No decisions or action items found; nothing written"
+        )
+        return
+
+    try:
+        output_path.parent.mkdir(parents=True, exist_ok=True)
+        if extension == APKG_EXTENSION:
+            output_path.write_bytes(format_apkg(deck, cards))
+        else:
+            output_path.write_text(format_anki_tsv(cards))
+    except OSError as e:
+        console.print(f"[red]Error:[/red] Failed to write {output_path}: {e}")
+        raise typer.Exit(ExitCode.ERROR)
+
+    console.print(
+        f"[green]✓[/green] Wrote {len(cards)} cards from {meetings} meetings to {output_path}"
+    )
+    state.logger.info(f"Anki export completed, {len(cards)} cards written to {output_path}")
+
+
+def _cards(doc: Document, created: datetime, folders: list[str]) -> list[Card]:
+    """Return the cards for a meeting's decisions and action items."""
+    outcomes = extract_outcomes(get_notes_content(doc))
+    title = html.escape(doc.title or "Untitled")
+    date = created.strftime("%Y-%m-%d")
+    tags = ["granola", *(slugify(f, fallback="folder") for f in folders)]
+
+    actions = outcomes.actions + outcomes.done
+    cards = []
+    for kind, items, question in (
+        ("decisions", outcomes.decisions, f"What did we decide about <b>{title}</b> on {date}?"),
+        ("actions", actions, f"What were the action items from <b>{title}</b> on {date}?"),
+    ):
+        if items:
+            back = "<ul>" + "".join(f"<li>{html.escape(item)}</li>" for item in items) + "</ul>"
+            cards.append(Card(card_guid(doc.id, kind), question, back, tags))
+    return cards
@@ -0,0 +1,121 @@
+"""API debugging commands."""
+
+import json
+from datetime import datetime
+from pathlib import Path
+from typing import Annotated, Any, Optional
+
+import typer
+from pydantic import BaseModel
+from rich.console import Console
+
+from granola.api.auth import AuthError, get_access_token
+from granola.api.client import ENDPOINTS, APIError, GranolaClient
+from granola.api.models import DocumentListsResponse, GranolaResponse
+from granola.api.redact import redact
+from granola.cli.exit_codes import ExitCode, api_error_exit_code
+
+console = Console()
+
+api_app = typer.Typer(help="Inspect Granola API responses (for debugging).", no_args_is_help=True)
+
+# Models each endpoint's responses are parsed with (as the export commands do)
+RESPONSE_MODELS: dict[str, type[BaseModel]] = {
+    "get-documents": GranolaResponse,
+    "get-document-lists": DocumentListsResponse,
+}
+
+
+def dump_cmd(
+    endpoint: Annotated[
+        str,
+        typer.Option("--endpoint", help=f"Endpoint to fetch: {', '.join(ENDPOINTS)}"),
+    ] = "get-documents",
+    raw: Annotated[
+        bool,
+        typer.Option("--raw", help="Write responses as returned, without parsing them"),
+    ] = False,
+    output: Annotated[
+        Optional[str],
+        typer.Option("--output", help="File to write (default: granola-<endpoint>-<time>.json)"),
+    ] = None,
+    timeout: Annotated[
+        int,
+        typer.Option("--timeout", help="HTTP timeout in seconds"),
+    ] = 120,
+) -> None:
+    """Write an endpoint's JSON responses to a file, with credentials redacted.
+
+    With --raw, the responses are written exactly as the API returned them (all
+    pages). Attach this file when reporting a parsing error. Without --raw, the
+    responses are parsed the way the export commands parse them and written back
+    out, which shows what granola understood (and fails with the parsing error).
+    """
+    from granola.cli.main import state, resolve_path
+
+    if endpoint not in ENDPOINTS:
+        console.print(
+            f"[red]Error:[/red] Unknown endpoint '{endpoint}'. "
+            f"Choose one of: {', '.join(ENDPOINTS)}"
+        )
+        raise typer.Exit(1)
+
+    supabase_path = state.supabase
+    if not supabase_path:
+        console.print(
+            "[red]Error:[/red] supabase.json path not set. "
+            "Use --supabase flag, SUPABASE_FILE env, or config file."
+        )
+        raise typer.Exit(ExitCode.AUTH)
+
+    try:
+        access_token = get_access_token(supabase_path)
+    except (AuthError, FileNotFoundError) as e:
+        console.print(f"[red]Error:[/red] Failed to read supabase.json: {e}")
+        raise typer.Exit(ExitCode.AUTH)
+
+    console.print(f"Fetching {endpoint}...")
+    try:
+        client = GranolaClient(access_token, timeout=timeout, transport=state.http_transport)
+        bodies = client.get_raw_responses(endpoint)
+    except APIError as e:
+        console.print(f"[red]Error:[/red] API request failed: {e}")
+        raise typer.Exit(api_error_exit_code(e))
+    state.logger.info(f"Received {len(bodies)} responses from {endpoint}")
+
+    responses: list[Any] = bodies
+    if endpoint not in RESPONSE_MODELS:
+        # Nothing to parse with; the raw responses are all there is
+        raw = True
+    if not raw:
+        model = RESPONSE_MODELS[endpoint]
+        try:
+            responses = [model.model_validate(body).model_dump(mode="json") for body in bodies]
+        except Exception as e:
+            console.print(f"[red]Error:[/red] Failed to parse {endpoint} response: {e}")
+            console.print("Run again with --raw to capture the unparsed responses.")
+            raise typer.Exit(1)
+
+    output_path = resolve_path(output) if output else None
+    if output_path is None:
+        stamp = datetime.now().strftime("%Y%m%d-%H%M%S")
+        output_path = Path.cwd() / f"granola-{endpoint}-{stamp}.json"
+
+    dump = {
+        "endpoint": endpoint,
+        "raw": raw,
+        "captured_at": datetime.now().astimezone().isoformat(timespec="seconds"),
+        "responses": redact(responses),
+    }
+    try:
+        output_path.parent.mkdir(parents=True, exist_ok=True)
+        output_path.write_text(json.dumps(dump, indent=2, ensure_ascii=False), encoding="utf-8")
+    except OSError as e:
+        console.print(f"[red]Error:[/red] Failed to write {output_path}: {e}")
+        raise typer.Exit(1)
+
+    console.print(f"[green]✓[/green] Wrote {endpoint} ({len(responses)} pages) to {output_path}")
+    console.print(
+        "Credentials are redacted, but the file contains your meeting notes; "
+        "review it before sharing."
+    )
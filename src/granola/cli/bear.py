@@ -0,0 +1,115 @@
+"""Bear command: push notes into the Bear notes app (macOS)."""
+
+import sys
+import time
+from pathlib import Path
+from typing import Annotated, Optional
+
+import typer
+from rich.console import Console
+
+from granola.api.models import Document
+from granola.bear import BearClient, BearError
+from granola.bear.client import DEFAULT_DATABASE, bear_tag, doc_tag
+from granola.cli.completion import complete_folders
+from granola.cli.documents import fetch_documents_with_folders
+from granola.cli.exit_codes import ExitCode
+from granola.source import get_notes_content, is_filtered_out
+
+console = Console()
+
+
+def bear_cmd(
+    folder: Annotated[
+        Optional[list[str]],
+        typer.Option(
+            "--folder",
+            help="Only meetings in this folder (can be used multiple times)",
+            autocompletion=complete_folders,
+        ),
+    ] = None,
+    database: Annotated[
+        Optional[str],
+        typer.Option("--database", help="Path of Bear's database.sqlite (default: Bear 2's)"),
+    ] = None,
+    delay: Annotated[
+        float,
+        typer.Option("--delay", help="Seconds to wait between notes, so Bear keeps up"),
+    ] = 0.5,
+    timeout: Annotated[
+        int,
+        typer.Option("--timeout", help="HTTP timeout in seconds"),
+    ] = 120,
+) -> None:
+    """Push Granola notes into Bear, one note per meeting (macOS only).
+
+    Notes are written through Bear's x-callback-url API in the background. Each
+    note is tagged with its Granola folders and #granola/<document ID>; re-running
+    the command finds notes by that tag in Bear's database and replaces the ones
+    whose meeting changed instead of creating duplicates. Meetings without notes
+    are skipped.
+    """
+    from granola.cli.main import state, resolve_path
+
+    if sys.platform != "darwin":
+        console.print("[red]Error:[/red] granola bear requires macOS (Bear is a Mac app)")
+        raise typer.Exit(ExitCode.ERROR)
+
+    database_path: Optional[Path] = resolve_path(database) if database else DEFAULT_DATABASE
+    if database_path is None:
+        console.print("[red]Error:[/red] --database must be a file path")
+        raise typer.Exit(ExitCode.ERROR)
+
+    documents, doc_folders, folder_parents = fetch_documents_with_folders(timeout)
+    wanted_folders = set(folder or [])
+
+    bear = BearClient(database_path, logger=state.logger)
+    try:
+        existing = bear.find_doc_notes()
+    except BearError as e:
+        console.print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(ExitCode.ERROR)
+
+    created = updated = skipped = 0
+
+    console.print(f"Pushing {len(documents)} documents to Bear...")
+    try:
+        for doc in documents:
+            folders = doc_folders.get(doc.id, [])
+            if is_filtered_out(folders, set(), wanted_folders, folder_parents):
+                continue
+            notes = get_notes_content(doc)
+            if not notes or not notes.strip():
+                state.logger.debug(f"Skipping document '{doc.title}' - no notes")
+                continue
+
+            text = _note_text(doc, folders, notes)
+            note = existing.get(doc.id)
+            if note is None:
+                bear.create_note(text)
+                created += 1
+            elif note.text.strip() == text.strip():
+                skipped += 1
+                continue
+            else:
+                bear.replace_note(note.id, text)
+                updated += 1
+            # Bear handles URLs one at a time; a burst of them can be dropped
+            time.sleep(delay)
+    except BearError as e:
+        console.print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(ExitCode.ERROR)
+
+    console.print(
+        f"[green]✓[/green] Bear push completed: "
+        f"{created} created, {updated} updated, {skipped} unchanged"
+    )
+    state.logger.info(
+        f"Bear push completed: created={created}, updated={updated}, skipped={skipped}"
+    )
+
+
+def _note_text(doc: Document, folders: list[str], notes: str) -> str:
+    """Build a note's Markdown: title, notes, then its tags."""
+    tags = " ".join(tag for tag in [doc_tag(doc.id), *map(bear_tag, folders)] if tag)
+    return f"# {doc.title or 'Untitled'}\n\n{notes.strip()}\n\n{tags}\n"
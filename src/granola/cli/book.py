@@ -0,0 +1,134 @@
+"""Book command: compile meeting notes into an EPUB for reading on an e-reader."""
+
+from datetime import datetime
+from typing import Annotated, Optional
+
+import typer
+from rich.console import Console
+
+from granola.cli.completion import complete_folders
+from granola.cli.documents import fetch_documents_with_folders
+from granola.formatters.epub import Chapter, format_epub
+from granola.source import get_notes_html, is_filtered_out
+from granola.utils.dates import parse_period, parse_timestamp
+
+console = Console()
+
+UNCATEGORIZED_FOLDER = "Uncategorized"
+
+
+def book_cmd(
+    output: Annotated[
+        str,
+        typer.Option("--output", help="Path of the .epub file to write"),
+    ],
+    since: Annotated[
+        Optional[str],
+        typer.Option("--since", help="Only meetings from this year, month or day on (2024-01)"),
+    ] = None,
+    until: Annotated[
+        Optional[str],
+        typer.Option("--until", help="Only meetings up to the end of this year, month or day"),
+    ] = None,
+    folder: Annotated[
+        Optional[list[str]],
+        typer.Option(
+            "--folder",
+            help="Only meetings in this folder (can be used multiple times)",
+            autocompletion=complete_folders,
+        ),
+    ] = None,
+    title: Annotated[
+        Optional[str],
+        typer.Option("--title", help="Book title (default: Granola meetings and the dates)"),
+    ] = None,
+    timeout: Annotated[
+        int,
+        typer.Option("--timeout", help="HTTP timeout in seconds"),
+    ] = 120,
+) -> None:
+    """Compile meeting notes into an EPUB book, one chapter per meeting.
+
+    Chapters are grouped into a part per Granola folder (a meeting in several
+    folders appears in the first), oldest meeting first. Meetings without notes
+    are left out.
+
+    --since and --until take a year (2024), month (2024-01) or day (2024-01-15);
+    both are inclusive, so --since 2024-01 --until 2024-03 is the first quarter.
+    """
+    from granola.cli.main import state, resolve_path
+
+    start = period_option("--since", since)[0] if since else None
+    end = period_option("--until", until)[1] if until else None
+
+    output_path = resolve_path(output)
+    if output_path is None:
+        console.print("[red]Error:[/red] --output must be a file path")
+        raise typer.Exit(1)
+
+    documents, doc_folders, folder_parents = fetch_documents_with_folders(timeout)
+    wanted_folders = set(folder or [])
+
+    chapters: list[Chapter] = []
+    for doc in documents:
+        created = parse_timestamp(doc.created_at)
+        if created is None or (start and created < start) or (end and created >= end):
+            continue
+        folders = doc_folders.get(doc.id) or [UNCATEGORIZED_FOLDER]
+        if wanted_folders:
+            folders = [
+                f
+                for f in folders
+                if not is_filtered_out([f], set(), wanted_folders, folder_parents)
+            ]
+            if not folders:
+                continue
+        notes_html = get_notes_html(doc)
+        if not notes_html.strip():
+            state.logger.debug(f"Skipping document '{doc.title}' - no notes")
+            continue
+        chapters.append(
+            Chapter(
+                title=doc.title or "Untitled",
+                created_at=created,
+                folder=folders[0],
+                body=notes_html,
+            )
+        )
+
+    if not chapters:
+        console.print("[yellow]Warning:[/yellow] No meetings with notes match; nothing written")
+        return
+
+    book_title = title or _default_title(since, until)
+    try:
+        output_path.parent.mkdir(parents=True, exist_ok=True)
+        output_path.write_bytes(format_epub(book_title, chapters))
+    except OSError as e:
+        console.print(f"[red]Error:[/red] Failed to write {output_path}: {e}")
+        raise typer.Exit(1)
+
+    console.print(f"[green]✓[/green] Wrote {len(chapters)} meetings to {output_path}")
+    state.logger.info(f"Book completed, {len(chapters)} chapters written to {output_path}")
+
+
+def period_option(name: str, value: str) -> tuple[datetime, datetime]:
+    """Parse a --since or --until period, exiting if it is invalid."""
+    period = parse_period(value)
+    if period is None:
+        console.print(
+            f"[red]Error:[/red] Invalid {name} '{value}', expected YYYY, YYYY-MM or YYYY-MM-DD"
+        )
+        raise typer.Exit(1)
+    return period
+
+
+def _default_title(since: Optional[str], until: Optional[str]) -> str:
+    """Return the default book title, naming the period it covers."""
+    if since and until:
+        return f"Granola meetings, {since} to {until}"
+    if since:
+        return f"Granola meetings since {since}"
+    if until:
+        return f"Granola meetings until {until}"
+    return "Granola meetings"
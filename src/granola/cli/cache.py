@@ -0,0 +1,95 @@
+"""Commands for the local Granola cache file."""
+
+from datetime import datetime
+from pathlib import Path
+from typing import Annotated, Optional
+
+import typer
+from rich.console import Console
+
+from granola.cache.backup import backup_file, backup_redacted, prune_backups
+from granola.cache.reader import get_default_cache_path
+from granola.cli.exit_codes import ExitCode
+
+console = Console()
+
+cache_app = typer.Typer(help="Manage the local Granola cache file.", no_args_is_help=True)
+
+
+def backup_cmd(
+    output: Annotated[
+        str,
+        typer.Option("--output", help="Directory to write backups to"),
+    ],
+    cache: Annotated[
+        Optional[str],
+        typer.Option("--cache", help="Path to Granola cache file"),
+    ] = None,
+    include_supabase: Annotated[
+        bool,
+        typer.Option("--include-supabase", help="Also back up supabase.json"),
+    ] = False,
+    redact_tokens: Annotated[
+        bool,
+        typer.Option("--redact-tokens", help="Redact credentials in the supabase.json backup"),
+    ] = False,
+    keep: Annotated[
+        Optional[int],
+        typer.Option("--keep", min=1, help="Keep only this many newest backups of each file"),
+    ] = None,
+) -> None:
+    """Copy the cache file to a timestamped backup (cache-v3-20240612-153000.json).
+
+    Granola prunes old transcripts from its cache, and the cache is the only
+    local copy of them; run this from a scheduled job to keep them.
+
+    Use --include-supabase to back up supabase.json too (it holds your login
+    tokens; add --redact-tokens to leave them out of the copy). Use --keep N to
+    delete all but the N newest backups of each file.
+    """
+    from granola.cli.main import state, resolve_path
+
+    output_dir = resolve_path(output)
+    if output_dir is None:
+        console.print("[red]Error:[/red] --output must be a directory path")
+        raise typer.Exit(ExitCode.ERROR)
+    cache_path = resolve_path(cache) or get_default_cache_path()
+    if not cache_path.is_file():
+        console.print(f"[red]Error:[/red] Cache file not found: {cache_path}")
+        raise typer.Exit(ExitCode.CACHE)
+
+    sources = [cache_path]
+    supabase_path = state.supabase
+    if include_supabase:
+        if supabase_path is None:
+            # Granola's default location, as export uses
+            supabase_path = (
+                Path.home() / "Library" / "Application Support" / "Granola" / "supabase.json"
+            )
+        if not supabase_path.is_file():
+            console.print(f"[red]Error:[/red] supabase.json not found: {supabase_path}")
+            raise typer.Exit(ExitCode.AUTH)
+        sources.append(supabase_path)
+
+    taken_at = datetime.now()
+    for source in sources:
+        try:
+            if source == supabase_path and redact_tokens:
+                target = backup_redacted(source, output_dir, taken_at)
+            else:
+                target = backup_file(source, output_dir, taken_at)
+        except (OSError, ValueError) as e:
+            console.print(f"[red]Error:[/red] Failed to back up {source}: {e}")
+            raise typer.Exit(ExitCode.ERROR)
+        console.print(f"[green]✓[/green] Backed up {source.name} to {target}")
+
+        if keep is not None:
+            try:
+                removed = prune_backups(source, output_dir, keep)
+            except OSError as e:
+                console.print(f"[yellow]Warning:[/yellow] Failed to remove old backups: {e}")
+                continue
+            for path in removed:
+                state.logger.info(f"Removed old backup {path}")
+            if removed:
+                console.print(f"Removed {len(removed)} old backup(s) of {source.name}")
@@ -0,0 +1,132 @@
+"""Calendar (iCal) export command."""
+
+from datetime import datetime, timedelta, timezone
+from pathlib import Path
+from typing import Annotated, Optional
+
+import typer
+from rich.console import Console
+
+from granola.cache.reader import CacheData, CacheDocument, get_default_cache_path, read_cache
+from granola.cli.exit_codes import ExitCode
+from granola.formatters.ical import CalendarEvent, format_ical
+from granola.utils.dates import parse_timestamp
+from granola.writers.sync_writer import ExportDoc, SyncWriter
+
+console = Console()
+
+# Duration used when neither calendar data nor a transcript is available
+DEFAULT_DURATION = timedelta(minutes=30)
+
+
+def calendar_cmd(
+    cache: Annotated[
+        Optional[str],
+        typer.Option("--cache", help="Path to Granola cache file"),
+    ] = None,
+    output: Annotated[
+        Optional[str],
+        typer.Option("--output", help="Path of the .ics file to write"),
+    ] = None,
+    export_dir: Annotated[
+        Optional[str],
+        typer.Option(
+            "--export-dir",
+            help="Directory of a combined export; events will link to the exported notes",
+        ),
+    ] = None,
+) -> None:
+    """Export meetings as calendar events to an iCalendar (.ics) file.
+
+    Meeting times come from the cached calendar event when available, otherwise
+    from the transcript timestamps, otherwise from the document creation time.
+    """
+    from granola.cli.main import state, resolve_path
+
+    cache_path = resolve_path(cache) if cache else get_default_cache_path()
+    if not cache_path.exists():
+        console.print(f"[red]Error:[/red] Cache file not found at {cache_path}")
+        raise typer.Exit(ExitCode.CACHE)
+
+    console.print("Reading Granola cache file...")
+    state.logger.info(f"Reading Granola cache file from {cache_path}")
+
+    try:
+        cache_data = read_cache(cache_path)
+    except Exception as e:
+        console.print(f"[red]Error:[/red] Failed to read cache file: {e}")
+        raise typer.Exit(ExitCode.CACHE)
+
+    notes_dir = resolve_path(export_dir) if export_dir else None
+    sync_writer = SyncWriter(notes_dir, logger=state.logger) if notes_dir else None
+
+    events: list[CalendarEvent] = []
+    for doc in cache_data.documents.values():
+        event = _build_event(doc, cache_data, sync_writer)
+        if event:
+            events.append(event)
+
+    output_path = resolve_path(output) if output else Path("./meetings.ics")
+    output_path.parent.mkdir(parents=True, exist_ok=True)
+
+    try:
+        output_path.write_text(format_ical(events), encoding="utf-8")
+    except OSError as e:
+        console.print(f"[red]Error:[/red] Failed to write {output_path}: {e}")
+        raise typer.Exit(1)
+
+    console.print(f"[green]✓[/green] Wrote {len(events)} events to {output_path}")
+    state.logger.info(f"Calendar export completed, {len(events)} events written")
+
+
+def _build_event(
+    doc: CacheDocument,
+    cache_data: CacheData,
+    sync_writer: Optional[SyncWriter],
+) -> Optional[CalendarEvent]:
+    """Build a calendar event for a cached document, or None if it has no usable time."""
+    segments = cache_data.transcripts.get(doc.id, [])
+
+    start = parse_timestamp(doc.event_start)
+    end = parse_timestamp(doc.event_end)
+
+    if start is None and segments:
+        start = parse_timestamp(segments[0].start_timestamp)
+        end = parse_timestamp(segments[-1].end_timestamp)
+
+    if start is None:
+        start = parse_timestamp(doc.created_at)
+        end = None
+
+    if start is None:
+        return None
+
+    if end is None or end <= start:
+        end = start + DEFAULT_DURATION
+
+    description = f"Granola document: {doc.id}"
+    url = ""
+    if sync_writer:
+        created_at = parse_timestamp(doc.created_at) or datetime.now(timezone.utc)
+        export_doc = ExportDoc(
+            id=doc.id,
+            title=doc.title,
+            created_at=created_at,
+            updated_at=created_at,
+            content="",
+            folders=cache_data.get_folder_names(doc.id),
+            started_at=parse_timestamp(doc.event_start),
+        )
+        paths = sync_writer.get_target_paths(export_doc)
+        if paths:
+            description = f"Notes: {paths[0]}"
+            url = paths[0].as_uri()
+
+    return CalendarEvent(
+        uid=f"{doc.id}@granola",
+        title=doc.title,
+        start=start,
+        end=end,
+        description=description,
+        url=url,
+    )
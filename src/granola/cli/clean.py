@@ -0,0 +1,164 @@
+"""Clean command: delete exported files of documents deleted in Granola."""
+
+import os
+from pathlib import Path
+from typing import Annotated, Optional
+
+import typer
+from rich.console import Console
+
+from granola.api.auth import AuthError, get_access_token
+from granola.api.client import APIError, GranolaClient
+from granola.cache.reader import get_default_cache_path, read_cache
+from granola.cli.exit_codes import ExitCode, api_error_exit_code
+from granola.utils.lock import ExportLock, LockError
+from granola.writers.manifest import MANIFEST_FILENAME, Manifest
+from granola.writers.sync_writer import ENCODED_SUFFIXES, SyncWriter
+
+console = Console()
+
+
+def clean_cmd(
+    output: Annotated[
+        Optional[str],
+        typer.Option("--output", help="Export output directory to clean"),
+    ] = None,
+    dry_run: Annotated[
+        bool,
+        typer.Option(
+            "--dry-run", help="List the files and folders that would be deleted, delete nothing"
+        ),
+    ] = False,
+    yes: Annotated[
+        bool,
+        typer.Option("--yes", "-y", help="Delete without asking for confirmation"),
+    ] = False,
+    cache: Annotated[
+        Optional[str],
+        typer.Option("--cache", help="Path to Granola cache file (for shared documents)"),
+    ] = None,
+    timeout: Annotated[
+        int,
+        typer.Option("--timeout", help="HTTP timeout in seconds"),
+    ] = 120,
+) -> None:
+    """Delete exported files of documents deleted in Granola, and empty folders.
+
+    Runs only the pruning of an export: nothing is fetched or written beyond the
+    list of documents. Use it with export --no-prune to keep regular exports
+    additive and prune deliberately, after checking what would go with --dry-run.
+
+    As with export, only files recorded in the output's manifest are ever
+    deleted. Documents shared with you count as existing if they are in the
+    local Granola cache. Folders mapped outside the output directory
+    (--folder-path) aren't cleaned.
+    """
+    from granola.cli.export import export_output_dir
+    from granola.cli.main import state, resolve_path
+
+    output_dir = export_output_dir(output)
+    if not (output_dir / MANIFEST_FILENAME).is_file():
+        console.print(
+            f"[red]Error:[/red] No manifest in {output_dir}; is it a granola export folder?"
+        )
+        raise typer.Exit(ExitCode.ERROR)
+
+    supabase_path = state.supabase
+    if not supabase_path:
+        console.print(
+            "[red]Error:[/red] supabase.json path not set. "
+            "Use --supabase flag, SUPABASE_FILE env, or config file."
+        )
+        raise typer.Exit(ExitCode.AUTH)
+
+    try:
+        access_token = get_access_token(supabase_path)
+    except (AuthError, FileNotFoundError) as e:
+        console.print(f"[red]Error:[/red] Failed to read supabase.json: {e}")
+        raise typer.Exit(ExitCode.AUTH)
+
+    console.print("Fetching documents from Granola API...")
+    try:
+        client = GranolaClient(access_token, timeout=timeout, transport=state.http_transport)
+        doc_ids = {doc.id for doc in client.get_documents()}
+    except APIError as e:
+        console.print(f"[red]Error:[/red] API request failed: {e}")
+        raise typer.Exit(api_error_exit_code(e))
+
+    cache_path = resolve_path(cache) if cache else get_default_cache_path()
+    try:
+        doc_ids.update(read_cache(cache_path).shared_documents)
+    except Exception as e:
+        state.logger.warning(f"Failed to read cache file (continuing without shared documents): {e}")
+
+    # One writer per kind of file exported (e.g. .md, and .txt from an earlier format)
+    writers = [
+        SyncWriter(output_dir, logger=state.logger, extension=extension)
+        for extension in _exported_extensions(Manifest.load(output_dir))
+    ]
+    orphans = [path for writer in writers for path in writer.prune(doc_ids, dry_run=True)[0]]
+    # Folders are the same for every writer; count the orphans of all of them as gone
+    folders = SyncWriter(output_dir, logger=state.logger).empty_folders(orphans)
+    if not orphans and not folders:
+        console.print(f"[green]✓[/green] Nothing to clean in {output_dir}")
+        return
+
+    for path in orphans:
+        console.print(f"  {os.path.relpath(path, output_dir)}", markup=False)
+    for path in folders:
+        console.print(f"  {os.path.relpath(path, output_dir)}{os.sep}", markup=False)
+    what = _describe(len(orphans), len(folders))
+    if dry_run:
+        console.print(f"Would delete {what} (dry run)")
+        return
+    if not yes and not typer.confirm(f"Delete {what}?"):
+        console.print("Nothing deleted.")
+        return
+
+    # Hold the output folder lock, so an export can't write while files are deleted
+    lock = ExportLock(output_dir)
+    try:
+        lock.acquire()
+    except LockError as e:
+        console.print(f"[red]Error:[/red] {e}. Try again when it has finished.")
+        raise typer.Exit(ExitCode.LOCKED)
+    try:
+        deleted = 0
+        removed = 0
+        # The last writer removes folders emptied by the others' deletions
+        for writer in writers or [SyncWriter(output_dir, logger=state.logger)]:
+            files, empty = writer.prune(doc_ids)
+            deleted += len(files)
+            removed += len(empty)
+    finally:
+        lock.release()
+
+    console.print(f"[green]✓[/green] Deleted {_describe(deleted, removed)}")
+    state.logger.info(
+        f"Cleaned {output_dir}: {deleted} orphaned files and {removed} empty folders deleted"
+    )
+
+
+def _describe(files: int, folders: int) -> str:
+    """Describe what clean deletes, e.g. "3 files of deleted documents and 1 empty folder"."""
+    parts = []
+    if files or not folders:
+        parts.append(f"{files} files of deleted documents")
+    if folders:
+        parts.append(f"{folders} empty folder{'s' if folders != 1 else ''}")
+    return " and ".join(parts)
+
+
+def _exported_extensions(manifest: Manifest) -> list[str]:
+    """Return the extensions of the files in a manifest, without encoding suffixes."""
+    extensions: set[str] = set()
+    for path in manifest.paths():
+        name = path.name
+        for suffix in ENCODED_SUFFIXES:
+            if name.endswith(suffix):
+                name = name[: -len(suffix)]
+                break
+        extension = Path(name).suffix
+        if extension:
+            extensions.add(extension)
+    return sorted(extensions)
@@ -0,0 +1,122 @@
+"""Shell completion of option values (folders, tags, documents) from the local Granola cache.
+
+The cache can be hundreds of megabytes, too slow to parse on every Tab press, so
+the values are kept in a small index next to the config file and only rebuilt
+when the cache file changes.
+"""
+
+import json
+import os
+from datetime import datetime, timezone
+from typing import Any, Iterable
+
+import typer
+
+from granola.cache.reader import get_default_cache_path, read_cache
+from granola.config.defaults import config_dir
+from granola.utils.dates import parse_timestamp
+
+# Documents offered when completing a document ID, most recent first
+RECENT_DOCUMENTS = 50
+
+# Index of completion values, in the config directory
+COMPLETION_INDEX = "completion.json"
+
+
+def complete_folders(ctx: typer.Context, incomplete: str) -> list[str]:
+    """Complete a folder name (--folder, --exclude-folder, --include-folder)."""
+    index = _read_index(ctx)
+    if index is None:
+        return []
+    return _matching(index["folders"], incomplete)
+
+
+def complete_tags(ctx: typer.Context, incomplete: str) -> list[str]:
+    """Complete a tag used on any meeting (--add-tag)."""
+    index = _read_index(ctx)
+    if index is None:
+        return []
+    return _matching(index["tags"], incomplete)
+
+
+def complete_document_ids(ctx: typer.Context, incomplete: str) -> list[tuple[str, str]]:
+    """Complete a document ID (--id) with recent meetings, described by date and title.
+
+    Meetings match by the start of their ID or by any part of their title; shells
+    that show descriptions (zsh, fish) list the title next to each ID.
+    """
+    index = _read_index(ctx)
+    if index is None:
+        return []
+    needle = incomplete.casefold()
+    epoch = datetime.min.replace(tzinfo=timezone.utc)
+    candidates = []
+    for doc_id, title, started in index["documents"]:
+        if not (doc_id.startswith(incomplete) or needle in title.casefold()):
+            continue
+        start = datetime.fromisoformat(started) if started else None
+        candidates.append((start or epoch, doc_id, title or "Untitled"))
+    candidates.sort(key=lambda candidate: candidate[0], reverse=True)
+    return [
+        (doc_id, f"{start.astimezone():%Y-%m-%d} {title}" if start != epoch else title)
+        for start, doc_id, title in candidates[:RECENT_DOCUMENTS]
+    ]
+
+
+def _read_index(ctx: typer.Context) -> dict[str, Any] | None:
+    """Return the completion values of the cache given with --cache so far, or the default one.
+
+    The index is read if it was built from the cache file as it is now (same path,
+    modification time and size); otherwise the cache is read and the index rebuilt.
+    Completion must never fail, so any error reading the cache gives None.
+    """
+    from granola.cli.main import resolve_path
+
+    try:
+        cache_path = resolve_path(ctx.params.get("cache")) or get_default_cache_path()
+        stat = cache_path.stat()
+    except Exception:
+        return None
+    key = {"cache": str(cache_path), "mtime": stat.st_mtime_ns, "size": stat.st_size}
+
+    index_path = config_dir() / COMPLETION_INDEX
+    try:
+        index = json.loads(index_path.read_text())
+        if index.get("key") == key:
+            return index
+    except (OSError, ValueError):
+        pass
+
+    try:
+        cache = read_cache(cache_path)
+    except Exception:
+        return None
+    documents = []
+    for doc in cache.documents.values():
+        start = parse_timestamp(doc.event_start) or parse_timestamp(doc.created_at)
+        documents.append([doc.id, doc.title or "", start.isoformat() if start else None])
+    index = {
+        "key": key,
+        "folders": sorted({folder.title for folder in cache.folders.values()}),
+        "tags": sorted({tag for doc in cache.documents.values() for tag in doc.tags}),
+        "documents": documents,
+    }
+
+    # Best effort: without the index, the next completion reads the cache again
+    try:
+        index_path.parent.mkdir(parents=True, exist_ok=True)
+        tmp_path = index_path.with_name(f".{index_path.name}.tmp")
+        tmp_path.write_text(json.dumps(index, ensure_ascii=False))
+        os.replace(tmp_path, index_path)
+    except OSError:
+        pass
+    return index
+
+
+def _matching(values: Iterable[str], incomplete: str) -> list[str]:
+    """Return the distinct non-empty values starting with incomplete (ignoring case), sorted."""
+    prefix = incomplete.casefold()
+    return sorted(
+        {value for value in values if value and value.casefold().startswith(prefix)},
+        key=str.casefold,
+    )
@@ -0,0 +1,142 @@
+"""Confluence publishing command."""
+
+import html
+from typing import Annotated, Optional
+
+import typer
+from rich.console import Console
+
+from granola.api.models import Document
+from granola.cli.documents import fetch_documents_with_folders
+from granola.cli.exit_codes import ExitCode
+from granola.confluence import ConfluenceClient, ConfluenceError
+from granola.source import get_notes_html
+from granola.utils.dates import parse_timestamp
+
+console = Console()
+
+UNCATEGORIZED_FOLDER = "Uncategorized"
+
+
+def confluence_cmd(
+    url: Annotated[
+        str,
+        typer.Option("--url", envvar="CONFLUENCE_URL", help="Confluence base URL"),
+    ],
+    space: Annotated[
+        str,
+        typer.Option("--space", envvar="CONFLUENCE_SPACE", help="Confluence space key"),
+    ],
+    user: Annotated[
+        str,
+        typer.Option("--user", envvar="CONFLUENCE_USER", help="Confluence account email"),
+    ],
+    token: Annotated[
+        str,
+        typer.Option("--token", envvar="CONFLUENCE_TOKEN", help="Confluence API token"),
+    ],
+    parent_id: Annotated[
+        Optional[str],
+        typer.Option("--parent-id", help="ID of the page to publish folder pages under"),
+    ] = None,
+    timeout: Annotated[
+        int,
+        typer.Option("--timeout", help="HTTP timeout in seconds"),
+    ] = 120,
+) -> None:
+    """Publish Granola notes to Confluence, one page per meeting.
+
+    Meeting pages are grouped under a parent page per Granola folder. Each page is
+    labelled with its Granola document ID, so re-running the command updates the
+    existing pages instead of creating duplicates.
+    """
+    from granola.cli.main import state
+
+    documents, doc_folders, _ = fetch_documents_with_folders(timeout)
+
+    confluence = ConfluenceClient(url, space, user, token, logger=state.logger)
+    folder_pages: dict[str, str] = {}
+    created = updated = skipped = 0
+
+    console.print(f"Publishing {len(documents)} documents to Confluence space {space}...")
+    try:
+        for doc in documents:
+            notes_html = get_notes_html(doc)
+            if not notes_html:
+                state.logger.debug(f"Skipping document '{doc.title}' - no notes")
+                continue
+
+            folders = doc_folders.get(doc.id) or [UNCATEGORIZED_FOLDER]
+            folder = folders[0]
+            if folder not in folder_pages:
+                folder_pages[folder] = _ensure_folder_page(confluence, folder, parent_id)
+
+            title = _page_title(doc)
+            body = _page_body(doc, folders, notes_html)
+
+            page = confluence.find_page_by_doc_id(doc.id)
+            if page is None:
+                page = confluence.create_page(title, body, parent_id=folder_pages[folder])
+                confluence.add_doc_label(page.id, doc.id)
+                confluence.set_doc_property(page.id, doc.id, doc.content_updated_at)
+                created += 1
+                continue
+
+            synced = confluence.get_doc_property(page.id) or {}
+            if synced.get("value", {}).get("updated_at") == doc.content_updated_at:
+                skipped += 1
+                continue
+
+            confluence.update_page(page, title, body, parent_id=folder_pages[folder])
+            confluence.set_doc_property(page.id, doc.id, doc.content_updated_at)
+            updated += 1
+    except ConfluenceError as e:
+        console.print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(ExitCode.NETWORK)
+    finally:
+        confluence.close()
+
+    console.print(
+        f"[green]✓[/green] Confluence publish completed: "
+        f"{created} created, {updated} updated, {skipped} skipped"
+    )
+    state.logger.info(
+        f"Confluence publish completed: created={created}, updated={updated}, skipped={skipped}"
+    )
+
+
+def _ensure_folder_page(
+    confluence: ConfluenceClient, folder: str, parent_id: Optional[str]
+) -> str:
+    """Return the ID of the page for a folder, creating it if needed."""
+    page = confluence.find_page_by_title(folder)
+    if page is None:
+        page = confluence.create_page(
+            folder,
+            f"<p>Meeting notes from the Granola folder <strong>{html.escape(folder)}</strong>.</p>",
+            parent_id=parent_id,
+        )
+    return page.id
+
+
+def _page_title(doc: Document) -> str:
+    """Build a page title; Confluence requires titles to be unique per space."""
+    title = doc.title or "Untitled"
+    created = parse_timestamp(doc.created_at)
+    if created:
+        return f"{created.strftime('%Y-%m-%d')} {title}"
+    return f"{title} ({doc.id[:8]})"
+
+
+def _page_body(doc: Document, folders: list[str], notes_html: str) -> str:
+    """Build the storage-format body for a meeting page."""
+    rows = [
+        ("Created", doc.created_at),
+        ("Updated", doc.updated_at),
+        ("Folders", ", ".join(folders)),
+        ("Granola ID", doc.id),
+    ]
+    table = "".join(
+        f"<tr><th>{name}</th><td>{html.escape(value)}</td></tr>" for name, value in rows
+    )
+    return f"<table><tbody>{table}</tbody></table>\n{notes_html}"
@@ -0,0 +1,110 @@
+"""Dashboard command: an HTML page of charts and a searchable table of meetings."""
+
+import os
+from pathlib import Path
+from typing import Annotated, Optional
+from urllib.parse import quote
+
+import typer
+from rich.console import Console
+
+from granola.cli.completion import complete_folders
+from granola.cli.exit_codes import ExitCode
+from granola.cli.report import load_cache_meetings
+from granola.formatters.dashboard import format_dashboard
+from granola.writers.manifest import MANIFEST_FILENAME, Manifest
+
+console = Console()
+
+
+def dashboard_cmd(
+    output: Annotated[
+        str,
+        typer.Option("--output", help="Path of the HTML file to write"),
+    ] = "dashboard.html",
+    export_dir: Annotated[
+        Optional[str],
+        typer.Option("--export-dir", help="Export output directory to link meetings to"),
+    ] = None,
+    title: Annotated[
+        str,
+        typer.Option("--title", help="Page title"),
+    ] = "Granola meetings",
+    weeks: Annotated[
+        int,
+        typer.Option("--weeks", min=1, help="Weeks the meetings-per-week chart covers"),
+    ] = 26,
+    since: Annotated[
+        Optional[str],
+        typer.Option("--since", help="Only meetings from this year, month or day on (2024-01)"),
+    ] = None,
+    until: Annotated[
+        Optional[str],
+        typer.Option("--until", help="Only meetings up to the end of this year, month or day"),
+    ] = None,
+    folder: Annotated[
+        Optional[list[str]],
+        typer.Option(
+            "--folder",
+            help="Only meetings in this folder (can be used multiple times)",
+            autocompletion=complete_folders,
+        ),
+    ] = None,
+    cache: Annotated[
+        Optional[str],
+        typer.Option("--cache", help="Path to Granola cache file"),
+    ] = None,
+) -> None:
+    """Generate a dashboard of your meetings as a single HTML page.
+
+    The page charts meetings per week and recorded hours by folder, and lists
+    every meeting in a table you can search by title, folder or attendee. It is
+    self-contained (no scripts or styles are loaded from elsewhere).
+
+    With --export-dir, meeting titles link to their exported files, found in
+    the export's manifest. Links are relative to the page, so keep the page
+    and the export in the same places relative to each other.
+    """
+    from granola.cli.main import state, resolve_path
+
+    output_path = resolve_path(output)
+    if output_path is None:
+        console.print("[red]Error:[/red] --output must be a file path")
+        raise typer.Exit(ExitCode.ERROR)
+
+    links: dict[str, str] = {}
+    if export_dir:
+        export_path = resolve_path(export_dir)
+        if export_path is None or not (export_path / MANIFEST_FILENAME).is_file():
+            console.print(
+                f"[red]Error:[/red] No manifest in {export_dir}; is it a granola export folder?"
+            )
+            raise typer.Exit(ExitCode.ERROR)
+        links = _export_links(Manifest.load(export_path), output_path.parent)
+
+    meetings = load_cache_meetings(cache, since, until, folder)
+    page = format_dashboard(meetings, links, title=title, weeks=weeks)
+
+    try:
+        output_path.parent.mkdir(parents=True, exist_ok=True)
+        output_path.write_text(page, encoding="utf-8")
+    except OSError as e:
+        console.print(f"[red]Error:[/red] Failed to write {output_path}: {e}")
+        raise typer.Exit(ExitCode.ERROR)
+
+    console.print(f"[green]✓[/green] Wrote a dashboard of {len(meetings)} meetings to {output_path}")
+    state.logger.info(f"Dashboard written to {output_path} ({len(links)} meetings linked)")
+
+
+def _export_links(manifest: Manifest, page_dir: Path) -> dict[str, str]:
+    """Return a link to each document's exported file, relative to the page's directory.
+
+    A document exported to several files (transcripts and notes, say) links to
+    the first of them.
+    """
+    links: dict[str, str] = {}
+    for path in sorted(manifest.paths()):
+        doc_id = manifest.doc_id(path)
+        if doc_id and doc_id not in links and path.is_file():
+            links[doc_id] = quote(Path(os.path.relpath(path, page_dir)).as_posix())
+    return links
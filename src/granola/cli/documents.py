@@ -0,0 +1,75 @@
+"""Documents and their folders from the Granola API, for commands that publish notes."""
+
+import typer
+from rich.console import Console
+
+from granola.api.auth import AuthError, get_access_token
+from granola.api.client import APIError, GranolaClient
+from granola.api.models import Document
+from granola.cli.exit_codes import ExitCode, api_error_exit_code
+
+console = Console()
+
+
+def fetch_documents_with_folders(
+    timeout: int,
+) -> tuple[list[Document], dict[str, list[str]], dict[str, str]]:
+    """Fetch the user's documents, the names of the folders each one is in, and how they nest.
+
+    Exits if supabase.json can't be read or the documents can't be fetched. If
+    only the folders can't be fetched, that's logged and documents are in none.
+
+    Args:
+        timeout: HTTP timeout in seconds.
+
+    Returns:
+        Tuple of (documents, map of document ID -> folder names, map of folder
+        name -> parent folder name for nested folders), the folder maps as
+        is_filtered_out takes them.
+    """
+    from granola.cli.main import state
+
+    supabase_path = state.supabase
+    if not supabase_path:
+        console.print(
+            "[red]Error:[/red] supabase.json path not set. "
+            "Use --supabase flag, SUPABASE_FILE env, or config file."
+        )
+        raise typer.Exit(ExitCode.AUTH)
+
+    if not supabase_path.exists():
+        console.print(f"[red]Error:[/red] supabase.json not found at {supabase_path}")
+        raise typer.Exit(ExitCode.AUTH)
+
+    try:
+        access_token = get_access_token(supabase_path)
+    except (AuthError, FileNotFoundError) as e:
+        console.print(f"[red]Error:[/red] Failed to read supabase.json: {e}")
+        raise typer.Exit(ExitCode.AUTH)
+
+    console.print("Fetching documents from Granola API...")
+    try:
+        client = GranolaClient(access_token, timeout=timeout, transport=state.http_transport)
+        documents = client.get_documents()
+    except APIError as e:
+        console.print(f"[red]Error:[/red] API request failed: {e}")
+        raise typer.Exit(api_error_exit_code(e))
+
+    doc_folders: dict[str, list[str]] = {}
+    folder_parents: dict[str, str] = {}
+    try:
+        lists = client.get_document_lists()
+    except APIError as e:
+        state.logger.warning(
+            f"Failed to fetch folder data from API (continuing without folders): {e}"
+        )
+        lists = []
+    titles = {lst.id: lst.title or "Unnamed" for lst in lists}
+    for lst in lists:
+        for doc in lst.documents:
+            if doc.get("id"):
+                doc_folders.setdefault(doc["id"], []).append(titles[lst.id])
+        parent = titles.get(lst.parent_document_list_id or "")
+        if parent:
+            folder_parents[titles[lst.id]] = parent
+    return documents, doc_folders, folder_parents
@@ -0,0 +1,32 @@
+"""Process exit codes.
+
+Wrapper scripts and launchd jobs can branch on these to tell what went wrong.
+They are part of the CLI's public interface; don't renumber them.
+"""
+
+from enum import IntEnum
+
+from granola.api.client import APIError
+
+
+class ExitCode(IntEnum):
+    """Exit codes returned by granola commands."""
+
+    OK = 0
+    ERROR = 1  # generic failure (invalid options, config file errors, ...)
+    # 2 is used by Typer for command-line usage errors
+    AUTH = 3  # supabase.json missing or unreadable, or the API rejected the token
+    NETWORK = 4  # API unreachable or returned an error
+    CACHE = 5  # Granola cache file missing or unreadable
+    PARTIAL = 6  # finished, but some documents failed to export
+    OUT_OF_SYNC = 7  # a check found the output out of date (or meetings without notes)
+    LOCKED = 8  # another export is running on the same output folder
+    TOO_MANY_ERRORS = 9  # stopped once --max-errors documents had failed
+    INTERRUPTED = 130  # stopped by Ctrl-C
+
+
+def api_error_exit_code(error: APIError) -> ExitCode:
+    """Return the exit code for a failed API request."""
+    if error.status_code in (401, 403):
+        return ExitCode.AUTH
+    return ExitCode.NETWORK
@@ -1,34 +1,124 @@
 """Combined export command with folder structure."""
 
+import contextvars
 import json
 import logging
-from dataclasses import dataclass
+import os
+import re
+import signal
+import sys
+import threading
+import time
+from concurrent.futures import Future
+from contextlib import contextmanager
+from dataclasses import dataclass, field, replace
 from datetime import datetime, timezone
 from pathlib import Path
-from typing import Annotated, Optional
+from typing import Annotated, Callable, Iterator, Optional
 
+import httpx
 import typer
 from rich.console import Console
 
 from granola.api.auth import AuthError, get_access_token
 from granola.api.client import APIError, GranolaClient
-from granola.api.models import Document
-from granola.api.models import ProseMirrorDoc
-from granola.cache.reader import SharedDocument, get_default_cache_path, read_cache
-from granola.formatters.combined import format_combined, format_transcript
-from granola.prosemirror.converter import to_markdown
+from granola.api.models import DEEP_LINKS, Document, Person, Workspace
+from granola.cache.reader import CacheData, CacheError, get_default_cache_path, read_cache
+from granola.cli.completion import complete_folders, complete_tags
+from granola.cli.exit_codes import ExitCode, api_error_exit_code
+from granola.cli.summary import (
+    folder_lines,
+    json_report,
+    print_plan,
+    print_request_stats,
+    print_summary,
+    summary_line,
+)
+from granola.cli.transcripts import TRANSCRIPT_SOURCES, load_speaker_map, report_skipped
+from granola.config.defaults import default_export_dir
+from granola.config.file import get_default_config_path, get_section
+from granola.formatters.apple_notes import format_apple_notes
+from granola.formatters.combined import format_combined
+from granola.formatters.interleaved import LAYOUTS
+from granola.formatters.registry import Formatter, formatter_names, get_formatter
+from granola.formatters.template import (
+    SlugRenderer,
+    TemplateError,
+    TemplateRenderer,
+    template_extension,
+)
+from granola.interfaces import Renderer
+from granola.source import ExportSource
 from granola.sync_config import (
     SyncConfig,
     get_effective_exclusions,
     load_sync_config,
     save_sync_config,
 )
+from granola.utils.dates import parse_age
+from granola.utils.language import STOPWORDS
+from granola.utils.lock import ExportLock, LockError
+from granola.utils.metrics import ExportMetrics, push, write_textfile
+from granola.utils.paths import format_output_template, split_output_template
+from granola.utils.selection import ORDERS, SORT_FIELDS
+from granola.utils.stats import DEFAULT_READING_WPM
+from granola.utils.timing import Timings
 from granola.webhooks import WebhookDispatcher, WebhookPayload
-from granola.writers.sync_writer import ExportDoc, SyncResult, SyncStats, SyncWriter
+from granola.writers.apple_notes import (
+    APPLE_NOTES_FORMAT,
+    DEFAULT_NOTES_FOLDER,
+    AppleNotesWriter,
+)
+from granola.writers.archive import is_tar_archive, write_tar_archive, write_zip_archive
+from granola.writers.attachments import ATTACHMENTS_DIR, Attachments
+from granola.writers.audio import AUDIO_DIR, copy_recordings
+from granola.writers.compression import GZIP_SUFFIX, gzip_bytes, gzip_content
+from granola.writers.daily_notes import (
+    DEFAULT_DAILY_NOTE_FORMAT,
+    DailyNoteEntry,
+    link_daily_notes,
+)
+from granola.writers.encryption import AGE_SUFFIX, AgeEncryptor, EncryptionError
+from granola.writers.feed import FeedEntry, make_summary, write_atom_feed
+from granola.writers.manifest import Manifest
+from granola.writers.obsidian import find_obsidian_vault
+from granola.writers.run_state import RunState
+from granola.writers.sync_writer import (
+    ORGANIZE_BY,
+    SYNC_ACTIONS,
+    ExportDoc,
+    SyncFailure,
+    SyncResult,
+    SyncStats,
+    SyncWriter,
+    archived_doc_ids,
+)
 
 console = Console()
 
 
+@dataclass
+class Destination:
+    """An additional output written by the same export run.
+
+    Paths ending in .zip are written as a zip archive, and paths ending in .tar.gz
+    (or .tgz) as a gzipped tar archive (both rebuilt on every run); anything else
+    is synced as a directory. For the apple-notes format, path is the Notes folder
+    meetings are written under.
+    """
+
+    path: Path
+    format: str = "txt"
+    organize_by: str = "folder"
+    zettel: bool = False
+    # Workspace (ID, name, or slug) to restrict this destination to
+    workspace: str | None = None
+
+    @property
+    def is_archive(self) -> bool:
+        return self.path.suffix.lower() == ".zip" or is_tar_archive(self.path)
+
+
 @dataclass
 class ExportResult:
     """Result of a programmatic export operation."""
@@ -39,8 +129,13 @@ class ExportResult:
     moved: int = 0
     deleted: int = 0
     skipped: int = 0
+    failed: int = 0
     error_message: str = ""
     webhook_summary: str = ""
+    # Documents that failed to export ("Title (id): error"); the rest were synced
+    failures: list[str] = field(default_factory=list)
+    # Counts per destination folder and action (see SyncStats.folders)
+    folders: dict[str, dict[str, int]] = field(default_factory=dict)
     # Effective exclusions (merged from local + sync folder)
     # App should update local settings if these differ
     effective_excluded_folders: list[str] | None = None
@@ -52,6 +147,7 @@ def run_export(
     cache_path: str | None = None,
     excluded_folders: list[str] | None = None,
     excluded_folders_updated: str | None = None,
+    included_folders: list[str] | None = None,
     webhook_configs: list[dict] | None = None,
     timeout: int = 120,
     logger: logging.Logger | None = None,
@@ -64,6 +160,7 @@ def run_export(
         cache_path: Path to Granola cache file.
         excluded_folders: List of folder names to exclude (from local settings).
         excluded_folders_updated: ISO timestamp of when local exclusions were updated.
+        included_folders: Folder allow-list (defaults to the sync folder config).
         webhook_configs: List of webhook configuration dicts.
         timeout: HTTP timeout in seconds.
         logger: Optional logger for debug output.
@@ -87,6 +184,10 @@ def run_export(
     excluded_set = set(effective_excluded)
     logger.info(f"Effective excluded folders: {effective_excluded}")
 
+    if included_folders:
+        sync_config.included_folders = list(included_folders)
+    included_set = set(sync_config.included_folders)
+
     # 1. Resolve supabase path
     if not supabase_path:
         # Try default location
@@ -106,7 +207,9 @@ def run_export(
     except (AuthError, FileNotFoundError) as e:
         return ExportResult(success=False, error_message=f"Failed to read supabase.json: {e}")
 
-    # 3. Fetch documents from API
+    # 3. Fetch documents from API, while the cache is read in the background (4)
+    cache_file = Path(cache_path) if cache_path else get_default_cache_path()
+    cache_future = _read_cache_in_background(cache_file)
     try:
         client = GranolaClient(access_token, timeout=timeout)
         api_docs = client.get_documents()
@@ -133,18 +236,16 @@ def run_export(
     except Exception as e:
         logger.warning(f"Error fetching folder data: {e}")
 
-    # 4. Read cache for transcripts only (folders now come from API)
+    # 4. Wait for the cache, read for transcripts only (folders now come from API)
     # If cache read fails, continue with empty cache (still sync API docs)
-    cache_file = Path(cache_path) if cache_path else get_default_cache_path()
     cache_data = None
     try:
-        cache_data = read_cache(cache_file)
+        cache_data = cache_future.result()
     except Exception as e:
         logger.warning(f"Failed to read cache file (continuing without transcripts): {e}")
 
     # If no cache data, create empty structure
     if cache_data is None:
-        from granola.cache.reader import CacheData
         cache_data = CacheData(
             documents={},
             transcripts={},
@@ -155,169 +256,63 @@ def run_export(
 
     logger.info(f"Loaded cache: {len(cache_data.transcripts)} transcripts")
 
-    # Helper to get folder names - prefer API data, fall back to cache
-    def get_folder_names(doc_id: str) -> list[str]:
-        """Get folder names for a document, preferring API over cache."""
-        if doc_id in api_doc_folders:
-            return api_doc_folders[doc_id]
-        return cache_data.get_folder_names(doc_id)
-
-    # 5. Build export documents
-    all_doc_ids: set[str] = set()
-    export_docs: list[ExportDoc] = []
-
-    for api_doc in api_docs:
-        folders = get_folder_names(api_doc.id)
-
-        if excluded_set and any(f in excluded_set for f in folders):
-            continue
-
-        all_doc_ids.add(api_doc.id)
-        segments = cache_data.transcripts.get(api_doc.id, [])
-        notes_content = _get_notes_content(api_doc)
-
-        has_notes = notes_content and notes_content.strip()
-        has_transcript = len(segments) > 0
-        if not has_notes and not has_transcript:
-            continue
-
-        content = format_combined(
-            title=api_doc.title,
-            doc_id=api_doc.id,
-            created_at=api_doc.created_at,
-            updated_at=api_doc.updated_at,
-            notes_content=notes_content,
-            segments=segments,
-            folders=folders,
-        )
-        transcript_text = format_transcript(segments) if segments else ""
-
-        try:
-            ts = api_doc.created_at.replace("Z", "+00:00")
-            created_at = datetime.fromisoformat(ts)
-            if created_at.tzinfo is None:
-                created_at = created_at.replace(tzinfo=timezone.utc)
-        except ValueError:
-            created_at = datetime.now(timezone.utc)
+    # 5. Documents are rendered as they are written (not all held in memory)
+    source = ExportSource(
+        api_docs=api_docs,
+        cache_data=cache_data,
+        api_doc_folders=api_doc_folders,
+        excluded=excluded_set,
+        included=included_set,
+        # Nested folders are written under their parents (hierarchy comes from the cache)
+        folder_parents=cache_data.get_folder_parents(),
+    )
 
-        try:
-            ts = api_doc.updated_at.replace("Z", "+00:00")
-            updated_at = datetime.fromisoformat(ts)
-            if updated_at.tzinfo is None:
-                updated_at = updated_at.replace(tzinfo=timezone.utc)
-        except ValueError:
-            updated_at = datetime.now(timezone.utc)
-
-        export_docs.append(ExportDoc(
-            id=api_doc.id,
-            title=api_doc.title,
-            created_at=created_at,
-            updated_at=updated_at,
-            content=content,
-            folders=folders,
-            has_notes=has_notes,
-            has_transcript=has_transcript,
-            notes_content=notes_content or "",
-            transcript_content=transcript_text,
-        ))
-
-    # 5b. Process shared documents from cache
-    for shared_doc in cache_data.shared_documents.values():
-        if shared_doc.id in all_doc_ids:
-            continue
+    # 6. Sync to filesystem, dispatching webhooks as documents change
+    dispatcher = WebhookDispatcher(webhook_configs, logger=logger) if webhook_configs else None
+    webhook_results = []
 
-        folders = get_folder_names(shared_doc.id)
-        if excluded_set and any(f in excluded_set for f in folders):
-            continue
+    def on_result(result: SyncResult) -> None:
+        if dispatcher and result.doc.has_notes and result.action != "moved":
+            webhook_results.extend(dispatcher.dispatch(_webhook_payload(result)))
 
-        all_doc_ids.add(shared_doc.id)
-        segments = cache_data.transcripts.get(shared_doc.id, [])
-        notes_content = _get_shared_notes_content(shared_doc)
+    sync_writer = SyncWriter(
+        output_dir,
+        logger=logger,
+        excluded_folders=list(excluded_set),
+        included_folders=list(included_set),
+        folder_parents=source.folder_parents,
+    )
 
-        has_notes = notes_content and notes_content.strip()
-        has_transcript = len(segments) > 0
-        if not has_notes and not has_transcript:
-            continue
+    # Hold the output folder lock while writing, so a manual and a scheduled
+    # export can't interleave
+    lock = ExportLock(output_dir)
+    try:
+        lock.acquire()
+    except LockError as e:
+        return ExportResult(success=False, error_message=str(e))
 
-        content = format_combined(
-            title=shared_doc.title,
-            doc_id=shared_doc.id,
-            created_at=shared_doc.created_at,
-            updated_at=shared_doc.updated_at,
-            notes_content=notes_content,
-            segments=segments,
-            folders=folders,
+    failures: list[SyncFailure] = []
+    try:
+        stats, _ = sync_writer.sync(
+            source.iter_docs(format_combined, logger, on_error=failures.append),
+            source.doc_ids(),
+            outline=source.iter_docs(None, logger),
+            on_result=on_result,
         )
-        transcript_text = format_transcript(segments) if segments else ""
-
-        try:
-            ts = shared_doc.created_at.replace("Z", "+00:00")
-            created_at = datetime.fromisoformat(ts)
-            if created_at.tzinfo is None:
-                created_at = created_at.replace(tzinfo=timezone.utc)
-        except ValueError:
-            created_at = datetime.now(timezone.utc)
-
-        try:
-            ts = shared_doc.updated_at.replace("Z", "+00:00")
-            updated_at = datetime.fromisoformat(ts)
-            if updated_at.tzinfo is None:
-                updated_at = updated_at.replace(tzinfo=timezone.utc)
-        except ValueError:
-            updated_at = datetime.now(timezone.utc)
-
-        export_docs.append(ExportDoc(
-            id=shared_doc.id,
-            title=shared_doc.title,
-            created_at=created_at,
-            updated_at=updated_at,
-            content=content,
-            folders=folders,
-            has_notes=has_notes,
-            has_transcript=has_transcript,
-            notes_content=notes_content or "",
-            transcript_content=transcript_text,
-        ))
+        failures.extend(sync_writer.failures)
 
-    # 6. Sync to filesystem (passing exclusions to delete excluded folders)
-    sync_writer = SyncWriter(output_dir, logger=logger, excluded_folders=list(excluded_set))
-    try:
-        stats, results = sync_writer.sync(export_docs, all_doc_ids)
+        # 6b. Save sync config to sync folder (so exclusions sync across computers)
+        save_sync_config(output_dir, sync_config)
     except Exception as e:
         import traceback
         return ExportResult(success=False, error_message=f"Sync failed: {e}\n{traceback.format_exc()}")
+    finally:
+        lock.release()
 
-    # 6b. Save sync config to sync folder (so exclusions sync across computers)
-    save_sync_config(output_dir, sync_config)
-
-    # 7. Dispatch webhooks
+    # 7. Summarize webhooks
     webhook_summary = ""
-    if webhook_configs:
-        dispatcher = WebhookDispatcher(webhook_configs, logger=logger)
-        webhook_results = []
-
-        for result in results:
-            if not result.doc.has_notes:
-                continue
-
-            payload = WebhookPayload.create(
-                event=f"document.{result.action}",
-                doc_id=result.doc.id,
-                title=result.doc.title or "",
-                created_at=result.doc.created_at.isoformat(),
-                updated_at=result.doc.updated_at.isoformat(),
-                folders=result.doc.folders,
-                file_path=str(result.file_path),
-                markdown_content=result.doc.content,
-                notes_content=result.doc.notes_content,
-                transcript_content=result.doc.transcript_content,
-                has_notes=result.doc.has_notes,
-                has_transcript=result.doc.has_transcript,
-            )
-            webhook_results.extend(dispatcher.dispatch(payload))
-
-        if webhook_results:
-            webhook_summary = dispatcher.get_summary(webhook_results)
+    if dispatcher and webhook_results:
+        webhook_summary = dispatcher.get_summary(webhook_results)
 
     return ExportResult(
         success=True,
@@ -326,17 +321,43 @@ def run_export(
         moved=stats.moved,
         deleted=stats.deleted,
         skipped=stats.skipped,
+        failed=len(failures),
         webhook_summary=webhook_summary,
         effective_excluded_folders=list(excluded_set),
+        failures=[_format_failure(f) for f in failures],
+        folders=stats.folders,
     )
 
 
 def default_export_output() -> Path:
-    """Return the default output directory for combined export."""
-    return Path.home() / "My Drive" / "z. Granola Notes"
+    """Return the output directory used without --output.
+
+    That's output in [export] in the config file, or else the platform default
+    (see default_export_dir).
+    """
+    from granola.cli.main import state, resolve_path
+
+    configured = get_section(state.config, "export").get("output")
+    if configured:
+        return resolve_path(split_output_template(configured)[0]) or default_export_dir()
+    return default_export_dir()
+
+
+def export_output_dir(output: Optional[str]) -> Path:
+    """Return the export's root directory: --output's (or the default) fixed part.
+
+    An output directory can be a template such as ~/Meetings/{year}/{month} (see
+    split_output_template); documents are written under its fixed part.
+    """
+    from granola.cli.main import resolve_path
+
+    if output:
+        return resolve_path(split_output_template(output)[0]) or Path.cwd()
+    return default_export_output()
 
 
 def export_cmd(
+    ctx: typer.Context,
     timeout: Annotated[
         int,
         typer.Option("--timeout", help="HTTP timeout in seconds"),
@@ -351,7 +372,19 @@ def export_cmd(
     ] = None,
     exclude_folder: Annotated[
         Optional[list[str]],
-        typer.Option("--exclude-folder", help="Folder to exclude (can be used multiple times)"),
+        typer.Option(
+            "--exclude-folder",
+            help="Folder to exclude (can be used multiple times)",
+            autocompletion=complete_folders,
+        ),
+    ] = None,
+    include_folder: Annotated[
+        Optional[list[str]],
+        typer.Option(
+            "--include-folder",
+            help="Only export documents in this folder (can be used multiple times)",
+            autocompletion=complete_folders,
+        ),
     ] = None,
     supabase: Annotated[
         Optional[str],
@@ -361,6 +394,335 @@ def export_cmd(
         Optional[list[str]],
         typer.Option("--webhook", help="JSON-encoded webhook config (can be used multiple times)"),
     ] = None,
+    feed: Annotated[
+        bool,
+        typer.Option("--feed", help="Write an index.xml Atom feed of exported meetings"),
+    ] = False,
+    deep_link: Annotated[
+        Optional[str],
+        typer.Option(
+            "--deep-link",
+            help=f"Link each file back to the document in Granola: {', '.join(DEEP_LINKS)}",
+        ),
+    ] = None,
+    wikilinks: Annotated[
+        bool,
+        typer.Option(
+            "--wikilinks",
+            help="Link attendee names in the notes as [[Person Name]] (for Obsidian)",
+        ),
+    ] = False,
+    ai_qa: Annotated[
+        bool,
+        typer.Option(
+            "--ai-qa",
+            help="Add the AI chat's answers and suggested questions after the notes",
+        ),
+    ] = False,
+    add_tag: Annotated[
+        Optional[list[str]],
+        typer.Option(
+            "--add-tag",
+            help="Add this tag to every exported document (can be used multiple times)",
+            autocompletion=complete_tags,
+        ),
+    ] = None,
+    doc_stats: Annotated[
+        bool,
+        typer.Option(
+            "--stats",
+            help="Add word count, transcript length and reading time to the frontmatter",
+        ),
+    ] = False,
+    speakers: Annotated[
+        Optional[str],
+        typer.Option("--speakers", help="speakers.yaml naming transcript speakers"),
+    ] = None,
+    strict: Annotated[
+        bool,
+        typer.Option("--strict", help="Fail if any cache entry can't be parsed"),
+    ] = False,
+    detect_lang: Annotated[
+        bool,
+        typer.Option("--detect-lang", help="Add the meeting's detected language as lang"),
+    ] = False,
+    lang: Annotated[
+        Optional[list[str]],
+        typer.Option(
+            "--lang",
+            help=f"Only export meetings in this language ({', '.join(STOPWORDS)}; repeatable)",
+        ),
+    ] = None,
+    reading_wpm: Annotated[
+        Optional[int],
+        typer.Option(
+            "--reading-wpm",
+            help=f"Reading speed for --stats reading time (default: {DEFAULT_READING_WPM})",
+        ),
+    ] = None,
+    audio: Annotated[
+        bool,
+        typer.Option("--audio", help="Add the meeting's local audio recording, if any, as audio"),
+    ] = False,
+    copy_audio: Annotated[
+        bool,
+        typer.Option(
+            "--copy-audio",
+            help=f"Copy local audio recordings into {AUDIO_DIR}/ in the output (implies --audio)",
+        ),
+    ] = False,
+    attachments: Annotated[
+        bool,
+        typer.Option(
+            "--attachments",
+            help=f"Download images and files linked from notes into {ATTACHMENTS_DIR}/",
+        ),
+    ] = False,
+    daily_notes: Annotated[
+        Optional[str],
+        typer.Option("--daily-notes", help="Link each meeting from its daily note in this folder"),
+    ] = None,
+    daily_note_format: Annotated[
+        Optional[str],
+        typer.Option(
+            "--daily-note-format",
+            help=f"strftime name of daily notes (default: {DEFAULT_DAILY_NOTE_FORMAT})",
+        ),
+    ] = None,
+    export_format: Annotated[
+        str,
+        typer.Option("--format", help=f"Output format: {', '.join(formatter_names())}"),
+    ] = "txt",
+    template: Annotated[
+        Optional[str],
+        typer.Option("--template", help="Jinja2 template to render each document with"),
+    ] = None,
+    zettel: Annotated[
+        bool,
+        typer.Option("--zettel", help="Prefix filenames with a zettel ID (YYYYMMDDHHMM)"),
+    ] = False,
+    layout: Annotated[
+        str,
+        typer.Option(
+            "--layout",
+            help=f"Arrangement of notes and transcript: {', '.join(LAYOUTS)}",
+        ),
+    ] = "sections",
+    section_minutes: Annotated[
+        Optional[int],
+        typer.Option(
+            "--section-minutes",
+            help="Split transcripts into sections with HH:MM headings every N minutes",
+        ),
+    ] = None,
+    section_gap: Annotated[
+        Optional[int],
+        typer.Option(
+            "--section-gap",
+            help="Start a new transcript section after N seconds of silence",
+        ),
+    ] = None,
+    organize_by: Annotated[
+        str,
+        typer.Option("--organize-by", help=f"Directory layout: {', '.join(ORGANIZE_BY)}"),
+    ] = "folder",
+    folder_path: Annotated[
+        Optional[list[str]],
+        typer.Option(
+            "--folder-path",
+            help="Write a folder elsewhere, as 'Folder=~/path' (can be used multiple times)",
+        ),
+    ] = None,
+    destination: Annotated[
+        Optional[list[str]],
+        typer.Option(
+            "--destination",
+            "--dest",
+            help="Also write to 'format:path' (a .zip or .tar.gz path writes an archive; "
+            "'apple-notes' writes to Apple Notes; repeatable)",
+        ),
+    ] = None,
+    wait: Annotated[
+        bool,
+        typer.Option("--wait", help="Wait for a running export of the same folder to finish"),
+    ] = False,
+    compress: Annotated[
+        bool,
+        typer.Option("--compress", help="Gzip exported files (written as <name>.gz)"),
+    ] = False,
+    encrypt: Annotated[
+        Optional[list[str]],
+        typer.Option(
+            "--encrypt",
+            help="Encrypt exported files to this age recipient (age1...; repeatable)",
+        ),
+    ] = None,
+    changed_only: Annotated[
+        bool,
+        typer.Option(
+            "--changed-only",
+            help="Only render documents updated in Granola since the last successful export",
+        ),
+    ] = False,
+    force: Annotated[
+        bool,
+        typer.Option(
+            "--force",
+            help="Rewrite every file, even of documents unchanged since they were exported",
+        ),
+    ] = False,
+    touch_only: Annotated[
+        bool,
+        typer.Option(
+            "--touch-only",
+            help="Write nothing, just set file times (and the manifest) to the documents'",
+        ),
+    ] = False,
+    dry_run: Annotated[
+        bool,
+        typer.Option(
+            "--dry-run",
+            help="List the files that would be added, updated, moved and deleted; write nothing",
+        ),
+    ] = False,
+    merge: Annotated[
+        bool,
+        typer.Option(
+            "--merge", help="Merge duplicate documents of a meeting into one exported file"
+        ),
+    ] = False,
+    min_words: Annotated[
+        Optional[int],
+        typer.Option(
+            "--min-words",
+            min=1,
+            help="Only export meetings with at least N words of notes and transcript",
+        ),
+    ] = None,
+    skip_empty: Annotated[
+        bool,
+        typer.Option(
+            "--skip-empty/--keep-empty",
+            help="Skip documents without notes or transcript, or write them with just a header",
+        ),
+    ] = True,
+    no_prune: Annotated[
+        bool,
+        typer.Option(
+            "--no-prune",
+            help="Don't delete files of deleted documents (run granola clean to)",
+        ),
+    ] = False,
+    archive_older_than: Annotated[
+        Optional[str],
+        typer.Option(
+            "--archive-older-than",
+            help="Move meetings older than this (e.g. 1y, 6m, 90d) into Archive/YYYY/",
+        ),
+    ] = None,
+    show_timings: Annotated[
+        bool,
+        typer.Option("--timings", help="Report time spent in each phase of the export"),
+    ] = False,
+    max_errors: Annotated[
+        Optional[int],
+        typer.Option(
+            "--max-errors",
+            help="Stop the export once this many documents have failed to render or write",
+        ),
+    ] = None,
+    json_report_file: Annotated[
+        Optional[str],
+        typer.Option(
+            "--json-report",
+            help="Write what the export did (counts per folder, failures, timings) as JSON",
+        ),
+    ] = None,
+    metrics_file: Annotated[
+        Optional[str],
+        typer.Option(
+            "--metrics-file",
+            help="Write Prometheus metrics of the run here (node_exporter textfile collector)",
+        ),
+    ] = None,
+    metrics_push: Annotated[
+        Optional[str],
+        typer.Option(
+            "--metrics-push", help="Push Prometheus metrics of the run to this Pushgateway URL"
+        ),
+    ] = None,
+    workspace: Annotated[
+        Optional[list[str]],
+        typer.Option(
+            "--workspace",
+            help="Only export documents in this workspace (ID, name, or slug; repeatable)",
+        ),
+    ] = None,
+    transcript_source: Annotated[
+        str,
+        typer.Option(
+            "--transcript-source",
+            help="Where transcripts come from: auto (cache, or API if the cache is missing), "
+            "cache, or api",
+        ),
+    ] = "auto",
+    include_shared: Annotated[
+        bool,
+        typer.Option(
+            "--include-shared",
+            help="Also fetch documents teammates shared with you, marked with their owner",
+        ),
+    ] = False,
+    attendees: Annotated[
+        bool,
+        typer.Option(
+            "--attendees",
+            help="Add attendees (name, email, company) to the frontmatter",
+        ),
+    ] = False,
+    all_panels: Annotated[
+        bool,
+        typer.Option(
+            "--all-panels",
+            help="Export the notes of every panel (template), not just the last viewed one",
+        ),
+    ] = False,
+    limit: Annotated[
+        Optional[int],
+        typer.Option("--limit", help="Only export the N most recently updated documents"),
+    ] = None,
+    skip: Annotated[
+        int,
+        typer.Option(
+            "--skip", help="Skip the first N documents (most recently updated, unless --sort)"
+        ),
+    ] = 0,
+    sort: Annotated[
+        Optional[str],
+        typer.Option(
+            "--sort",
+            help=f"Process documents ordered by: {', '.join(SORT_FIELDS)} (default: as fetched)",
+        ),
+    ] = None,
+    order: Annotated[
+        Optional[str],
+        typer.Option(
+            "--order",
+            help="Sort direction: asc or desc (default: desc, or asc for title)",
+        ),
+    ] = None,
+    match: Annotated[
+        Optional[str],
+        typer.Option("--match", help="Only include documents whose title matches this regex"),
+    ] = None,
+    exclude_match: Annotated[
+        Optional[str],
+        typer.Option("--exclude-match", help="Skip documents whose title matches this regex"),
+    ] = None,
+    skip_untitled: Annotated[
+        bool,
+        typer.Option("--skip-untitled", help="Skip documents without a title"),
+    ] = False,
 ) -> None:
     """Export combined notes and transcripts with folder structure.
 
@@ -368,17 +730,482 @@ def export_cmd(
     and combines them into .txt files organized by Granola folder structure.
 
     Documents in multiple folders will be duplicated into each folder.
+    Nested Granola folders are written as nested directories (Clients/Acme/...).
     Documents not in any folder will be placed in the "Uncategorized" folder.
     Files are synced incrementally - only updated when the source changes.
-    Deleted documents are removed from the output directory.
+    Deleted documents are removed from the output directory. Only files recorded in the
+    output directory's manifest (.granola-manifest.json) are ever deleted.
+
+    Only one export can write to an output directory at a time (.granola.lock). A
+    second run exits with an error, or waits for the first one with --wait.
+
+    Each export records the last updated time of the documents it wrote
+    (.granola-state.json). Use --changed-only to render and write only documents
+    updated in Granola since then, however the output's files were touched. Run
+    without it after changing export options, or to pick up folder moves, which
+    don't change a document's updated time. Destinations are always written in full.
+
+    Use --force to rewrite every file (and Apple Notes note), not just those of
+    documents updated since they were written, e.g. after changing a template,
+    format or frontmatter option.
+
+    Use --touch-only after restoring the output folder from a backup: no file is
+    written, moved or deleted, but existing files get their document's updated
+    time as their modification time and are recorded in the manifest, so the next
+    export only rewrites what actually changed.
+
+    Use --dry-run to see what an export would change before running it for real:
+    documents are fetched and rendered, and the files that would be added,
+    updated, moved (or folders renamed) and deleted are listed, but nothing in the
+    output folder is written, moved or deleted, and no webhooks are sent. Check
+    the deletions before an export after changing exclusions or --organize-by.
+
+    Use --min-words to leave out trivially short meetings (accidental recordings,
+    30-second huddles): only meetings with at least that many words in their notes
+    and transcript together are exported. Files of shorter meetings are left as
+    they are.
+
+    Documents with no transcript and no words in their notes outside headings
+    (just a template's headings and empty list items, say) are skipped and
+    counted as empty in the summary; use --keep-empty to write them anyway,
+    with just their header.
+
+    Use --no-prune to only add and update files, leaving files of documents deleted
+    in Granola (and empty folders) in place, and run `granola clean` to remove them
+    when you choose.
+
+    On a terminal, the export ends with a table of the files added, updated and
+    moved per folder and the time spent per phase (API fetch, cache parse, render,
+    write, prune); otherwise, with one line of counts. Use --timings to also report
+    the time spent per phase when not on a terminal, and the requests (pages),
+    bytes and time per API endpoint, which tell a slow API from a slow network
+    (--debug logs each request). Use the global --profile option for a full
+    cProfile dump.
+
+    Use --json-report to write what the export did to a JSON file: the numbers of
+    files added, updated, moved, deleted and skipped, in total and per folder, the
+    documents that failed, and the time spent per phase. The counts per folder are
+    also logged.
+
+    To monitor scheduled exports, use --metrics-file to write Prometheus metrics
+    of each run (last run and last success times, documents fetched, files
+    written, errors, API latencies) for node_exporter's textfile collector, or
+    --metrics-push to push them to a Pushgateway ([export] metrics_file and
+    metrics_push in the config file). Metrics are written even if the export
+    fails, so alert on granola_export_last_success_timestamp_seconds.
+
+    Ctrl-C stops the export after the file being written, skips deleting orphaned
+    files, and prints what was done so far (press it twice to abort immediately).
+
+    A document that fails to render or write doesn't stop the export: the rest are
+    synced, the failures are listed at the end, and the command exits non-zero.
+    Use --max-errors to stop once that many documents have failed instead (when
+    the disk is full, say, or the API returns garbage), without deleting orphaned
+    files; the command then exits with code 9.
+
+    Transcripts are read from the local Granola cache. Use --transcript-source api to
+    fetch them from the API instead (one request per document), e.g. on a machine
+    without the Granola app; by default the API is used only if the cache is missing.
+
+    Use --workspace to export only the documents of one Granola workspace (see
+    `granola workspaces`), e.g. team notes and personal notes to separate outputs.
+    Destinations can be restricted with workspace = "..." in the config file.
+
+    Use --include-shared to also export documents teammates have shared with you
+    (e.g. in shared folders), not just your own. They are fetched from the API and
+    marked with shared: true and their owner in the frontmatter (or header).
+
+    Use --attendees to list each meeting's attendees in the frontmatter (or header).
+    Names, companies and job titles missing from the meeting are looked up in your
+    Granola people directory.
+
+    Use --all-panels to export every panel of a meeting (the notes generated with
+    each template you used), each under its own heading, instead of only the last
+    viewed one. Panels are fetched from the API, one request per document.
+
+    Use --limit and --skip to export only some of the most recently updated
+    documents, e.g. --limit 20 for a quick demo. Files of the other documents are
+    left as they are.
+
+    Use --sort created|updated|title and --order asc|desc to set the order documents
+    are processed in (and so which ones --limit picks, and which of two documents
+    with the same name gets the numbered file name).
+
+    Use --match and --exclude-match to include or skip documents by title (regular
+    expressions), e.g. --match '^Weekly 1:1' for all of your weekly 1:1s. Use
+    --skip-untitled to leave out documents nobody gave a title (quick notes and
+    accidental recordings, otherwise exported as "untitled").
+
+    Use --merge to export duplicates (documents with the same title created within
+    30 minutes of each other, and not for different calendar events) as one file:
+    the notes and transcript of all of them, named after the earliest. The merge is
+    recorded in the manifest, so deleting the duplicates in Granola doesn't delete
+    the merged file: it's kept as it is from then on.
+
+    Use --archive-older-than (e.g. 1y, 6m, 90d) to move the files of older meetings
+    into Archive/YYYY/ in the output (by the year of the meeting, keeping their
+    folders), so the folders you work in stay small. Archived meetings aren't
+    rendered or checked for changes again. Exporting without the option moves
+    them back out of the archive and brings them up to date.
 
     Use --exclude-folder to skip documents in specific folders. Documents in an excluded
-    folder will be skipped entirely, even if they also belong to other folders.
+    folder (or one of its subfolders) will be skipped entirely, even if they also belong
+    to other folders.
+
+    Use --include-folder to restrict the export to an allow-list of folders. The
+    allow-list is saved to the sync folder config and reused by later runs. Existing
+    files of filtered-out documents are not treated as orphans.
+
+    The output directory can be a template with {year}, {month} and {day}, filled in
+    per meeting from its date: --output '~/Notes/Meetings/{year}/{month}' writes
+    2024/01/<folder>/... under ~/Notes/Meetings, which holds the manifest (give it
+    as --output to verify and clean). Archived meetings go in Archive/YYYY/ instead.
+
+    Use --folder-path (or [export.folder_paths] in the config file) to send a
+    folder's documents to a directory of its own, possibly outside the output root.
+
+    Use --feed to also write an index.xml Atom feed to the output directory.
+
+    Use --deep-link app (or deep_link in [export] in the config file) to add a
+    granola_url link to the frontmatter (or header) that opens the meeting in the
+    Granola app, with its audio; --deep-link web opens it in the browser instead.
+
+    Use --wikilinks to turn the names of a meeting's attendees (and @-mentions of
+    them) in its notes into [[Person Name]] links, so Obsidian's graph connects
+    meetings to the people in them.
+
+    Use --ai-qa to add an "AI Q&A" section after the notes with the answers of
+    Granola's chat and the questions it suggests asking, which are otherwise
+    only in the app.
+
+    Set folder_tags in [export] in the config file to a prefix (e.g. "meeting/") to
+    add each meeting's folders to the frontmatter (or header) as tags, like
+    meeting/clients/acme, for navigating by tag rather than by directory.
+
+    Use --add-tag (or tags in [export] in the config file) to add fixed tags to
+    every meeting, e.g. --add-tag granola --add-tag meeting, so exported files are
+    easy to find and manage in bulk. They come after any folder tags.
+
+    Use --stats to add words (the notes' word count), transcript_minutes (how
+    long the transcript runs) and reading_minutes (time to read the notes and
+    transcript, at --reading-wpm words per minute) to the frontmatter (or header),
+    e.g. to chart meeting load with Dataview.
+
+    Use --speakers (or speakers in [export] in the config file, or
+    ~/.config/granola/speakers.yaml) to replace "You" and "System" in transcripts
+    with real names, per meeting or per calendar attendee.
+
+    Use --detect-lang to add lang (the language the meeting was held in, guessed
+    from its transcript or notes) to the frontmatter (or header), and --lang to
+    export only meetings in a language, e.g. --lang es --output ~/Reuniones. Files
+    of meetings in other languages are left as they are.
+
+    Use --audio to add audio (the path of the meeting's audio recording) to the
+    frontmatter (or header), for meetings the Granola cache has a local recording
+    of. Use --copy-audio to also copy the recordings into an audio/ folder in the
+    output, so notes keep their recording after Granola's copy is gone.
+
+    Use --attachments to download the images and files (PDFs, slides, ...) notes
+    link to into an attachments/ folder in the output, and link to the copies
+    instead, so notes stay complete when the originals disappear from Granola.
+    Only files written by this export are changed; existing files pick up the
+    copies when their meeting next changes.
+
+    Use --daily-notes (or daily_notes in [export] in the config file) to link each
+    meeting from the Obsidian daily note of its day (YYYY-MM-DD.md, or set
+    --daily-note-format). Links go in a block at the end of the note; notes that
+    don't exist yet are created with just the links.
+
+    Use --format hugo to write Hugo page bundles (content/meetings/<slug>/index.md)
+    or --format jekyll to write Jekyll posts (_posts/YYYY-MM-DD-<slug>.md), with
+    folders as tags, ready to build a static site from.
+
+    Use --format dendron to write a flat Dendron vault with dot-hierarchy names
+    (meetings.<folder>.YYYY.MM.DD.<slug>.md).
+
+    Use --format tex to write LaTeX fragments (a \\section per meeting, with
+    itemize lists and escaped text) to \\input into typeset reports.
+
+    Use --format adoc to write AsciiDoc for Asciidoctor or Antora, with metadata
+    as document attributes (folders as :page-tags:) instead of YAML frontmatter.
+
+    Use --format rst to write reStructuredText for Sphinx, with metadata as a
+    field list at the top of each page.
+
+    Use --format textbundle to write a TextPack per meeting (a zipped TextBundle
+    holding the Markdown and the images it links to), which Bear, Ulysses and
+    Craft import. Attachments are packed into each file, so --attachments isn't
+    needed.
+
+    Use --template (or template in [export] in the config file) to render each
+    document through your own Jinja2 template instead; the --format layout still
+    applies. A template named like meeting.md.j2 writes .md files. Use
+    [export.templates] in the config file to pick a template by the Granola note
+    template a meeting was written with (e.g. one for 1:1s, one for interviews).
+
+    Use --zettel to prefix filenames with a timestamp-based zettel ID instead of the
+    date (ignored by formats with their own naming, such as hugo and jekyll).
+
+    Cache entries that can't be parsed are skipped with a warning (details with
+    --debug); use --strict to fail instead of exporting without them.
+
+    Use --layout interleaved to place typed notes in the transcript at the time they
+    were written, reconstructing the meeting as it happened. Only notes the editor
+    recorded a time for are moved; the rest stay in the notes section.
+
+    Use --section-minutes (e.g. 10) and/or --section-gap (seconds of silence, e.g.
+    30) to split long transcripts into sections with ### HH:MM headings, so
+    editors with an outline view can jump around an hour-long meeting.
+
+    Use --compress to gzip each exported file (Standup_0f1e2d3c.txt.gz), e.g. for
+    a year of raw transcripts. Like --encrypt, it applies to exported documents
+    only; a .tar.gz destination writes a gzipped archive instead.
+
+    Use --encrypt age1... (or encrypt in [export] in the config file) to encrypt
+    each exported file to one or more age public keys before it's written, as
+    <name>.age; the age tool must be installed. Only the exported documents are
+    encrypted, not attachments, audio copies, feeds, daily notes or destinations.
+
+    Use --organize-by date to lay files out as YYYY/MM/<file> instead of by Granola
+    folder, --organize-by folder-date to nest YYYY/MM under each folder, or
+    --organize-by tag to use one directory per document tag.
+
+    Use --destination (or [[export.destinations]] in the config file) to write the
+    same documents to further outputs in one run, each in its own format, e.g.
+    --destination hugo:~/site --destination txt:~/Backups/granola.zip.
+
+    Use --destination apple-notes (macOS) to also create and update a note per
+    meeting in Apple Notes, in a folder per Granola folder under a Granola folder
+    (or apple-notes:<folder>). Notes are found again by the Granola ID at their
+    end, so re-running updates them; notes are never deleted.
     """
     from granola.cli.main import state, resolve_path
 
+    try:
+        formatter = get_formatter(export_format)
+    except ValueError as e:
+        console.print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+
+    if transcript_source not in TRANSCRIPT_SOURCES:
+        console.print(
+            f"[red]Error:[/red] Unknown --transcript-source '{transcript_source}'. "
+            f"Choose one of: {', '.join(TRANSCRIPT_SOURCES)}"
+        )
+        raise typer.Exit(1)
+
+    try:
+        title_match = re.compile(match) if match else None
+        title_exclude = re.compile(exclude_match) if exclude_match else None
+    except re.error as e:
+        console.print(f"[red]Error:[/red] Invalid title pattern: {e}")
+        raise typer.Exit(1)
+
+    if sort is not None and sort not in SORT_FIELDS:
+        console.print(
+            f"[red]Error:[/red] Unknown --sort '{sort}'. Choose one of: {', '.join(SORT_FIELDS)}"
+        )
+        raise typer.Exit(1)
+
+    if order is not None and order not in ORDERS:
+        console.print(
+            f"[red]Error:[/red] Unknown --order '{order}'. Choose one of: {', '.join(ORDERS)}"
+        )
+        raise typer.Exit(1)
+
+    archive_before: datetime | None = None
+    if archive_older_than:
+        archive_age = parse_age(archive_older_than)
+        if archive_age is None:
+            console.print(
+                f"[red]Error:[/red] Invalid --archive-older-than '{archive_older_than}'. "
+                "Use a number of days, weeks, months or years, e.g. 90d or 1y"
+            )
+            raise typer.Exit(1)
+        archive_before = datetime.now(timezone.utc) - archive_age
+
+    if (limit is not None and limit < 0) or skip < 0:
+        console.print("[red]Error:[/red] --limit and --skip must not be negative")
+        raise typer.Exit(1)
+
+    if layout not in LAYOUTS:
+        console.print(
+            f"[red]Error:[/red] Unknown --layout '{layout}'. Choose one of: {', '.join(LAYOUTS)}"
+        )
+        raise typer.Exit(1)
+
+    if (section_minutes is not None and section_minutes <= 0) or (
+        section_gap is not None and section_gap <= 0
+    ):
+        console.print("[red]Error:[/red] --section-minutes and --section-gap must be positive")
+        raise typer.Exit(1)
+
+    if organize_by not in ORGANIZE_BY:
+        console.print(
+            f"[red]Error:[/red] Unknown --organize-by '{organize_by}'. "
+            f"Choose one of: {', '.join(ORGANIZE_BY)}"
+        )
+        raise typer.Exit(1)
+
     # 0. Resolve output directory early (needed for sync config)
-    output_dir = resolve_path(output) if output else default_export_output()
+    output_dir = export_output_dir(output)
+    output_spec = output or get_section(state.config, "export").get("output")
+    output_template = split_output_template(output_spec)[1] if output_spec else None
+    if output_template:
+        try:
+            format_output_template(output_template, datetime.now())
+        except ValueError as e:
+            console.print(f"[red]Error:[/red] Invalid output directory: {e}")
+            raise typer.Exit(1)
+    if not output and not output_dir.exists():
+        console.print(
+            f"Exporting to {output_dir} (first run). To export somewhere else, use "
+            f"--output or set output in [export] in {get_default_config_path()}."
+        )
+
+    # 0a. Per-folder output paths (config file, overridden by flags)
+    export_config = get_section(state.config, "export")
+    folder_path_specs = dict(export_config.get("folder_paths", {}))
+    for spec in folder_path or []:
+        name, sep, path = spec.partition("=")
+        if not sep or not name.strip() or not path.strip():
+            console.print(f"[red]Error:[/red] Invalid --folder-path '{spec}', expected Folder=path")
+            raise typer.Exit(1)
+        folder_path_specs[name.strip()] = path.strip()
+    folder_paths: dict[str, Path] = {}
+    for name, path in folder_path_specs.items():
+        resolved = resolve_path(str(path))
+        if resolved:
+            folder_paths[name] = resolved
+            state.logger.info(f"Folder '{name}' -> {resolved}")
+
+    # 0a2. Additional destinations (config file, plus flags)
+    try:
+        destinations = _parse_destinations(
+            export_config.get("destinations", []), destination or [], resolve_path
+        )
+    except ValueError as e:
+        console.print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(1)
+
+    # 0a3. Custom templates (replace the format's renderer, keep its layout).
+    # Templates see the full API documents, which are filled in once fetched.
+    api_documents: dict[str, Document] = {}
+    template_spec = template or export_config.get("template")
+    deep_link = deep_link or export_config.get("deep_link")
+    if deep_link is not None and deep_link not in DEEP_LINKS:
+        console.print(
+            f"[red]Error:[/red] Unknown --deep-link '{deep_link}'. "
+            f"Choose one of: {', '.join(DEEP_LINKS)}"
+        )
+        raise typer.Exit(1)
+    speaker_map = load_speaker_map(speakers)
+    languages = {code.lower() for code in lang} if lang else None
+    unknown_languages = sorted((languages or set()) - set(STOPWORDS))
+    if unknown_languages:
+        console.print(
+            f"[red]Error:[/red] Unknown --lang {', '.join(unknown_languages)}. "
+            f"Choose from: {', '.join(STOPWORDS)}"
+        )
+        raise typer.Exit(1)
+    if reading_wpm is None:
+        reading_wpm = export_config.get("reading_wpm", DEFAULT_READING_WPM)
+    if reading_wpm <= 0:
+        console.print("[red]Error:[/red] --reading-wpm must be positive")
+        raise typer.Exit(1)
+    folder_tag_prefix = export_config.get("folder_tags")
+    if folder_tag_prefix is not None and not isinstance(folder_tag_prefix, str):
+        console.print(
+            '[red]Error:[/red] folder_tags in [export] must be a tag prefix, e.g. "meeting/"'
+        )
+        raise typer.Exit(1)
+    configured_tags = export_config.get("tags", [])
+    if not isinstance(configured_tags, list):
+        console.print('[red]Error:[/red] tags in [export] must be a list, e.g. ["granola"]')
+        raise typer.Exit(1)
+    extra_tags = [str(tag).strip() for tag in [*configured_tags, *(add_tag or [])]]
+    if any(not tag or any(c.isspace() for c in tag) for tag in extra_tags):
+        console.print("[red]Error:[/red] Tags must not be empty or contain spaces")
+        raise typer.Exit(1)
+    daily_notes_spec = daily_notes or export_config.get("daily_notes")
+    daily_notes_dir = resolve_path(daily_notes_spec) if daily_notes_spec else None
+    daily_note_format = daily_note_format or export_config.get(
+        "daily_note_format", DEFAULT_DAILY_NOTE_FORMAT
+    )
+    slug_specs = export_config.get("templates", {})
+    if template_spec or slug_specs:
+        try:
+            renderer: Renderer = formatter.render
+            extension = formatter.extension
+            if template_spec:
+                template_path = _resolve_template(template_spec, resolve_path)
+                renderer = TemplateRenderer.load(template_path, api_documents)
+                extension = template_extension(template_path, extension)
+                state.logger.info(f"Rendering documents with template {template_path}")
+            if slug_specs:
+                if not isinstance(slug_specs, dict):
+                    raise TemplateError("[export.templates] must map template slugs to files")
+                slug_renderers: dict[str, Renderer] = {
+                    str(slug): TemplateRenderer.load(
+                        _resolve_template(path, resolve_path), api_documents
+                    )
+                    for slug, path in slug_specs.items()
+                }
+                renderer = SlugRenderer(slug_renderers, renderer, api_documents)
+                state.logger.info(f"Templates by slug: {', '.join(slug_renderers)}")
+        except TemplateError as e:
+            console.print(f"[red]Error:[/red] {e}")
+            raise typer.Exit(1)
+        formatter = Formatter(
+            name="template",
+            render=renderer,
+            extension=extension,
+            layout=formatter.layout,
+            # A template writing another file type (meeting.md.j2) isn't packaged
+            package=formatter.package if extension == formatter.extension else None,
+        )
+    if attachments and formatter.package:
+        console.print(
+            f"[yellow]Warning:[/yellow] --format {export_format} packs attachments into each "
+            "file; ignoring --attachments"
+        )
+        attachments = False
+
+    # 0a4. Encryption of exported files (flags replace the config file's recipients)
+    configured = export_config.get("encrypt", [])
+    recipients = list(encrypt or []) or (
+        [configured] if isinstance(configured, str) else list(configured)
+    )
+    encryptor: AgeEncryptor | None = None
+    if recipients:
+        try:
+            encryptor = AgeEncryptor(recipients)
+        except EncryptionError as e:
+            console.print(f"[red]Error:[/red] {e}")
+            raise typer.Exit(1)
+        unencrypted = [
+            name
+            for name, used in (
+                ("attachments", attachments),
+                ("audio copies", copy_audio),
+                ("the feed", feed),
+                ("daily notes", daily_notes_dir),
+                ("destinations", destinations),
+            )
+            if used
+        ]
+        if unencrypted:
+            console.print(
+                f"[yellow]Warning:[/yellow] --encrypt only encrypts the exported "
+                f"documents; not encrypted: {', '.join(unencrypted)}"
+            )
+
+    # 0a5. Metrics of the run, written when the command exits however it ends
+    metrics = ExportMetrics()
+    metrics_path = resolve_path(metrics_file or export_config.get("metrics_file"))
+    metrics_push = metrics_push or export_config.get("metrics_push")
+    if (metrics_path or metrics_push) and not dry_run:
+        ctx.call_on_close(_metrics_emitter(metrics, metrics_path, metrics_push, state.logger))
 
     # 0b. Load and merge exclusions from sync folder config
     # This allows exclusions to sync across computers
@@ -391,6 +1218,13 @@ def export_cmd(
     excluded_folders = set(effective_excluded)
     state.logger.info(f"Effective excluded folders: {effective_excluded}")
 
+    # 0c. Folder allow-list (flag overrides, and is saved to, the sync folder config)
+    if include_folder:
+        sync_config.included_folders = list(include_folder)
+    included_folders = set(sync_config.included_folders)
+    if included_folders:
+        state.logger.info(f"Included folders: {sorted(included_folders)}")
+
     # 1. Get supabase path (command option > global state)
     supabase_path = resolve_path(supabase) if supabase else state.supabase
     if not supabase_path:
@@ -398,11 +1232,11 @@ def export_cmd(
             "[red]Error:[/red] supabase.json path not set. "
             "Use --supabase flag, SUPABASE_FILE env, or config file."
         )
-        raise typer.Exit(1)
+        raise typer.Exit(ExitCode.AUTH)
 
     if not supabase_path.exists():
         console.print(f"[red]Error:[/red] supabase.json not found at {supabase_path}")
-        raise typer.Exit(1)
+        raise typer.Exit(ExitCode.AUTH)
 
     # Get access token
     state.logger.info(f"Reading supabase configuration from {supabase_path}")
@@ -410,43 +1244,80 @@ def export_cmd(
         access_token = get_access_token(supabase_path)
     except (AuthError, FileNotFoundError) as e:
         console.print(f"[red]Error:[/red] Failed to read supabase.json: {e}")
-        raise typer.Exit(1)
+        raise typer.Exit(ExitCode.AUTH)
+
+    # 2. Fetch documents from API, while the cache is read in the background (3c)
+    timings = Timings()
+    cache_path = resolve_path(cache) if cache else get_default_cache_path()
+    state.logger.info(f"Reading cache file from {cache_path}")
+    cache_future = _read_cache_in_background(cache_path, strict=strict, timings=timings)
 
-    # 2. Fetch documents from API
     console.print("Fetching documents from Granola API...")
     state.logger.info(f"Fetching documents from Granola API (timeout={timeout}s)")
 
     try:
-        client = GranolaClient(access_token, timeout=timeout)
-        api_docs = client.get_documents()
+        client = GranolaClient(
+            access_token,
+            timeout=timeout,
+            transport=state.http_transport,
+            on_response=metrics.observe_request,
+        )
+        with timings.phase("api fetch"):
+            api_docs = client.get_documents()
     except APIError as e:
         console.print(f"[red]Error:[/red] API request failed: {e}")
-        raise typer.Exit(1)
+        raise typer.Exit(api_error_exit_code(e))
 
     state.logger.info(f"Retrieved {len(api_docs)} documents from API")
+    api_documents.update((doc.id, doc) for doc in api_docs)
+
+    # 3a. Resolve workspace filters (names and slugs -> IDs)
+    workspace_specs = list(workspace or []) + [d.workspace for d in destinations if d.workspace]
+    workspace_lookup: dict[str, str] = {}
+    if workspace_specs:
+        try:
+            with timings.phase("api fetch"):
+                workspaces = client.get_workspaces()
+            workspace_lookup = _resolve_workspaces(workspace_specs, workspaces)
+        except APIError as e:
+            console.print(f"[red]Error:[/red] Failed to fetch workspaces: {e}")
+            raise typer.Exit(api_error_exit_code(e))
+        except ValueError as e:
+            console.print(f"[red]Error:[/red] {e}")
+            raise typer.Exit(1)
 
     # 3b. Fetch folder assignments from API
     api_doc_folders: dict[str, list[str]] = {}
     api_folders: dict[str, str] = {}
     try:
-        api_folders, api_doc_folders = client.get_doc_folder_mapping()
+        with timings.phase("api fetch"):
+            api_folders, api_doc_folders = client.get_doc_folder_mapping()
         state.logger.info(f"Retrieved {len(api_folders)} folders from API, {len(api_doc_folders)} doc-folder mappings")
     except APIError as e:
         state.logger.warning(f"Failed to fetch folder data from API (continuing without folders): {e}")
 
-    # 3c. Read cache for transcripts only (folders now come from API)
-    cache_path = resolve_path(cache) if cache else get_default_cache_path()
-
-    state.logger.info(f"Reading cache file from {cache_path}")
+    # 3c. Wait for the cache, read for transcripts only (folders now come from API)
     cache_data = None
     try:
-        cache_data = read_cache(cache_path)
+        cache_data = cache_future.result()
+    except CacheError as e:
+        console.print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(ExitCode.CACHE)
     except Exception as e:
-        state.logger.warning(f"Failed to read cache file (continuing without transcripts): {e}")
+        if transcript_source == "cache":
+            state.logger.warning(
+                f"Failed to read cache file (continuing without transcripts): {e}"
+            )
+        else:
+            state.logger.info(f"Failed to read cache file: {e}")
+    if cache_data is not None:
+        report_skipped(cache_data)
+    use_api_transcripts = transcript_source == "api" or (
+        transcript_source == "auto" and cache_data is None
+    )
 
     # If no cache data, create empty structure
     if cache_data is None:
-        from granola.cache.reader import CacheData
         cache_data = CacheData(
             documents={},
             transcripts={},
@@ -457,283 +1328,835 @@ def export_cmd(
 
     state.logger.info(f"Loaded cache data: {len(cache_data.transcripts)} transcripts")
 
-    # Helper to get folder names - prefer API data, fall back to cache
-    def get_folder_names(doc_id: str) -> list[str]:
-        if doc_id in api_doc_folders:
-            return api_doc_folders[doc_id]
-        return cache_data.get_folder_names(doc_id)
-
-    # 4. Build export documents by merging API docs with cache data
-    all_doc_ids: set[str] = set()
-    export_docs: list[ExportDoc] = []
+    # 3d. Fetch documents shared with the user (in their folders or the cache) that
+    # get-documents doesn't return
+    shared_ids: set[str] = set()
+    if include_shared:
+        own_ids = {doc.id for doc in api_docs}
+        missing = (set(api_doc_folders) | set(cache_data.shared_documents)) - own_ids
+        try:
+            with timings.phase("api fetch"):
+                shared_docs = client.get_documents_batch(sorted(missing)) if missing else []
+        except APIError as e:
+            console.print(f"[red]Error:[/red] Failed to fetch shared documents: {e}")
+            raise typer.Exit(api_error_exit_code(e))
+        state.logger.info(f"Retrieved {len(shared_docs)} shared documents from API")
+        api_docs.extend(shared_docs)
+        api_documents.update((doc.id, doc) for doc in shared_docs)
+        shared_ids = {doc.id for doc in shared_docs} | set(cache_data.shared_documents)
+    metrics.documents_fetched = len(api_docs)
+
+    # 3e. Fetch the people directory to fill in attendee details
+    people: dict[str, Person] | None = None
+    if attendees:
+        try:
+            with timings.phase("api fetch"):
+                people = {p.email.lower(): p for p in client.get_people() if p.email}
+            state.logger.info(f"Retrieved {len(people)} people from API")
+        except APIError as e:
+            state.logger.warning(
+                f"Failed to fetch people from API (continuing with attendees as recorded): {e}"
+            )
+            people = {}
 
-    for api_doc in api_docs:
-        # Get folder names for this document (from API, not cache)
-        folders = get_folder_names(api_doc.id)
+    # 4. Documents are rendered as they are written (not all held in memory)
+    run_state = RunState.load(output_dir)
+    if force and changed_only:
+        console.print("[red]Error:[/red] --force and --changed-only can't be used together")
+        raise typer.Exit(1)
+    if max_errors is not None and max_errors < 1:
+        console.print("[red]Error:[/red] --max-errors must be at least 1")
+        raise typer.Exit(1)
+    if touch_only and (force or changed_only):
+        console.print(
+            "[red]Error:[/red] --touch-only can't be used with --force or --changed-only"
+        )
+        raise typer.Exit(1)
+    if dry_run and touch_only:
+        console.print("[red]Error:[/red] --dry-run and --touch-only can't be used together")
+        raise typer.Exit(1)
+    if changed_only:
+        state.logger.info(f"Exporting documents changed since the last run ({run_state.root})")
+    source = ExportSource(
+        api_docs=api_docs,
+        cache_data=cache_data,
+        api_doc_folders=api_doc_folders,
+        excluded=excluded_folders,
+        included=included_folders,
+        # Nested folders are written under their parents (hierarchy comes from the cache)
+        folder_parents=cache_data.get_folder_parents(),
+        workspace_ids={workspace_lookup[w] for w in workspace} if workspace else None,
+        shared_ids=shared_ids,
+        people=people,
+        deep_link=deep_link,
+        wikilinks=wikilinks,
+        ai_qa=ai_qa,
+        stats=doc_stats,
+        reading_wpm=reading_wpm,
+        detect_lang=detect_lang,
+        languages=languages,
+        speakers=speaker_map,
+        layout=layout,
+        section_minutes=section_minutes,
+        section_gap=section_gap,
+        limit=limit,
+        skip=skip,
+        sort=sort,
+        order=order,
+        title_match=title_match,
+        title_exclude=title_exclude,
+        skip_untitled=skip_untitled,
+        folder_tag_prefix=folder_tag_prefix,
+        extra_tags=list(dict.fromkeys(extra_tags)),
+        changed_since=run_state if changed_only else None,
+        merge=merge,
+        merged=Manifest.load(output_dir).merged if merge else {},
+        archived_before=archive_before,
+        archived=archived_doc_ids(output_dir) if archive_before else set(),
+        skip_empty=skip_empty,
+        min_words=min_words,
+        timings=timings,
+    )
 
-        # Skip if document is in any excluded folder
-        if excluded_folders and any(f in excluded_folders for f in folders):
-            state.logger.debug(f"Skipping document '{api_doc.title}' - in excluded folder")
-            continue
+    # 4b. Extra data is fetched only for the documents about to be exported (see
+    # ExportSource.prepare): transcripts from the API instead of the cache, every
+    # panel rather than just the last viewed one, and local audio recordings
+    if use_api_transcripts:
+        cache_data.transcripts = {}
+
+    def prepare(doc_ids: list[str]) -> None:
+        if use_api_transcripts:
+            # Documents whose transcript can't be fetched are exported without one
+            console.print(f"Fetching {len(doc_ids)} transcripts from Granola API...")
+            with timings.phase("api fetch"):
+                transcripts = client.get_transcripts(doc_ids)
+            cache_data.transcripts.update(transcripts)
+            state.logger.info(f"Retrieved {len(transcripts)} transcripts from API")
+
+        if all_panels:
+            # Documents whose panels can't be fetched are exported with the last viewed one
+            console.print(f"Fetching panels of {len(doc_ids)} documents from Granola API...")
+            with timings.phase("api fetch"):
+                panels = client.get_document_panels(doc_ids)
+            source.panels.update(panels)
+            state.logger.info(f"Retrieved panels of {len(panels)} documents from API")
+
+        if audio or copy_audio:
+            recordings = {
+                doc_id: Path(cache_data.documents[doc_id].audio_path or "").expanduser()
+                for doc_id in doc_ids
+                if doc_id in cache_data.documents and cache_data.documents[doc_id].audio_path
+            }
+            recordings = {doc_id: path for doc_id, path in recordings.items() if path.is_file()}
+            if copy_audio and not dry_run:
+                copies = copy_recordings(recordings, output_dir, state.logger)
+                source.audio.update((doc_id, path.as_posix()) for doc_id, path in copies.items())
+            else:
+                source.audio.update((doc_id, str(path)) for doc_id, path in recordings.items())
+            state.logger.info(f"Found audio recordings of {len(recordings)} documents")
+
+    if use_api_transcripts or all_panels or audio or copy_audio:
+        source.prepare = prepare
+
+    # 5. Prepare webhooks for documents with notes that are added or updated
+    webhook_configs = []
+    if webhook:
+        for w in webhook:
+            try:
+                webhook_configs.append(json.loads(w))
+            except json.JSONDecodeError as e:
+                state.logger.warning(f"Invalid webhook config: {e}")
 
-        all_doc_ids.add(api_doc.id)
+    dispatcher = (
+        WebhookDispatcher(webhook_configs, logger=state.logger) if webhook_configs else None
+    )
+    webhook_results = []
 
-        # Get transcript segments
-        segments = cache_data.transcripts.get(api_doc.id, [])
+    def on_result(result: SyncResult) -> None:
+        if not dispatcher:
+            return
 
-        # Get notes content (convert ProseMirror to Markdown)
-        notes_content = _get_notes_content(api_doc)
+        # Files moved between folders have no new content to announce
+        if result.action == "moved":
+            return
 
-        # Skip documents with no notes and no transcript
-        has_notes = notes_content and notes_content.strip()
-        has_transcript = len(segments) > 0
-        if not has_notes and not has_transcript:
-            state.logger.debug(f"Skipping document '{api_doc.title}' - no notes or transcript")
-            continue
+        # Only send webhooks for documents with notes content
+        if not result.doc.has_notes:
+            state.logger.debug(f"Skipping webhook for '{result.doc.title}' - no notes content")
+            return
 
-        # Format the combined content
-        content = format_combined(
-            title=api_doc.title,
-            doc_id=api_doc.id,
-            created_at=api_doc.created_at,
-            updated_at=api_doc.updated_at,
-            notes_content=notes_content,
-            segments=segments,
-            folders=folders,
-        )
+        # Dispatch to all configured webhooks
+        webhook_results.extend(dispatcher.dispatch(_webhook_payload(result)))
 
-        # Format transcript separately for webhooks
-        transcript_text = format_transcript(segments) if segments else ""
+    # 6. Sync to filesystem (passing exclusions to delete excluded folders)
+    if dry_run:
+        console.print(f"Planning sync to {output_dir} (dry run)...")
+    else:
+        console.print(f"Syncing documents to {output_dir}...")
+    state.logger.info(f"Starting sync to {output_dir}")
 
-        # Parse created_at timestamp
-        try:
-            ts = api_doc.created_at.replace("Z", "+00:00")
-            created_at = datetime.fromisoformat(ts)
-            if created_at.tzinfo is None:
-                created_at = created_at.replace(tzinfo=timezone.utc)
-        except ValueError:
-            created_at = datetime.now(timezone.utc)
-
-        # Parse updated_at timestamp
-        try:
-            ts = api_doc.updated_at.replace("Z", "+00:00")
-            updated_at = datetime.fromisoformat(ts)
-            if updated_at.tzinfo is None:
-                updated_at = updated_at.replace(tzinfo=timezone.utc)
-        except ValueError:
-            updated_at = datetime.now(timezone.utc)
-
-        export_docs.append(ExportDoc(
-            id=api_doc.id,
-            title=api_doc.title,
-            created_at=created_at,
-            updated_at=updated_at,
-            content=content,
-            folders=folders,
-            has_notes=has_notes,
-            has_transcript=has_transcript,
-            notes_content=notes_content or "",
-            transcript_content=transcript_text,
-        ))
-
-    # 4b. Process shared documents from cache
-    state.logger.info(f"Processing {len(cache_data.shared_documents)} shared documents")
-    for shared_doc in cache_data.shared_documents.values():
-        # Skip if we already have this document from the API
-        if shared_doc.id in all_doc_ids:
-            continue
+    sync_writer = SyncWriter(
+        output_dir,
+        logger=state.logger,
+        excluded_folders=list(excluded_folders),
+        included_folders=list(included_folders),
+        extension=formatter.extension,
+        layout=formatter.layout,
+        zettel=zettel,
+        organize_by=organize_by,
+        folder_paths=folder_paths,
+        folder_parents=source.folder_parents,
+        timings=timings,
+        content_filter=(
+            Attachments(
+                output_dir, timeout=timeout, transport=state.http_transport, logger=state.logger
+            )
+            if attachments and not dry_run
+            else None
+        ),
+        encode=_encoder(compress, encryptor, formatter.package),
+        encoded_suffix=(GZIP_SUFFIX if compress else "") + (AGE_SUFFIX if encryptor else ""),
+        archive_before=archive_before,
+        subdir_template=output_template,
+        force=force,
+        dry_run=dry_run,
+    )
 
-        # Get folder names for this document (from API, not cache)
-        folders = get_folder_names(shared_doc.id)
+    # 6a. Dry run: plan the sync without the lock, which would be written too
+    if dry_run:
+        _plan_sync(sync_writer, source, formatter, no_prune, state.logger)
+        return
 
-        # Skip if document is in any excluded folder
-        if excluded_folders and any(f in excluded_folders for f in folders):
-            state.logger.debug(f"Skipping shared document '{shared_doc.title}' - in excluded folder")
-            continue
+    # Hold the output folder lock while writing, so runs can't interleave
+    lock = _acquire_lock(output_dir, wait)
 
-        all_doc_ids.add(shared_doc.id)
+    if touch_only:
+        try:
+            touched = sync_writer.touch(source.iter_docs(None, state.logger))
+        finally:
+            lock.release()
+        console.print(f"[green]✓[/green] Touched {len(touched)} files (nothing written)")
+        state.logger.info(f"Touch-only export to {output_dir}: {len(touched)} files touched")
+        metrics.finished = True
+        return
+
+    # Documents handed to the writer, with the updated time they were rendered at
+    exported: dict[str, str] = {}
+
+    def track(docs: Iterator[ExportDoc]) -> Iterator[ExportDoc]:
+        for doc in docs:
+            exported[doc.id] = doc.updated_at.isoformat()
+            yield doc
+
+    # Documents that failed to render or write, counted as they fail (--max-errors)
+    failure_count = 0
+
+    def on_failure(failure: SyncFailure) -> None:
+        nonlocal failure_count
+        failure_count += 1
+        if max_errors is not None and failure_count == max_errors:
+            state.logger.warning(f"{failure_count} documents failed; stopping the export")
+            sync_writer.request_stop()
+
+    def on_render_error(failure: SyncFailure) -> None:
+        failures.append(failure)
+        on_failure(failure)
 
-        # Get transcript segments (shared docs may have transcripts in cache)
-        segments = cache_data.transcripts.get(shared_doc.id, [])
+    try:
+        failures: list[SyncFailure] = []
+        try:
+            with _stop_on_interrupt(sync_writer):
+                stats, _ = sync_writer.sync(
+                    track(
+                        source.iter_docs(
+                            formatter.render,
+                            state.logger,
+                            on_error=on_render_error,
+                            stop=lambda: max_errors is not None and failure_count >= max_errors,
+                        )
+                    ),
+                    source.doc_ids(),
+                    outline=source.iter_docs(None, state.logger),
+                    on_result=on_result,
+                    prune=not no_prune,
+                    on_failure=on_failure,
+                )
+        except Exception as e:
+            console.print(f"[red]Error:[/red] Sync failed: {e}")
+            raise typer.Exit(1)
+        failures.extend(sync_writer.failures)
+        metrics.files = {
+            action: getattr(stats, action) for action in SYNC_ACTIONS if action != "failed"
+        }
+        metrics.errors = len(failures)
+
+        # 6b. Save sync config to sync folder
+        save_sync_config(output_dir, sync_config)
+
+        # Stopped by --max-errors or Ctrl-C: report what was done and stop
+        if sync_writer.interrupted:
+            _report_stopped(stats, failures, max_errors, state.logger)
+
+        # 6b2. Remember what was exported, for --changed-only (failed documents are
+        # tried again next time)
+        _record_exported(run_state, exported, failures, state.logger)
+
+        # 6c. Write Atom feed of exported meetings
+        if feed:
+            _write_feed(source, sync_writer, state.logger)
+
+        # 6d. Link meetings from daily notes
+        if daily_notes_dir:
+            _link_daily_notes(source, sync_writer, daily_notes_dir, daily_note_format, state.logger)
+
+        # 7. Print results (a table of changes per folder on a terminal)
+        print_summary(console, stats, len(source.empty), failures, timings)
+        state.logger.info(
+            f"Export completed: added={stats.added}, updated={stats.updated}, "
+            f"moved={stats.moved}, deleted={stats.deleted}, skipped={stats.skipped}, "
+            f"empty={len(source.empty)}, failed={len(failures)}"
+        )
+        for line in folder_lines(stats):
+            state.logger.info(f"  {line}")
 
-        # Get notes content from shared doc
-        notes_content = _get_shared_notes_content(shared_doc)
+        # 7b. Write additional destinations from the same fetched data
+        _write_destinations(
+            destinations, source, workspace_lookup, failures, state.logger, force=force
+        )
+    finally:
+        lock.release()
+
+    # 8. Print webhook summary
+    if dispatcher and webhook_results:
+        summary = dispatcher.get_summary(webhook_results)
+        console.print(f"[blue]ℹ[/blue] {summary}")
+        state.logger.info(summary)
+
+    # 8b. Print time spent per phase (already in the summary on a terminal), and
+    # the requests, size and time per API endpoint
+    if show_timings:
+        _print_timings(timings, client, state.logger)
+
+    # 8c. Write the JSON report of the run
+    if json_report_file:
+        _write_json_report(
+            resolve_path(json_report_file),
+            json_report(output_dir, stats, len(source.empty), failures, timings),
+            state.logger,
+        )
 
-        # Skip documents with no notes and no transcript
-        has_notes = notes_content and notes_content.strip()
-        has_transcript = len(segments) > 0
-        if not has_notes and not has_transcript:
-            state.logger.debug(f"Skipping shared document '{shared_doc.title}' - no notes or transcript")
-            continue
+    metrics.finished = True
+    metrics.errors = len(failures)
 
-        # Format the combined content
-        content = format_combined(
-            title=shared_doc.title,
-            doc_id=shared_doc.id,
-            created_at=shared_doc.created_at,
-            updated_at=shared_doc.updated_at,
-            notes_content=notes_content,
-            segments=segments,
-            folders=folders,
-        )
+    # 9. Report documents that failed; everything else has been exported
+    if failures:
+        console.print(f"\n[red]✗[/red] {len(failures)} document(s) failed to export:")
+        for failure in failures:
+            console.print(f"  - {_format_failure(failure)}", markup=False)
+        raise typer.Exit(ExitCode.PARTIAL)
 
-        # Format transcript separately for webhooks
-        transcript_text = format_transcript(segments) if segments else ""
 
-        # Parse created_at timestamp
-        try:
-            ts = shared_doc.created_at.replace("Z", "+00:00")
-            created_at = datetime.fromisoformat(ts)
-            if created_at.tzinfo is None:
-                created_at = created_at.replace(tzinfo=timezone.utc)
-        except ValueError:
-            created_at = datetime.now(timezone.utc)
-
-        # Parse updated_at timestamp
-        try:
-            ts = shared_doc.updated_at.replace("Z", "+00:00")
-            updated_at = datetime.fromisoformat(ts)
-            if updated_at.tzinfo is None:
-                updated_at = updated_at.replace(tzinfo=timezone.utc)
-        except ValueError:
-            updated_at = datetime.now(timezone.utc)
-
-        export_docs.append(ExportDoc(
-            id=shared_doc.id,
-            title=shared_doc.title,
-            created_at=created_at,
-            updated_at=updated_at,
-            content=content,
-            folders=folders,
-            has_notes=has_notes,
-            has_transcript=has_transcript,
-            notes_content=notes_content or "",
-            transcript_content=transcript_text,
-        ))
-
-    # 5. Sync to output directory
-    console.print(f"Syncing {len(export_docs)} documents to {output_dir}...")
-    state.logger.info(f"Starting sync to {output_dir}, {len(export_docs)} documents")
+def _plan_sync(
+    sync_writer: SyncWriter,
+    source: ExportSource,
+    formatter: Formatter,
+    no_prune: bool,
+    logger: logging.Logger,
+) -> None:
+    """Plan a sync without writing anything (--dry-run), and print the plan.
 
-    # 6. Sync to filesystem (passing exclusions to delete excluded folders)
-    sync_writer = SyncWriter(output_dir, logger=state.logger, excluded_folders=list(excluded_folders))
+    Raises typer.Exit with PARTIAL if any document would fail to export.
+    """
+    failures: list[SyncFailure] = []
     try:
-        stats, results = sync_writer.sync(export_docs, all_doc_ids)
+        with _stop_on_interrupt(sync_writer):
+            stats, _ = sync_writer.sync(
+                source.iter_docs(formatter.render, logger, on_error=failures.append),
+                source.doc_ids(),
+                outline=source.iter_docs(None, logger),
+                prune=not no_prune,
+            )
     except Exception as e:
         console.print(f"[red]Error:[/red] Sync failed: {e}")
         raise typer.Exit(1)
+    failures.extend(sync_writer.failures)
+    print_plan(console, sync_writer.plan, sync_writer.output_dir)
+    console.print(
+        f"[yellow]Dry run[/yellow] (nothing written): "
+        f"{summary_line(stats, len(source.empty), failures)}"
+    )
+    logger.info(f"Dry run of export to {sync_writer.output_dir}: {len(sync_writer.plan)} changes")
+    for failure in failures:
+        console.print(f"  - {_format_failure(failure)}", markup=False)
+    if failures:
+        raise typer.Exit(ExitCode.PARTIAL)
+
 
-    # 6b. Save sync config to sync folder
-    save_sync_config(output_dir, sync_config)
+def _acquire_lock(output_dir: Path, wait: bool) -> ExportLock:
+    """Lock the output folder, waiting for another run to finish with wait (--wait).
+
+    Raises typer.Exit with LOCKED if it is locked and wait isn't set.
+    """
+    lock = ExportLock(output_dir)
+    try:
+        lock.acquire()
+    except LockError as e:
+        if not wait:
+            console.print(f"[red]Error:[/red] {e}. Use --wait to wait for it to finish.")
+            raise typer.Exit(ExitCode.LOCKED)
+        console.print(f"{e}; waiting for it to finish...")
+        lock.acquire(wait=True)
+    return lock
+
+
+def _report_stopped(
+    stats: SyncStats,
+    failures: list[SyncFailure],
+    max_errors: Optional[int],
+    logger: logging.Logger,
+) -> None:
+    """Report a sync stopped by --max-errors or Ctrl-C, and exit.
+
+    The manifest is already saved, but orphaned files weren't deleted.
+    """
+    # Stopped by --max-errors: report the failures and stop like on Ctrl-C
+    if max_errors is not None and len(failures) >= max_errors:
+        console.print(
+            f"[red]Export stopped[/red] after {len(failures)} documents failed "
+            f"(--max-errors {max_errors}): {stats.added} added, {stats.updated} updated, "
+            f"{stats.moved} moved; orphaned files were not deleted"
+        )
+        for failure in failures:
+            console.print(f"  - {_format_failure(failure)}", markup=False)
+        logger.info(f"Export stopped after {len(failures)} failed documents")
+        raise typer.Exit(ExitCode.TOO_MANY_ERRORS)
 
-    # 7. Print results
     console.print(
-        f"[green]✓[/green] Export completed: "
+        f"[yellow]Export interrupted:[/yellow] "
         f"{stats.added} added, {stats.updated} updated, "
-        f"{stats.moved} moved, {stats.deleted} deleted, {stats.skipped} skipped"
+        f"{stats.moved} moved, {stats.deleted} deleted, {stats.skipped} skipped; "
+        f"orphaned files were not deleted"
     )
-    state.logger.info(
-        f"Export completed: added={stats.added}, updated={stats.updated}, "
+    logger.info(
+        f"Export interrupted: added={stats.added}, updated={stats.updated}, "
         f"moved={stats.moved}, deleted={stats.deleted}, skipped={stats.skipped}"
     )
+    raise typer.Exit(ExitCode.INTERRUPTED)
 
-    # 8. Dispatch webhooks for documents with notes that were added or updated
-    webhook_configs = []
-    if webhook:
-        for w in webhook:
-            try:
-                webhook_configs.append(json.loads(w))
-            except json.JSONDecodeError as e:
-                state.logger.warning(f"Invalid webhook config: {e}")
 
-    if webhook_configs:
-        dispatcher = WebhookDispatcher(webhook_configs, logger=state.logger)
-        webhook_results = []
+def _record_exported(
+    run_state: RunState,
+    exported: dict[str, str],
+    failures: list[SyncFailure],
+    logger: logging.Logger,
+) -> None:
+    """Save the updated times of the exported documents, for --changed-only.
 
-        for result in results:
-            # Only send webhooks for documents with notes content
-            if not result.doc.has_notes:
-                state.logger.debug(
-                    f"Skipping webhook for '{result.doc.title}' - no notes content"
-                )
+    Documents that failed aren't recorded, so they are tried again next time.
+    """
+    for failure in failures:
+        exported.pop(failure.doc_id, None)
+    for doc_id, updated_at in exported.items():
+        run_state.record(doc_id, updated_at)
+    if not run_state.save():
+        logger.warning(f"Failed to save export state to {run_state.root}")
+
+
+def _write_feed(source: ExportSource, sync_writer: SyncWriter, logger: logging.Logger) -> None:
+    """Write an Atom feed of the exported meetings to the output folder (--feed)."""
+    output_dir = sync_writer.output_dir
+    with sync_writer.timings.phase("feed"):
+        entries: list[FeedEntry] = []
+        for doc in source.iter_docs(None, logger):
+            paths = sync_writer.get_target_paths(doc)
+            if not paths:
                 continue
+            entries.append(FeedEntry(
+                id=doc.id,
+                title=doc.title,
+                created_at=doc.created_at,
+                updated_at=doc.updated_at,
+                summary=make_summary(doc.notes_content),
+                file_path=Path(os.path.relpath(paths[0], output_dir)),
+            ))
+        try:
+            feed_path = write_atom_feed(output_dir, entries)
+            logger.info(f"Wrote Atom feed with {len(entries)} entries to {feed_path}")
+        except OSError as e:
+            logger.warning(f"Failed to write Atom feed: {e}")
+
+
+def _link_daily_notes(
+    source: ExportSource,
+    sync_writer: SyncWriter,
+    daily_notes_dir: Path,
+    daily_note_format: str,
+    logger: logging.Logger,
+) -> None:
+    """Link the exported meetings from the daily notes of their days (--daily-notes)."""
+    entries: list[DailyNoteEntry] = []
+    for doc in source.iter_docs(None, logger):
+        paths = sync_writer.get_target_paths(doc)
+        if paths:
+            entries.append(DailyNoteEntry(doc.title, doc.local_start, paths[0]))
+    try:
+        changed = link_daily_notes(
+            entries,
+            daily_notes_dir,
+            daily_note_format,
+            vault=find_obsidian_vault(daily_notes_dir),
+        )
+        logger.info(f"Linked meetings from {changed} daily notes")
+    except OSError as e:
+        logger.warning(f"Failed to update daily notes: {e}")
+
+
+def _write_destinations(
+    destinations: list[Destination],
+    source: ExportSource,
+    workspace_lookup: dict[str, str],
+    failures: list[SyncFailure],
+    logger: logging.Logger,
+    force: bool = False,
+) -> None:
+    """Write the additional destinations (--also) from the same fetched documents.
 
-            # Build webhook payload
-            payload = WebhookPayload.create(
-                event=f"document.{result.action}",
-                doc_id=result.doc.id,
-                title=result.doc.title or "",
-                created_at=result.doc.created_at.isoformat(),
-                updated_at=result.doc.updated_at.isoformat(),
-                folders=result.doc.folders,
-                file_path=str(result.file_path),
-                markdown_content=result.doc.content,
-                notes_content=result.doc.notes_content,
-                transcript_content=result.doc.transcript_content,
-                has_notes=result.doc.has_notes,
-                has_transcript=result.doc.has_transcript,
-            )
+    Documents that fail are appended to failures; a destination that can't be
+    written at all raises typer.Exit.
+    """
+    for dest in destinations:
+        # Destinations have no state of their own, so they are always written in full
+        dest_source = replace(source, changed_since=None)
+        if dest.workspace:
+            dest_source = replace(dest_source, workspace_ids={workspace_lookup[dest.workspace]})
+        try:
+            summary = _write_destination(dest, dest_source, logger, failures, force=force)
+        except Exception as e:
+            console.print(f"[red]Error:[/red] Failed to write {dest.path}: {e}")
+            raise typer.Exit(1)
+        console.print(f"[green]✓[/green] {dest.path} ({dest.format}): {summary}")
+        logger.info(f"Destination {dest.path} ({dest.format}): {summary}")
+
+
+def _print_timings(timings: Timings, client: GranolaClient, logger: logging.Logger) -> None:
+    """Print the time spent per phase and the requests per API endpoint (--timings).
 
-            # Dispatch to all configured webhooks
-            webhook_results.extend(dispatcher.dispatch(payload))
+    On a terminal, the phases are already in the summary table.
+    """
+    if not console.is_terminal:
+        console.print("Timings:")
+        for line in timings.format():
+            console.print(f"  {line}")
+    print_request_stats(console, client.request_stats)
+    logger.info(f"Export timings: {timings.phases}")
+    logger.info(f"API requests: {client.request_stats}")
 
-        # Print webhook summary
-        if webhook_results:
-            summary = dispatcher.get_summary(webhook_results)
-            console.print(f"[blue]ℹ[/blue] {summary}")
-            state.logger.info(summary)
 
+def _write_json_report(report_path: Path, report: dict, logger: logging.Logger) -> None:
+    """Write the JSON report of the run (--json-report), raising typer.Exit on failure."""
+    try:
+        report_path.parent.mkdir(parents=True, exist_ok=True)
+        report_path.write_text(json.dumps(report, indent=2, ensure_ascii=False) + "\n")
+    except OSError as e:
+        console.print(f"[red]Error:[/red] Failed to write {report_path}: {e}")
+        raise typer.Exit(1)
+    logger.info(f"Wrote JSON report to {report_path}")
 
-def _get_notes_content(doc: Document) -> str | None:
-    """Extract Granola AI-generated notes from an API document.
 
-    Priority:
-    1. Notes (ProseMirror) - converted to Markdown (if non-empty)
-    2. LastViewedPanel.Content (ProseMirror) - converted to Markdown
-    3. LastViewedPanel.OriginalContent (HTML)
-    4. Content (raw)
+def _read_cache_in_background(
+    cache_path: Path, strict: bool = False, timings: Timings | None = None
+) -> Future[CacheData]:
+    """Start reading the cache file in a background thread, to overlap the API fetch.
 
-    Note: notes_plain is human-written notes, not Granola AI notes.
+    The cache can be hundreds of megabytes, and parsing it takes about as long as
+    fetching the documents. The thread is a daemon, so an export that fails before
+    the cache is needed exits without waiting for it.
+
+    Returns:
+        A future of the cache data, or of the exception reading it raised.
     """
-    # Try Notes (ProseMirror) - Granola AI-generated notes
-    if doc.notes:
-        content = to_markdown(doc.notes)
-        if content and content.strip():
-            return content
+    future: Future[CacheData] = Future()
+    # Run in a copy of this context, so the cache parse span is traced under the command
+    context = contextvars.copy_context()
 
-    # Try LastViewedPanel.Content (ProseMirror) - also AI-generated
-    if doc.last_viewed_panel and doc.last_viewed_panel.content:
-        return to_markdown(doc.last_viewed_panel.content)
+    def read() -> None:
+        try:
+            if timings is None:
+                data = read_cache(cache_path, strict=strict)
+            else:
+                with timings.phase("cache parse"):
+                    data = read_cache(cache_path, strict=strict)
+        except Exception as e:
+            future.set_exception(e)
+            return
+        future.set_result(data)
+
+    threading.Thread(target=context.run, args=(read,), name="cache-read", daemon=True).start()
+    return future
+
+
+@contextmanager
+def _stop_on_interrupt(writer: SyncWriter) -> Iterator[None]:
+    """Make the first Ctrl-C stop a sync after the current file; a second one aborts.
+
+    Signal handlers can only be installed from the main thread, so elsewhere
+    (e.g. the menubar's worker thread) this does nothing.
+    """
+    if threading.current_thread() is not threading.main_thread():
+        yield
+        return
 
-    # Try LastViewedPanel.OriginalContent (HTML - return as-is)
-    if doc.last_viewed_panel and doc.last_viewed_panel.original_content:
-        return doc.last_viewed_panel.original_content
+    def handle_interrupt(signum: int, frame: object) -> None:
+        console.print("\n[yellow]Interrupted[/yellow] - finishing the current file...")
+        writer.request_stop()
+        signal.signal(signal.SIGINT, signal.default_int_handler)
 
-    # Fallback to Content field
-    return doc.content
+    previous = signal.signal(signal.SIGINT, handle_interrupt)
+    try:
+        yield
+    finally:
+        signal.signal(signal.SIGINT, previous)
 
 
-def _get_shared_notes_content(shared_doc: SharedDocument) -> str | None:
-    """Extract notes content from a shared document in the cache.
+def _metrics_emitter(
+    metrics: ExportMetrics,
+    path: Optional[Path],
+    push_url: Optional[str],
+    logger: logging.Logger,
+) -> Callable[[], None]:
+    """Return a function that writes and pushes the run's metrics, warning on failure."""
 
-    Priority:
-    1. notes_markdown - AI-generated notes already in markdown
-    2. last_viewed_panel.content - ProseMirror content
-    """
-    # Try notes_markdown first
-    if shared_doc.notes_markdown and shared_doc.notes_markdown.strip():
-        return shared_doc.notes_markdown
-
-    # Try last_viewed_panel.content (stored as raw dict in cache)
-    if shared_doc.last_viewed_panel:
-        lvp = shared_doc.last_viewed_panel
-        content_data = lvp.get("content")
-        if content_data:
-            # Parse as ProseMirrorDoc and convert to markdown
+    def emit() -> None:
+        if path:
+            try:
+                write_textfile(metrics, path)
+                logger.info(f"Wrote metrics to {path}")
+            except OSError as e:
+                console.print(f"[yellow]Warning:[/yellow] Failed to write metrics to {path}: {e}")
+        if push_url:
             try:
-                pm_doc = ProseMirrorDoc.model_validate(content_data)
-                return to_markdown(pm_doc)
-            except Exception:
-                pass
+                push(metrics, push_url)
+                logger.info(f"Pushed metrics to {push_url}")
+            except httpx.HTTPError as e:
+                console.print(f"[yellow]Warning:[/yellow] Failed to push metrics: {e}")
+
+    return emit
+
+
+def _parse_destinations(
+    config_entries: list[dict],
+    specs: list[str],
+    resolve_path: Callable[[str], Optional[Path]],
+) -> list[Destination]:
+    """Build destinations from [[export.destinations]] tables and --destination flags.
+
+    Args:
+        config_entries: Tables with path and optional format, organize_by, zettel,
+            workspace.
+        specs: Flag values of the form "format:path".
+        resolve_path: Expands ~ and environment variables in paths.
 
-    return None
+    Returns:
+        List of destinations.
+
+    Raises:
+        ValueError: If an entry is malformed or names an unknown format or layout.
+    """
+    entries = list(config_entries)
+    for spec in specs:
+        if spec.strip() == APPLE_NOTES_FORMAT:
+            entries.append({"format": APPLE_NOTES_FORMAT})
+            continue
+        fmt, sep, path = spec.partition(":")
+        if not sep or not fmt.strip() or not path.strip():
+            raise ValueError(f"Invalid --destination '{spec}', expected format:path")
+        entries.append({"format": fmt.strip(), "path": path.strip()})
+
+    destinations: list[Destination] = []
+    for entry in entries:
+        if isinstance(entry, dict) and entry.get("format") == APPLE_NOTES_FORMAT:
+            if sys.platform != "darwin":
+                raise ValueError("apple-notes destinations require macOS")
+            # The path names a Notes folder, not a file
+            folder = str(entry.get("path") or DEFAULT_NOTES_FOLDER)
+            workspace = str(entry["workspace"]) if entry.get("workspace") else None
+            destinations.append(
+                Destination(path=Path(folder), format=APPLE_NOTES_FORMAT, workspace=workspace)
+            )
+            continue
+        if not isinstance(entry, dict) or not entry.get("path"):
+            raise ValueError(f"Destination is missing a path: {entry!r}")
+        path = resolve_path(str(entry["path"]))
+        if path is None:
+            raise ValueError(f"Invalid destination path: {entry['path']!r}")
+
+        dest = Destination(
+            path=path,
+            format=str(entry.get("format", "txt")),
+            organize_by=str(entry.get("organize_by", "folder")),
+            zettel=bool(entry.get("zettel", False)),
+            workspace=str(entry["workspace"]) if entry.get("workspace") else None,
+        )
+        try:
+            get_formatter(dest.format)
+        except ValueError as e:
+            raise ValueError(f"{e} (destination {path})") from None
+        if dest.organize_by not in ORGANIZE_BY:
+            raise ValueError(
+                f"Unknown organize_by '{dest.organize_by}' for destination {path}. "
+                f"Choose one of: {', '.join(ORGANIZE_BY)}"
+            )
+        destinations.append(dest)
+
+    return destinations
+
+
+def _resolve_workspaces(specs: list[str], workspaces: list[Workspace]) -> dict[str, str]:
+    """Map workspace IDs, names, or slugs to workspace IDs.
+
+    Raises:
+        ValueError: If a value matches no workspace.
+    """
+    resolved: dict[str, str] = {}
+    for spec in specs:
+        match = next((w for w in workspaces if w.matches(spec)), None)
+        if match is None:
+            available = ", ".join(w.name or w.id for w in workspaces) or "none"
+            raise ValueError(f"Unknown workspace '{spec}'. Available workspaces: {available}")
+        resolved[spec] = match.id
+    return resolved
+
+
+def _resolve_template(spec: object, resolve_path: Callable[[str], Optional[Path]]) -> Path:
+    """Resolve a template path from a flag or config value.
+
+    Raises:
+        TemplateError: If the path is invalid.
+    """
+    path = resolve_path(str(spec))
+    if path is None:
+        raise TemplateError(f"Invalid template path: {spec}")
+    return path
+
+
+def _write_destination(
+    dest: Destination,
+    source: ExportSource,
+    logger: logging.Logger,
+    failures: list[SyncFailure],
+    force: bool = False,
+) -> str:
+    """Render documents in a destination's format and write them out.
+
+    Documents that fail to render or write are appended to failures. With force,
+    every file (or note) is rewritten.
+
+    Returns:
+        Human-readable summary of what was written.
+    """
+    if dest.format == APPLE_NOTES_FORMAT:
+        notes_writer = AppleNotesWriter(
+            str(dest.path), logger=logger, folder_parents=source.folder_parents, force=force
+        )
+        with source.timings.phase("write"):
+            stats = notes_writer.sync(
+                source.iter_docs(format_apple_notes, logger, on_error=failures.append)
+            )
+        failures.extend(notes_writer.failures)
+        return (
+            f"{stats.added} added, {stats.updated} updated, {stats.skipped} skipped"
+            + (f", {stats.failed} failed" if stats.failed else "")
+        )
+
+    formatter = get_formatter(dest.format)
+    writer = SyncWriter(
+        Path() if dest.is_archive else dest.path,
+        logger=logger,
+        excluded_folders=list(source.excluded),
+        included_folders=list(source.included),
+        extension=formatter.extension,
+        layout=formatter.layout,
+        zettel=dest.zettel,
+        organize_by=dest.organize_by,
+        folder_parents=source.folder_parents,
+        timings=source.timings,
+        encode=formatter.package,
+        force=force,
+    )
+
+    if dest.is_archive:
+        # Lay files out relative to the archive root
+        package = formatter.package
+        files = (
+            (path, package(doc.content) if package else doc.content)
+            for doc in source.iter_docs(formatter.render, logger, on_error=failures.append)
+            for path in writer.get_target_paths(doc)
+        )
+        with source.timings.phase("write"):
+            if is_tar_archive(dest.path):
+                count = write_tar_archive(dest.path, files)
+            else:
+                count = write_zip_archive(dest.path, files)
+        return f"{count} files archived"
+
+    stats, _ = writer.sync(
+        source.iter_docs(formatter.render, logger, on_error=failures.append),
+        source.doc_ids(),
+        outline=source.iter_docs(None, logger),
+    )
+    failures.extend(writer.failures)
+    return (
+        f"{stats.added} added, {stats.updated} updated, "
+        f"{stats.moved} moved, {stats.deleted} deleted, {stats.skipped} skipped"
+        + (f", {stats.failed} failed" if stats.failed else "")
+    )
+
+
+def _encoder(
+    compress: bool,
+    encryptor: AgeEncryptor | None,
+    package: Callable[[str], bytes] | None = None,
+) -> Callable[[str], bytes] | None:
+    """Return the function exported files are encoded with.
+
+    Content is packaged (for package formats), then compressed, then encrypted.
+    """
+    if package is None:
+        if compress and encryptor:
+            return lambda content: encryptor.encrypt(gzip_content(content))
+        if compress:
+            return gzip_content
+        return encryptor
+    if not (compress or encryptor):
+        return package
+
+    def encode(content: str) -> bytes:
+        data = package(content)
+        if compress:
+            data = gzip_bytes(data)
+        return encryptor.encrypt(data) if encryptor else data
+
+    return encode
+
+
+def _format_failure(failure: SyncFailure) -> str:
+    """Format a failure for the end-of-run report."""
+    return f"{failure.title or 'Untitled'} ({failure.doc_id}): {failure.error}"
+
+
+def _webhook_payload(result: SyncResult) -> WebhookPayload:
+    """Build the webhook payload for a sync result."""
+    return WebhookPayload.create(
+        event=f"document.{result.action}",
+        doc_id=result.doc.id,
+        title=result.doc.title or "",
+        created_at=result.doc.created_at.isoformat(),
+        updated_at=result.doc.updated_at.isoformat(),
+        folders=result.doc.folders,
+        file_path=str(result.file_path),
+        markdown_content=result.doc.content,
+        notes_content=result.doc.notes_content,
+        transcript_content=result.doc.transcript_content,
+        has_notes=result.doc.has_notes,
+        has_transcript=result.doc.has_transcript,
+    )
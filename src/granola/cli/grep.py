@@ -0,0 +1,119 @@
+"""Grep command: search notes and transcripts in the local cache."""
+
+import re
+from dataclasses import dataclass
+from typing import Annotated, Optional
+
+import typer
+from rich.console import Console
+from rich.markup import escape
+
+from granola.cache.reader import TranscriptSegment, get_default_cache_path, read_cache
+from granola.cli.exit_codes import ExitCode
+from granola.formatters.combined import format_transcript
+
+console = Console()
+
+
+@dataclass
+class _Searchable:
+    """A document's text as it's searched."""
+
+    title: str
+    created_at: str
+    notes: str
+    segments: list[TranscriptSegment]
+
+
+def grep_cmd(
+    pattern: Annotated[
+        str,
+        typer.Argument(help="Text to search for"),
+    ],
+    cache: Annotated[
+        Optional[str],
+        typer.Option("--cache", help="Path to Granola cache file"),
+    ] = None,
+    ignore_case: Annotated[
+        bool,
+        typer.Option("--ignore-case", "-i", help="Match regardless of case"),
+    ] = False,
+    regex: Annotated[
+        bool,
+        typer.Option("--regex", "-E", help="Treat the pattern as a regular expression"),
+    ] = False,
+) -> None:
+    """Search meeting notes and transcripts for text ("when did we discuss X?").
+
+    Reads the local Granola cache directly, so there's nothing to index first.
+    Matching lines are printed with the meeting's title and date, and transcript
+    lines with their time, oldest meeting first.
+    """
+    from granola.cli.main import state, resolve_path
+
+    try:
+        matcher = re.compile(
+            pattern if regex else re.escape(pattern), re.IGNORECASE if ignore_case else 0
+        )
+    except re.error as e:
+        console.print(f"[red]Error:[/red] Invalid pattern: {e}")
+        raise typer.Exit(1)
+
+    cache_path = resolve_path(cache) if cache else get_default_cache_path()
+    if not cache_path.exists():
+        console.print(f"[red]Error:[/red] Cache file not found at {cache_path}")
+        raise typer.Exit(ExitCode.CACHE)
+
+    state.logger.info(f"Reading Granola cache file from {cache_path}")
+    try:
+        cache_data = read_cache(cache_path)
+    except Exception as e:
+        console.print(f"[red]Error:[/red] Failed to read cache file: {e}")
+        raise typer.Exit(ExitCode.CACHE)
+
+    docs: dict[str, _Searchable] = {}
+    for doc in cache_data.documents.values():
+        docs[doc.id] = _Searchable(doc.title, doc.created_at, doc.notes_markdown or "", [])
+    for shared in cache_data.shared_documents.values():
+        if shared.id not in docs:
+            docs[shared.id] = _Searchable(
+                shared.title, shared.created_at, shared.notes_markdown or "", []
+            )
+    for doc_id, segments in cache_data.transcripts.items():
+        if doc_id in docs:
+            docs[doc_id].segments = segments
+        else:
+            docs[doc_id] = _Searchable(doc_id, "", "", segments)
+
+    matches = 0
+    for doc in sorted(docs.values(), key=lambda d: d.created_at):
+        heading = f"[bold]{escape(doc.title or 'Untitled')}[/bold] [dim]{doc.created_at[:10]}[/dim]"
+        for line in doc.notes.splitlines():
+            if matcher.search(line):
+                console.print(f"{heading} [cyan](notes)[/cyan] {_highlight(line, matcher)}")
+                matches += 1
+        if doc.segments:
+            # The formatted lines carry the time and speaker ("[HH:MM:SS] You: ...")
+            for line in format_transcript(doc.segments).splitlines():
+                if matcher.search(line.split(": ", 1)[-1]):
+                    console.print(f"{heading} {_highlight(line, matcher)}")
+                    matches += 1
+
+    if not matches:
+        console.print("No matches.")
+        return
+    state.logger.info(f"{matches} matching lines")
+
+
+def _highlight(line: str, matcher: re.Pattern[str]) -> str:
+    """Escape a line for Rich markup, highlighting the matches."""
+    parts: list[str] = []
+    end = 0
+    for m in matcher.finditer(line):
+        if m.start() == m.end():
+            continue
+        parts.append(escape(line[end : m.start()]))
+        parts.append(f"[bold yellow]{escape(m.group())}[/bold yellow]")
+        end = m.end()
+    parts.append(escape(line[end:]))
+    return "".join(parts).strip()
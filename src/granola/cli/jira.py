@@ -0,0 +1,173 @@
+"""Jira command: file tickets for action items marked in meeting notes."""
+
+import re
+from datetime import datetime
+from typing import Annotated, Optional
+
+import typer
+from rich.console import Console
+
+from granola.api.models import DEEP_LINKS, Document
+from granola.cli.book import period_option
+from granola.cli.completion import complete_folders
+from granola.cli.documents import fetch_documents_with_folders
+from granola.cli.exit_codes import ExitCode
+from granola.config.file import get_section
+from granola.jira import JiraClient, JiraError
+from granola.jira.client import GRANOLA_LABEL, item_label
+from granola.source import get_notes_content, is_filtered_out
+from granola.tasks.ledger import item_key
+from granola.utils.dates import parse_timestamp
+from granola.utils.outcomes import MarkedLine, find_marked_lines
+
+console = Console()
+
+# Lines filed as tickets by default: "JIRA: Fix the login timeout"
+DEFAULT_PATTERN = r"^JIRA\s*:\s*(.+)$"
+
+
+def jira_cmd(
+    url: Annotated[
+        Optional[str],
+        typer.Option("--url", envvar="JIRA_URL", help="Jira base URL"),
+    ] = None,
+    project: Annotated[
+        Optional[str],
+        typer.Option("--project", envvar="JIRA_PROJECT", help="Key of the Jira project"),
+    ] = None,
+    user: Annotated[
+        Optional[str],
+        typer.Option("--user", envvar="JIRA_USER", help="Jira account email (Jira Cloud)"),
+    ] = None,
+    token: Annotated[
+        Optional[str],
+        typer.Option("--token", envvar="JIRA_TOKEN", help="Jira API or personal access token"),
+    ] = None,
+    pattern: Annotated[
+        Optional[str],
+        typer.Option("--pattern", help="Regex of the lines to file (default: lines starting JIRA:)"),
+    ] = None,
+    issue_type: Annotated[
+        Optional[str],
+        typer.Option("--issue-type", help="Type of the tickets filed (default: Task)"),
+    ] = None,
+    since: Annotated[
+        Optional[str],
+        typer.Option("--since", help="Only meetings from this year, month or day on (2024-01)"),
+    ] = None,
+    folder: Annotated[
+        Optional[list[str]],
+        typer.Option(
+            "--folder",
+            help="Only meetings in this folder (can be used multiple times)",
+            autocompletion=complete_folders,
+        ),
+    ] = None,
+    timeout: Annotated[
+        int,
+        typer.Option("--timeout", help="HTTP timeout in seconds"),
+    ] = 120,
+) -> None:
+    """File Jira tickets for the action items marked in meeting notes.
+
+    Each line of the notes matching --pattern (by default, lines starting
+    "JIRA:") becomes a ticket, summarized by the rest of the line (or the
+    pattern's first group). The description gives the meeting context: title,
+    date, attendees, the section of the notes the line is in, and a link to
+    the meeting.
+
+    Settings can also come from the [jira] table of the config file (url,
+    project, user, token, pattern, issue_type). Tickets are labelled with a key
+    of their meeting and line, so re-running the command doesn't file them again.
+    """
+    from granola.cli.main import state
+
+    jira_config = get_section(state.config, "jira")
+    url = url or jira_config.get("url")
+    project = project or jira_config.get("project")
+    user = user or jira_config.get("user")
+    token = token or jira_config.get("token")
+    issue_type = issue_type or jira_config.get("issue_type", "Task")
+    pattern_spec = pattern or jira_config.get("pattern", DEFAULT_PATTERN)
+
+    missing = [name for name, value in (("--url", url), ("--project", project)) if not value]
+    if missing:
+        console.print(
+            f"[red]Error:[/red] {' and '.join(missing)} not set. "
+            "Use the flags, JIRA_URL/JIRA_PROJECT env, or [jira] in the config file."
+        )
+        raise typer.Exit(ExitCode.ERROR)
+    if not token:
+        console.print(
+            "[red]Error:[/red] Jira token not set. "
+            "Use --token, JIRA_TOKEN env, or token in [jira] in the config file."
+        )
+        raise typer.Exit(ExitCode.AUTH)
+
+    try:
+        # Notes are free text, so "jira:" matches as well as "JIRA:"
+        marker = re.compile(str(pattern_spec), re.IGNORECASE)
+    except re.error as e:
+        console.print(f"[red]Error:[/red] Invalid --pattern '{pattern_spec}': {e}")
+        raise typer.Exit(ExitCode.ERROR)
+
+    start = period_option("--since", since)[0] if since else None
+
+    documents, doc_folders, folder_parents = fetch_documents_with_folders(timeout)
+    wanted_folders = set(folder or [])
+
+    jira = JiraClient(str(url), str(project), str(token), user=user, logger=state.logger)
+    created = skipped = 0
+
+    try:
+        for doc in sorted(documents, key=lambda d: d.created_at):
+            meeting_start = parse_timestamp(doc.created_at)
+            if meeting_start is None or (start and meeting_start < start):
+                continue
+            folders = doc_folders.get(doc.id, [])
+            if is_filtered_out(folders, set(), wanted_folders, folder_parents):
+                continue
+
+            for marked in find_marked_lines(get_notes_content(doc), marker):
+                label = item_label(item_key(doc.id, marked.text))
+                existing = jira.find_issue_by_label(label)
+                if existing:
+                    state.logger.debug(f"Skipping '{marked.text}' - already filed as {existing}")
+                    skipped += 1
+                    continue
+                key = jira.create_issue(
+                    marked.text,
+                    _description(doc, meeting_start, folders, marked),
+                    issue_type=str(issue_type),
+                    labels=[GRANOLA_LABEL, label],
+                )
+                console.print(f"  {key}: {marked.text} ({jira.issue_url(key)})")
+                created += 1
+    except JiraError as e:
+        console.print(f"[red]Error:[/red] {e}")
+        if e.status_code in (401, 403):
+            raise typer.Exit(ExitCode.AUTH)
+        raise typer.Exit(ExitCode.NETWORK)
+    finally:
+        jira.close()
+
+    console.print(
+        f"[green]✓[/green] Jira filing completed: {created} created, {skipped} already filed"
+    )
+    state.logger.info(f"Jira filing completed: created={created}, skipped={skipped}")
+
+
+def _description(
+    doc: Document, meeting_start: datetime, folders: list[str], marked: MarkedLine
+) -> str:
+    """Build a ticket description (Jira wiki markup) giving the meeting context."""
+    rows = [
+        ("Meeting", doc.title or "Untitled"),
+        ("Date", meeting_start.strftime("%Y-%m-%d %H:%M")),
+        ("Attendees", ", ".join(p.name or p.email for p in doc.attendees)),
+        ("Folders", ", ".join(folders)),
+        ("Section", marked.section or ""),
+        ("Granola", DEEP_LINKS["web"].format(id=doc.id)),
+    ]
+    lines = [f"*{name}:* {value}" for name, value in rows if value]
+    return "\n".join([f"bq. {marked.line}", "", *lines])
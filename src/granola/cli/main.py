@@ -3,13 +3,27 @@
 import logging
 import sys
 from pathlib import Path
-from typing import Annotated, Optional
+from typing import Annotated, Any, Callable, Optional
 
+import httpx
 import typer
 from dotenv import load_dotenv
 from rich.console import Console
 
 from granola import __version__
+from granola.config.defaults import (
+    executable_dir,
+    find_portable_root,
+    portable_root,
+    set_portable_root,
+)
+from granola.config.file import (
+    ConfigError,
+    get_default_config_path,
+    get_section,
+    load_config_file,
+)
+from granola.utils.dates import load_date_formats, set_date_formats
 
 # Create the Typer app
 app = typer.Typer(
@@ -25,7 +39,10 @@ console = Console()
 class State:
     debug: bool = False
     supabase: Optional[Path] = None
+    config: dict[str, Any] = {}
     logger: logging.Logger = logging.getLogger("granola")
+    # Transport for API requests (set by --record / --replay)
+    http_transport: Optional[httpx.BaseTransport] = None
 
 
 state = State()
@@ -53,7 +70,11 @@ def setup_logging(debug: bool) -> logging.Logger:
 
 
 def resolve_path(input_path: Optional[str]) -> Optional[Path]:
-    """Expand ~ and environment variables in paths."""
+    """Expand ~ and environment variables in paths.
+
+    In portable mode, relative paths are relative to the portable folder rather
+    than the working directory.
+    """
     if not input_path:
         return None
 
@@ -64,6 +85,9 @@ def resolve_path(input_path: Optional[str]) -> Optional[Path]:
 
     path_str = os.path.expandvars(path_str)
     path = Path(path_str).expanduser()
+    root = portable_root()
+    if root and not path.is_absolute():
+        path = root / path
     return path.resolve()
 
 
@@ -74,8 +98,24 @@ def version_callback(value: bool) -> None:
         raise typer.Exit()
 
 
+def start_profiling(path: Path) -> Callable[[], None]:
+    """Start cProfile and return a function that stops it and writes the stats."""
+    import cProfile
+
+    profiler = cProfile.Profile()
+    profiler.enable()
+
+    def stop() -> None:
+        profiler.disable()
+        profiler.dump_stats(path)
+        console.print(f"Profile written to {path} (view with: python -m pstats {path})")
+
+    return stop
+
+
 @app.callback()
 def main(
+    ctx: typer.Context,
     debug: Annotated[
         bool,
         typer.Option("--debug", help="Enable debug logging"),
@@ -88,6 +128,31 @@ def main(
         Optional[str],
         typer.Option("--config", help="Path to config file"),
     ] = None,
+    profile: Annotated[
+        Optional[str],
+        typer.Option("--profile", help="Write cProfile stats for the command to this file"),
+    ] = None,
+    record: Annotated[
+        Optional[str],
+        typer.Option("--record", help="Save API responses as fixtures in this directory"),
+    ] = None,
+    replay: Annotated[
+        Optional[str],
+        typer.Option("--replay", help="Answer API requests from fixtures in this directory"),
+    ] = None,
+    portable: Annotated[
+        bool,
+        typer.Option(
+            "--portable",
+            help="Keep config and default paths next to the granola executable",
+        ),
+    ] = False,
+    trace: Annotated[
+        Optional[str],
+        typer.Option(
+            "--trace", help="Send OpenTelemetry traces of the command to this OTLP/HTTP endpoint"
+        ),
+    ] = None,
     version: Annotated[
         Optional[bool],
         typer.Option("--version", callback=version_callback, is_eager=True),
@@ -101,6 +166,13 @@ def main(
     state.debug = debug
     state.logger = setup_logging(debug)
 
+    # Portable mode: config, default paths and relative paths are in the folder of
+    # the executable (turned on by --portable or a portable.toml next to it)
+    set_portable_root(executable_dir() if portable else find_portable_root())
+    if portable_root():
+        load_dotenv(portable_root() / ".env")
+        state.logger.info(f"Portable mode: paths are relative to {portable_root()}")
+
     # Handle supabase path from flag, env, or config
     import os
     if supabase:
@@ -108,20 +180,127 @@ def main(
     elif os.environ.get("SUPABASE_FILE"):
         state.supabase = resolve_path(os.environ.get("SUPABASE_FILE"))
 
+    # Load config file from flag, env, or default location (optional)
+    config_path = resolve_path(config or os.environ.get("GRANOLA_CONFIG"))
+    if config_path is None and get_default_config_path().exists():
+        config_path = get_default_config_path()
+    if config_path:
+        try:
+            state.config = load_config_file(config_path)
+        except ConfigError as e:
+            console.print(f"[red]Error:[/red] {e}")
+            raise typer.Exit(1)
+
+    # Date and time formats of headers, transcripts and file names
+    dates_config = get_section(state.config, "dates")
+    if dates_config:
+        try:
+            set_date_formats(load_date_formats(dates_config))
+        except ValueError as e:
+            console.print(f"[red]Error:[/red] {e}")
+            raise typer.Exit(1)
+
+    # Record or replay API traffic (fixtures are redacted JSON files)
+    if record and replay:
+        console.print("[red]Error:[/red] --record and --replay can't be used together")
+        raise typer.Exit(1)
+    record_dir = resolve_path(record)
+    replay_dir = resolve_path(replay)
+    if record_dir:
+        from granola.api.recording import RecordingTransport
+
+        state.http_transport = RecordingTransport(record_dir)
+        state.logger.info(f"Recording API responses to {record_dir}")
+    elif replay_dir:
+        from granola.api.recording import ReplayTransport
+
+        if not replay_dir.is_dir():
+            console.print(f"[red]Error:[/red] Fixtures directory not found: {replay_dir}")
+            raise typer.Exit(1)
+        state.http_transport = ReplayTransport(replay_dir)
+        state.logger.info(f"Replaying API responses from {replay_dir}")
+
+    # Profile the whole subcommand; stats are written when it finishes
+    profile_path = resolve_path(profile)
+    if profile_path:
+        ctx.call_on_close(start_profiling(profile_path))
+
+    # Trace the subcommand and its phases (config: [tracing] endpoint)
+    trace_endpoint = trace or get_section(state.config, "tracing").get("endpoint")
+    if trace_endpoint:
+        from granola.utils.tracing import TracingError, span, start_tracing
+
+        try:
+            ctx.call_on_close(start_tracing(str(trace_endpoint)))
+        except TracingError as e:
+            console.print(f"[red]Error:[/red] {e}")
+            raise typer.Exit(1)
+        # Closed before the callback above (LIFO), so the span is flushed with the rest
+        ctx.with_resource(span(f"granola {ctx.invoked_subcommand}"))
+        state.logger.info(f"Tracing to {trace_endpoint}")
+
     if state.debug:
         state.logger.debug(f"Debug mode enabled")
         if state.supabase:
             state.logger.debug(f"Supabase file: {state.supabase}")
+        if config_path:
+            state.logger.debug(f"Config file: {config_path}")
 
 
 # Import and register subcommands
 from granola.cli.notes import notes_cmd
 from granola.cli.transcripts import transcripts_cmd
 from granola.cli.export import export_cmd
+from granola.cli.calendar import calendar_cmd
+from granola.cli.confluence import confluence_cmd
+from granola.cli.workspaces import workspaces_cmd
+from granola.cli.api import api_app, dump_cmd
+from granola.cli.cache import backup_cmd, cache_app
+from granola.cli.push import push_cmd
+from granola.cli.grep import grep_cmd
+from granola.cli.verify import verify_cmd
+from granola.cli.book import book_cmd
+from granola.cli.bear import bear_cmd
+from granola.cli.anki import anki_cmd
+from granola.cli.tasks import tasks_cmd
+from granola.cli.jira import jira_cmd
+from granola.cli.match import match_cmd
+from granola.cli.report import report_app, time_cmd
+from granola.cli.stats import people_cmd, stats_app
+from granola.cli.dashboard import dashboard_cmd
+from granola.cli.clean import clean_cmd
+from granola.cli.version import version_cmd
 
 app.command(name="notes")(notes_cmd)
 app.command(name="transcripts")(transcripts_cmd)
 app.command(name="export")(export_cmd)
+app.command(name="calendar")(calendar_cmd)
+app.command(name="confluence")(confluence_cmd)
+app.command(name="workspaces")(workspaces_cmd)
+app.command(name="push")(push_cmd)
+app.command(name="grep")(grep_cmd)
+app.command(name="verify")(verify_cmd)
+app.command(name="book")(book_cmd)
+app.command(name="bear")(bear_cmd)
+app.command(name="anki")(anki_cmd)
+app.command(name="tasks")(tasks_cmd)
+app.command(name="jira")(jira_cmd)
+app.command(name="match")(match_cmd)
+app.command(name="dashboard")(dashboard_cmd)
+app.command(name="clean")(clean_cmd)
+app.command(name="version")(version_cmd)
+
+api_app.command(name="dump")(dump_cmd)
+app.add_typer(api_app, name="api")
+
+cache_app.command(name="backup")(backup_cmd)
+app.add_typer(cache_app, name="cache")
+
+report_app.command(name="time")(time_cmd)
+app.add_typer(report_app, name="report")
+
+stats_app.command(name="people")(people_cmd)
+app.add_typer(stats_app, name="stats")
 
 
 if __name__ == "__main__":
@@ -0,0 +1,97 @@
+"""Match command: reconcile exported notes with a calendar export (.ics)."""
+
+import os
+from pathlib import Path
+from typing import Annotated, Optional
+
+import typer
+from rich.console import Console
+
+from granola.cli.book import period_option
+from granola.cli.exit_codes import ExitCode
+from granola.formatters.ical import parse_ical
+from granola.writers.calendar_match import match_events
+from granola.writers.manifest import MANIFEST_FILENAME
+
+console = Console()
+
+
+def match_cmd(
+    ics: Annotated[
+        str,
+        typer.Option("--ics", help="Calendar export (.ics file) to match the notes with"),
+    ],
+    output: Annotated[
+        Optional[str],
+        typer.Option("--output", help="Export output directory to check"),
+    ] = None,
+    since: Annotated[
+        Optional[str],
+        typer.Option("--since", help="Only events from this year, month or day on (2024-01)"),
+    ] = None,
+    until: Annotated[
+        Optional[str],
+        typer.Option("--until", help="Only events up to the end of this year, month or day"),
+    ] = None,
+) -> None:
+    """Match exported notes with the events of a calendar, listing meetings without notes.
+
+    Exported notes record their calendar event's iCalendar UID (event_uid) when
+    Granola's calendar data has it; this matches those with the events of a
+    calendar app's .ics export. Past events with no notes are listed, as are
+    notes whose event isn't in the calendar. Exits with code 7 if any meeting
+    has no notes.
+
+    Recurring events share one UID, so a series counts as having notes if any of
+    its meetings has. Compressed and encrypted exports can't be matched.
+    """
+    from granola.cli.export import export_output_dir
+    from granola.cli.main import state, resolve_path
+
+    start = period_option("--since", since)[0] if since else None
+    end = period_option("--until", until)[1] if until else None
+
+    ics_path = resolve_path(ics)
+    if ics_path is None:
+        console.print("[red]Error:[/red] --ics must be a file path")
+        raise typer.Exit(ExitCode.ERROR)
+    try:
+        events = parse_ical(ics_path.read_text(encoding="utf-8", errors="replace"))
+    except OSError as e:
+        console.print(f"[red]Error:[/red] Failed to read {ics_path}: {e}")
+        raise typer.Exit(ExitCode.ERROR)
+
+    output_dir = export_output_dir(output)
+    if not (output_dir / MANIFEST_FILENAME).is_file():
+        console.print(
+            f"[red]Error:[/red] No manifest in {output_dir}; is it a granola export folder?"
+        )
+        raise typer.Exit(ExitCode.ERROR)
+
+    report = match_events(events, output_dir, start=start, end=end)
+
+    for event in report.missing:
+        when = event.start.astimezone().strftime("%Y-%m-%d %H:%M")
+        series = " (recurring)" if event.recurring else ""
+        console.print(f"  no notes: {when}  {event.title or 'Untitled'}{series}", markup=False)
+    for uid, paths in sorted(report.unlisted.items()):
+        for path in paths:
+            console.print(f"  not in calendar: {_display(path, output_dir)}", markup=False)
+            state.logger.info(f"Event {uid} of {path} is not in {ics_path}")
+    if report.unlinked:
+        state.logger.info(f"{report.unlinked} exported files record no calendar event")
+
+    summary = (
+        f"{len(report.matched)} events with notes, {len(report.missing)} without, "
+        f"{sum(len(paths) for paths in report.unlisted.values())} notes not in the calendar"
+    )
+    if not report.missing:
+        console.print(f"[green]✓[/green] Every meeting in {ics_path.name} has notes: {summary}")
+        return
+    console.print(f"[yellow]✗[/yellow] Meetings without notes: {summary}")
+    raise typer.Exit(ExitCode.OUT_OF_SYNC)
+
+
+def _display(path: Path, output_dir: Path) -> str:
+    """Return a path relative to the output directory if it is inside it."""
+    return os.path.relpath(path, output_dir) if path.is_relative_to(output_dir) else str(path)
@@ -1,5 +1,6 @@
 """Notes export command."""
 
+import re
 from pathlib import Path
 from typing import Annotated, Optional
 
@@ -8,15 +9,31 @@ from rich.console import Console
 
 from granola.api.auth import AuthError, get_access_token
 from granola.api.client import APIError, GranolaClient
+from granola.api.models import Document
+from granola.cli.exit_codes import ExitCode, api_error_exit_code
+from granola.config.defaults import NOTES_SUBDIR
+from granola.config.file import get_default_config_path, get_section
 from granola.formatters.markdown import to_markdown_file
+from granola.formatters.registry import Formatter, formatter_names, get_formatter
+from granola.source import get_notes_content
+from granola.utils.selection import ORDERS, SORT_FIELDS, page, sort_documents, title_selected
 from granola.writers.file_writer import write_documents
+from granola.writers.sync_writer import SyncFailure
 
 console = Console()
 
 
 def default_notes_output() -> Path:
-    """Return the default output directory for notes."""
-    return Path.home() / "My Drive" / "z. Granola Notes" / "Markdown"
+    """Return the output directory used without --output.
+
+    That's output in [notes] in the config file, or else Markdown/ in the export's
+    default output.
+    """
+    from granola.cli.export import default_export_output
+    from granola.cli.main import state, resolve_path
+
+    configured = resolve_path(get_section(state.config, "notes").get("output"))
+    return configured or default_export_output() / NOTES_SUBDIR
 
 
 def notes_cmd(
@@ -28,10 +45,125 @@ def notes_cmd(
         Optional[str],
         typer.Option("--output", help="Output directory for exported Markdown files"),
     ] = None,
+    zettel: Annotated[
+        bool,
+        typer.Option(
+            "--zettel",
+            help="Prefix filenames with a zettel ID (YYYYMMDDHHMM) and use it as the frontmatter id",
+        ),
+    ] = False,
+    export_format: Annotated[
+        Optional[str],
+        typer.Option(
+            "--format",
+            help=f"Render with an export format ({', '.join(formatter_names())}) "
+            "instead of Markdown with frontmatter",
+        ),
+    ] = None,
+    limit: Annotated[
+        Optional[int],
+        typer.Option("--limit", help="Only write the N most recently updated documents"),
+    ] = None,
+    skip: Annotated[
+        int,
+        typer.Option("--skip", help="Skip the first N documents (most recently updated, unless --sort)"),
+    ] = 0,
+    sort: Annotated[
+        Optional[str],
+        typer.Option(
+            "--sort",
+            help=f"Process documents ordered by: {', '.join(SORT_FIELDS)} (default: as fetched)",
+        ),
+    ] = None,
+    order: Annotated[
+        Optional[str],
+        typer.Option(
+            "--order",
+            help="Sort direction: asc or desc (default: desc, or asc for title)",
+        ),
+    ] = None,
+    match: Annotated[
+        Optional[str],
+        typer.Option("--match", help="Only include documents whose title matches this regex"),
+    ] = None,
+    exclude_match: Annotated[
+        Optional[str],
+        typer.Option("--exclude-match", help="Skip documents whose title matches this regex"),
+    ] = None,
+    skip_untitled: Annotated[
+        bool,
+        typer.Option("--skip-untitled", help="Skip documents without a title"),
+    ] = False,
+    force: Annotated[
+        bool,
+        typer.Option("--force", help="Rewrite every file, even if its document is unchanged"),
+    ] = False,
 ) -> None:
-    """Export Granola notes to Markdown files."""
+    """Export Granola notes to Markdown files.
+
+    With --format, each note is rendered by that export format instead (without
+    transcripts, which come from the local cache) and written flat to the output
+    directory.
+
+    Use --limit and --skip to write only some of the most recently updated notes,
+    and --match and --exclude-match to select notes by title (regular expressions).
+    Use --skip-untitled to leave out notes without a title.
+
+    Files are only rewritten if their note was updated since; use --force to
+    rewrite them all (e.g. after changing --format or --zettel).
+    """
     from granola.cli.main import state, resolve_path
 
+    try:
+        title_match = re.compile(match) if match else None
+        title_exclude = re.compile(exclude_match) if exclude_match else None
+    except re.error as e:
+        console.print(f"[red]Error:[/red] Invalid title pattern: {e}")
+        raise typer.Exit(1)
+
+    if sort is not None and sort not in SORT_FIELDS:
+        console.print(
+            f"[red]Error:[/red] Unknown --sort '{sort}'. Choose one of: {', '.join(SORT_FIELDS)}"
+        )
+        raise typer.Exit(1)
+
+    if order is not None and order not in ORDERS:
+        console.print(
+            f"[red]Error:[/red] Unknown --order '{order}'. Choose one of: {', '.join(ORDERS)}"
+        )
+        raise typer.Exit(1)
+
+    if (limit is not None and limit < 0) or skip < 0:
+        console.print("[red]Error:[/red] --limit and --skip must not be negative")
+        raise typer.Exit(1)
+
+    formatter: Formatter | None = None
+    if export_format:
+        try:
+            formatter = get_formatter(export_format)
+        except ValueError as e:
+            console.print(f"[red]Error:[/red] {e}")
+            raise typer.Exit(1)
+        if formatter.package:
+            console.print(
+                f"[red]Error:[/red] --format {export_format} writes packages, not text; "
+                f"use granola export --format {export_format}"
+            )
+            raise typer.Exit(1)
+
+    def converter(doc: Document) -> str:
+        if formatter is None:
+            return to_markdown_file(doc, zettel=zettel)
+        return formatter.render(
+            title=doc.title or "",
+            doc_id=doc.id,
+            created_at=doc.created_at,
+            updated_at=doc.updated_at,
+            notes_content=get_notes_content(doc) or "",
+            segments=[],
+            folders=[],
+        )
+
     # Get supabase path
     supabase_path = state.supabase
     if not supabase_path:
@@ -39,11 +171,11 @@ def notes_cmd(
             "[red]Error:[/red] supabase.json path not set. "
             "Use --supabase flag, SUPABASE_FILE env, or config file."
         )
-        raise typer.Exit(1)
+        raise typer.Exit(ExitCode.AUTH)
 
     if not supabase_path.exists():
         console.print(f"[red]Error:[/red] supabase.json not found at {supabase_path}")
-        raise typer.Exit(1)
+        raise typer.Exit(ExitCode.AUTH)
 
     # Get access token
     state.logger.info(f"Reading supabase configuration from {supabase_path}")
@@ -51,38 +183,65 @@ def notes_cmd(
         access_token = get_access_token(supabase_path)
     except (AuthError, FileNotFoundError) as e:
         console.print(f"[red]Error:[/red] Failed to read supabase.json: {e}")
-        raise typer.Exit(1)
+        raise typer.Exit(ExitCode.AUTH)
 
     # Fetch documents from API
     console.print("Fetching documents from Granola API...")
     state.logger.info(f"Fetching documents from Granola API (timeout={timeout}s)")
 
     try:
-        client = GranolaClient(access_token, timeout=timeout)
+        client = GranolaClient(access_token, timeout=timeout, transport=state.http_transport)
         documents = client.get_documents()
     except APIError as e:
         console.print(f"[red]Error:[/red] API request failed: {e}")
-        raise typer.Exit(1)
+        raise typer.Exit(api_error_exit_code(e))
 
     state.logger.info(f"Retrieved {len(documents)} documents")
 
+    documents = [
+        d for d in documents if title_selected(d.title, title_match, title_exclude, skip_untitled)
+    ]
+    if sort or limit is not None or skip:
+        documents = page(sort_documents(documents, sort or "updated", order), limit, skip)
+
     # Resolve output directory
     output_dir = resolve_path(output) if output else default_notes_output()
+    if not output and not output_dir.exists():
+        console.print(
+            f"Writing notes to {output_dir} (first run). To write them somewhere else, use "
+            f"--output or set output in [notes] in {get_default_config_path()}."
+        )
 
     console.print(f"Exporting {len(documents)} notes to {output_dir}...")
     state.logger.info(f"Writing documents to Markdown files in {output_dir}")
 
-    # Write documents
+    # Write documents; one that fails to write shouldn't abort the rest
+    failures: list[SyncFailure] = []
+
+    def on_error(failure: SyncFailure) -> None:
+        console.print(f"[red]Error:[/red] Failed to write '{failure.title}': {failure.error}")
+        failures.append(failure)
+
     try:
         written = write_documents(
             documents,
             output_dir,
-            converter=to_markdown_file,
-            extension=".md",
+            converter=converter,
+            extension=formatter.extension if formatter else ".md",
+            zettel=zettel,
+            force=force,
+            on_error=on_error,
         )
     except Exception as e:
         console.print(f"[red]Error:[/red] Failed to write files: {e}")
         raise typer.Exit(1)
 
+    if failures:
+        console.print(
+            f"[red]✗[/red] {written} files written, {len(failures)} note(s) failed to write"
+        )
+        state.logger.info(f"Export finished, {written} files written, {len(failures)} failed")
+        raise typer.Exit(ExitCode.PARTIAL)
+
     console.print(f"[green]✓[/green] Export completed successfully ({written} files written)")
     state.logger.info(f"Export completed successfully, {written} files written")
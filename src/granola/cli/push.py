@@ -0,0 +1,142 @@
+"""Push command: update a document's notes from a local Markdown file."""
+
+from typing import Annotated
+
+import typer
+from rich.console import Console
+
+from granola.api.auth import AuthError, get_access_token
+from granola.api.client import APIError, GranolaClient
+from granola.api.models import ProseMirrorDoc, ProseMirrorNode
+from granola.cli.completion import complete_document_ids
+from granola.cli.exit_codes import ExitCode, api_error_exit_code
+from granola.prosemirror.converter import from_markdown, to_json, to_markdown, to_plain_text
+
+console = Console()
+
+
+def push_cmd(
+    file: Annotated[
+        str,
+        typer.Argument(help="Markdown file with the new notes"),
+    ],
+    doc_id: Annotated[
+        str,
+        typer.Option(
+            "--id", help="ID of the document to update", autocompletion=complete_document_ids
+        ),
+    ],
+    append: Annotated[
+        bool,
+        typer.Option("--append", help="Add to the existing notes instead of replacing them"),
+    ] = False,
+    dry_run: Annotated[
+        bool,
+        typer.Option("--dry-run", help="Show the resulting notes structure without updating"),
+    ] = False,
+    timeout: Annotated[
+        int,
+        typer.Option("--timeout", help="HTTP timeout in seconds"),
+    ] = 120,
+) -> None:
+    """Replace (or append to) a document's notes with the contents of a Markdown file.
+
+    The Markdown is converted to Granola's editor format: headings, paragraphs, and
+    bullet and numbered lists are kept; other formatting is pushed as literal text.
+    Use --dry-run to see the resulting structure first.
+    """
+    from granola.cli.main import state, resolve_path
+
+    path = resolve_path(file)
+    try:
+        markdown = path.read_text(encoding="utf-8")
+    except OSError as e:
+        console.print(f"[red]Error:[/red] Failed to read {path}: {e}")
+        raise typer.Exit(1)
+
+    notes = from_markdown(markdown)
+    if not notes.content:
+        console.print(f"[red]Error:[/red] {path} has no content to push")
+        raise typer.Exit(1)
+
+    client: GranolaClient | None = None
+    if append:
+        client = _connect(timeout)
+        try:
+            documents = client.get_documents_batch([doc_id])
+        except APIError as e:
+            console.print(f"[red]Error:[/red] API request failed: {e}")
+            raise typer.Exit(api_error_exit_code(e))
+        if not documents:
+            console.print(f"[red]Error:[/red] Document {doc_id} not found")
+            raise typer.Exit(1)
+        existing = documents[0].notes
+        notes = ProseMirrorDoc(content=[*(existing.content if existing else []), *notes.content])
+
+    if dry_run:
+        for line in _describe(notes):
+            console.print(line, markup=False, highlight=False)
+        console.print(f"Dry run: document {doc_id} was not updated.")
+        return
+
+    client = client or _connect(timeout)
+    state.logger.info(f"Updating notes of {doc_id} ({len(notes.content)} blocks)")
+    try:
+        client.update_document_notes(
+            doc_id,
+            notes=to_json(notes),
+            notes_markdown=to_markdown(notes),
+            notes_plain=to_plain_text(notes),
+        )
+    except APIError as e:
+        console.print(f"[red]Error:[/red] API request failed: {e}")
+        raise typer.Exit(api_error_exit_code(e))
+
+    action = "Appended to" if append else "Replaced"
+    console.print(f"[green]✓[/green] {action} the notes of document {doc_id}")
+
+
+def _connect(timeout: int) -> GranolaClient:
+    """Create an API client from the configured supabase.json, or exit."""
+    from granola.cli.main import state
+
+    supabase_path = state.supabase
+    if not supabase_path:
+        console.print(
+            "[red]Error:[/red] supabase.json path not set. "
+            "Use --supabase flag, SUPABASE_FILE env, or config file."
+        )
+        raise typer.Exit(ExitCode.AUTH)
+
+    try:
+        access_token = get_access_token(supabase_path)
+    except (AuthError, FileNotFoundError) as e:
+        console.print(f"[red]Error:[/red] Failed to read supabase.json: {e}")
+        raise typer.Exit(ExitCode.AUTH)
+
+    return GranolaClient(access_token, timeout=timeout, transport=state.http_transport)
+
+
+def _describe(doc: ProseMirrorDoc) -> list[str]:
+    """Return an indented outline of a document's nodes (for --dry-run)."""
+    lines = [doc.type]
+    for node in doc.content:
+        _describe_node(node, 1, lines)
+    return lines
+
+
+def _describe_node(node: ProseMirrorNode, depth: int, lines: list[str]) -> None:
+    """Append a node and its children to an outline."""
+    label = node.type
+    if node.attrs:
+        label += " " + " ".join(f"{k}={v}" for k, v in node.attrs.items())
+    # Show a paragraph's or heading's text on its own line
+    if node.content and all(child.type == "text" for child in node.content):
+        text = "".join(child.text for child in node.content)
+        if len(text) > 60:
+            text = text[:57] + "..."
+        lines.append(f"{'  ' * depth}{label}: {text!r}")
+        return
+    lines.append(f"{'  ' * depth}{label}")
+    for child in node.content:
+        _describe_node(child, depth + 1, lines)
@@ -0,0 +1,174 @@
+"""Report commands: summaries of meetings in the local Granola cache."""
+
+from typing import Annotated, Optional
+
+import typer
+from rich.console import Console
+from rich.table import Table
+
+from granola.cache.reader import get_default_cache_path, read_cache
+from granola.cli.book import period_option
+from granola.cli.completion import complete_folders
+from granola.cli.exit_codes import ExitCode
+from granola.reports import Meeting, load_meetings
+from granola.reports.time import (
+    GROUP_BY,
+    TimeReport,
+    format_time_csv,
+    format_time_json,
+    time_report,
+)
+from granola.source import is_filtered_out
+
+console = Console()
+
+report_app = typer.Typer(help="Reports on your meetings.", no_args_is_help=True)
+
+# Formats a report can be printed or written in
+REPORT_FORMATS = ("table", "csv", "json")
+
+
+def time_cmd(
+    group_by: Annotated[
+        str,
+        typer.Option("--group-by", help=f"Group meetings by: {', '.join(GROUP_BY)}"),
+    ] = "week",
+    report_format: Annotated[
+        str,
+        typer.Option("--format", help=f"Report format: {', '.join(REPORT_FORMATS)}"),
+    ] = "table",
+    output: Annotated[
+        Optional[str],
+        typer.Option("--output", help="File to write the report to (default: print it)"),
+    ] = None,
+    since: Annotated[
+        Optional[str],
+        typer.Option("--since", help="Only meetings from this year, month or day on (2024-01)"),
+    ] = None,
+    until: Annotated[
+        Optional[str],
+        typer.Option("--until", help="Only meetings up to the end of this year, month or day"),
+    ] = None,
+    folder: Annotated[
+        Optional[list[str]],
+        typer.Option(
+            "--folder",
+            help="Only meetings in this folder (can be used multiple times)",
+            autocompletion=complete_folders,
+        ),
+    ] = None,
+    round_to: Annotated[
+        int,
+        typer.Option("--round-to", min=0, help="Round each meeting up to this many minutes"),
+    ] = 0,
+    cache: Annotated[
+        Optional[str],
+        typer.Option("--cache", help="Path to Granola cache file"),
+    ] = None,
+) -> None:
+    """Report recorded meeting time per week, person or folder, billable-hours style.
+
+    A meeting's time is the length of its transcript, so only meetings recorded
+    in Granola count; meetings still in the cache without a transcript are left
+    out. Use --round-to to bill in increments (e.g. --round-to 15 rounds each
+    meeting up to a quarter hour).
+
+    Grouped by person, a meeting counts for each of the other people invited;
+    grouped by folder, for each of its folders. Use --format csv or json (and
+    --output) for spreadsheets and invoicing tools.
+    """
+    from granola.cli.main import state
+
+    if group_by not in GROUP_BY:
+        console.print(
+            f"[red]Error:[/red] Unknown --group-by '{group_by}'. "
+            f"Choose one of: {', '.join(GROUP_BY)}"
+        )
+        raise typer.Exit(ExitCode.ERROR)
+    if report_format not in REPORT_FORMATS:
+        console.print(
+            f"[red]Error:[/red] Unknown --format '{report_format}'. "
+            f"Choose one of: {', '.join(REPORT_FORMATS)}"
+        )
+        raise typer.Exit(ExitCode.ERROR)
+
+    meetings = load_cache_meetings(cache, since, until, folder)
+    report = time_report(meetings, group_by, round_to)
+    if report.untimed:
+        state.logger.info(f"Left out {report.untimed} meetings without a transcript")
+
+    if report_format == "table":
+        if output:
+            console.print("[red]Error:[/red] --output needs --format csv or json")
+            raise typer.Exit(ExitCode.ERROR)
+        _print_time_table(report)
+        return
+
+    text = format_time_csv(report) if report_format == "csv" else format_time_json(report)
+    write_report(text, output, "time report")
+
+
+def load_cache_meetings(
+    cache: Optional[str],
+    since: Optional[str],
+    until: Optional[str],
+    folder: Optional[list[str]],
+) -> list[Meeting]:
+    """Read the cache's meetings in the period and folders asked for."""
+    from granola.cli.main import state, resolve_path
+
+    start = period_option("--since", since)[0] if since else None
+    end = period_option("--until", until)[1] if until else None
+
+    cache_path = resolve_path(cache) if cache else get_default_cache_path()
+    if not cache_path.exists():
+        console.print(f"[red]Error:[/red] Cache file not found at {cache_path}")
+        raise typer.Exit(ExitCode.CACHE)
+    state.logger.info(f"Reading Granola cache file from {cache_path}")
+    try:
+        cache_data = read_cache(cache_path)
+    except Exception as e:
+        console.print(f"[red]Error:[/red] Failed to read cache file: {e}")
+        raise typer.Exit(ExitCode.CACHE)
+
+    wanted_folders = set(folder or [])
+    folder_parents = cache_data.get_folder_parents()
+    return [
+        meeting
+        for meeting in load_meetings(cache_data)
+        if not (start and meeting.start < start)
+        and not (end and meeting.start >= end)
+        and not is_filtered_out(meeting.folders, set(), wanted_folders, folder_parents)
+    ]
+
+
+def write_report(text: str, output: Optional[str], name: str) -> None:
+    """Write a CSV or JSON report to --output, or print it without one."""
+    from granola.cli.main import resolve_path
+
+    if not output:
+        print(text, end="")
+        return
+    output_path = resolve_path(output)
+    if output_path is None:
+        console.print("[red]Error:[/red] --output must be a file path")
+        raise typer.Exit(ExitCode.ERROR)
+    try:
+        output_path.parent.mkdir(parents=True, exist_ok=True)
+        output_path.write_text(text, encoding="utf-8")
+    except OSError as e:
+        console.print(f"[red]Error:[/red] Failed to write {output_path}: {e}")
+        raise typer.Exit(ExitCode.ERROR)
+    console.print(f"[green]✓[/green] Wrote the {name} to {output_path}")
+
+
+def _print_time_table(report: TimeReport) -> None:
+    """Print a time report as a table, with the total as its last row."""
+    table = Table(show_footer=True)
+    table.add_column(report.group_by.capitalize(), footer=report.total.group)
+    table.add_column("Meetings", justify="right", footer=str(report.total.meetings))
+    table.add_column("Minutes", justify="right", footer=str(report.total.minutes))
+    table.add_column("Hours", justify="right", footer=f"{report.total.hours:.2f}")
+    for row in report.rows:
+        table.add_row(row.group, str(row.meetings), str(row.minutes), f"{row.hours:.2f}")
+    console.print(table)
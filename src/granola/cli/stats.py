@@ -0,0 +1,119 @@
+"""Stats commands: statistics of meetings in the local Granola cache."""
+
+from datetime import datetime, timedelta
+from typing import Annotated, Optional
+
+import typer
+from rich.console import Console
+from rich.table import Table
+
+from granola.cli.book import period_option
+from granola.cli.completion import complete_folders
+from granola.cli.exit_codes import ExitCode
+from granola.cli.report import REPORT_FORMATS, load_cache_meetings, write_report
+from granola.reports.people import (
+    format_people_csv,
+    format_people_json,
+    month_starts,
+    people_stats,
+    sparkline,
+)
+
+console = Console()
+
+stats_app = typer.Typer(help="Statistics of your meetings.", no_args_is_help=True)
+
+# Arrows showing a person's trend in the table
+_TREND_ARROWS = {"up": "↑", "down": "↓", "flat": "→"}
+
+
+def people_cmd(
+    months: Annotated[
+        int,
+        typer.Option("--months", min=2, help="Months the trend covers, up to --until or now"),
+    ] = 6,
+    limit: Annotated[
+        int,
+        typer.Option("--limit", min=0, help="Show only this many people (0 for everyone)"),
+    ] = 20,
+    report_format: Annotated[
+        str,
+        typer.Option("--format", help=f"Report format: {', '.join(REPORT_FORMATS)}"),
+    ] = "table",
+    output: Annotated[
+        Optional[str],
+        typer.Option("--output", help="File to write the report to (default: print it)"),
+    ] = None,
+    since: Annotated[
+        Optional[str],
+        typer.Option("--since", help="Only meetings from this year, month or day on (2024-01)"),
+    ] = None,
+    until: Annotated[
+        Optional[str],
+        typer.Option("--until", help="Only meetings up to the end of this year, month or day"),
+    ] = None,
+    folder: Annotated[
+        Optional[list[str]],
+        typer.Option(
+            "--folder",
+            help="Only meetings in this folder (can be used multiple times)",
+            autocompletion=complete_folders,
+        ),
+    ] = None,
+    cache: Annotated[
+        Optional[str],
+        typer.Option("--cache", help="Path to Granola cache file"),
+    ] = None,
+) -> None:
+    """Show who you spend your meeting time with.
+
+    For each person invited to your meetings (from the calendar events in the
+    cache): how many meetings you had together, their recorded hours (transcript
+    length), when you last met, and meetings per month over the last --months
+    months, with whether that's going up or down (the recent half of the months
+    against the older half). People are told apart by email.
+    """
+    if report_format not in REPORT_FORMATS:
+        console.print(
+            f"[red]Error:[/red] Unknown --format '{report_format}'. "
+            f"Choose one of: {', '.join(REPORT_FORMATS)}"
+        )
+        raise typer.Exit(ExitCode.ERROR)
+
+    # The trend ends with the last month reported on (a period's end is exclusive)
+    now = datetime.now().astimezone()
+    end = min(period_option("--until", until)[1] - timedelta(microseconds=1), now) if until else now
+    meetings = load_cache_meetings(cache, since, until, folder)
+    people = people_stats(meetings, end, months)
+    if limit:
+        people = people[:limit]
+    starts = month_starts(end.astimezone(), months)
+
+    if report_format == "csv":
+        write_report(format_people_csv(people, starts), output, "people stats")
+        return
+    if report_format == "json":
+        write_report(format_people_json(people, starts), output, "people stats")
+        return
+    if output:
+        console.print("[red]Error:[/red] --output needs --format csv or json")
+        raise typer.Exit(ExitCode.ERROR)
+    if not people:
+        console.print("No meetings with other attendees found.")
+        return
+
+    table = Table()
+    table.add_column("Person")
+    table.add_column("Meetings", justify="right")
+    table.add_column("Hours", justify="right")
+    table.add_column("Last met")
+    table.add_column(f"{starts[0]:%b %Y} – {starts[-1]:%b %Y}")
+    for person in people:
+        table.add_row(
+            person.name,
+            str(person.meetings),
+            f"{person.hours:.1f}",
+            person.last_met.astimezone().strftime("%Y-%m-%d") if person.last_met else "",
+            f"{sparkline(person.monthly)} {_TREND_ARROWS[person.trend]}",
+        )
+    console.print(table)
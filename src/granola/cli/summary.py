@@ -0,0 +1,183 @@
+"""End-of-run export summary: a table on a terminal, one line otherwise, and a JSON report."""
+
+from datetime import datetime, timezone
+from pathlib import Path
+from typing import Any
+
+from rich.console import Console
+from rich.table import Table
+
+from granola.api.client import RequestStats
+from granola.utils.timing import Timings
+from granola.writers.sync_writer import SYNC_ACTIONS, PlannedChange, SyncFailure, SyncStats
+
+# Actions shown per folder in the summary table (the rest are only totalled)
+FOLDER_ACTIONS = ("added", "updated", "moved")
+
+# Headings of the changes a dry run lists (--dry-run), in the order they are listed
+PLAN_HEADINGS = {
+    "added": "[green]Would add[/green]",
+    "updated": "[yellow]Would update[/yellow]",
+    "moved": "[blue]Would move[/blue]",
+    "deleted": "[red]Would delete[/red]",
+}
+
+
+def print_summary(
+    console: Console,
+    stats: SyncStats,
+    empty: int,
+    failures: list[SyncFailure],
+    timings: Timings,
+) -> None:
+    """Print an export's summary: as tables if the console is a terminal, else as one line.
+
+    The table has a row per folder that files were added to, updated in or moved to.
+    """
+    if not console.is_terminal:
+        line = summary_line(stats, empty, failures)
+        console.print(f"[green]✓[/green] Export completed: {line}")
+        return
+
+    table = Table(title="Export completed", show_footer=True)
+    table.add_column("Folder", footer="Total")
+    for action in FOLDER_ACTIONS:
+        table.add_column(action.capitalize(), justify="right", footer=str(getattr(stats, action)))
+    for folder, counts in sorted(stats.folders.items()):
+        if any(counts.get(action) for action in FOLDER_ACTIONS):
+            table.add_row(folder, *(_count(counts.get(action, 0)) for action in FOLDER_ACTIONS))
+    table.caption = (
+        f"{stats.deleted} deleted, {stats.skipped} skipped"
+        + (f", {empty} empty" if empty else "")
+        + (f", [red]{len(failures)} failed[/red]" if failures else "")
+    )
+    console.print(table)
+
+    if timings.phases:
+        # Phases overlap (documents are rendered as they are written), so no total
+        durations = Table()
+        durations.add_column("Phase")
+        durations.add_column("Time", justify="right")
+        for phase, seconds in timings.phases.items():
+            durations.add_row(phase, f"{seconds:.2f}s")
+        console.print(durations)
+
+
+def print_request_stats(console: Console, request_stats: dict[str, RequestStats]) -> None:
+    """Print the API requests made per endpoint (--timings): pages, size and time.
+
+    Printed as a table if the console is a terminal, else as one line per endpoint.
+    Slow requests with small responses point at the API rather than the network.
+    """
+    if not request_stats:
+        return
+    rows = [
+        (
+            path,
+            str(stats.requests),
+            _size(stats.bytes),
+            f"{stats.seconds:.2f}s",
+            f"{stats.seconds / stats.requests:.2f}s, {_size(stats.bytes // stats.requests)}",
+        )
+        for path, stats in request_stats.items()
+    ]
+    if not console.is_terminal:
+        console.print("API requests:")
+        for path, requests, size, seconds, average in rows:
+            console.print(f"  {path}  {requests} requests  {size}  {seconds}  ({average} each)")
+        return
+
+    table = Table(title="API requests")
+    table.add_column("Endpoint")
+    for column in ("Requests", "Size", "Time", "Per request"):
+        table.add_column(column, justify="right")
+    for row in rows:
+        table.add_row(*row)
+    console.print(table)
+
+
+def print_plan(console: Console, plan: list[PlannedChange], output_dir: Path) -> None:
+    """Print the file changes a dry run would make (--dry-run), grouped by action.
+
+    Paths are relative to the output directory (absolute for folders mapped
+    outside it); moves show where the file or folder comes from.
+    """
+    for action, heading in PLAN_HEADINGS.items():
+        changes = [change for change in plan if change.action == action]
+        if not changes:
+            continue
+        console.print(f"{heading} ({len(changes)}):")
+        for change in changes:
+            line = _relative(change.path, output_dir)
+            if change.source is not None:
+                line = f"{_relative(change.source, output_dir)} -> {line}"
+            console.print(f"  {line}", markup=False, highlight=False)
+
+
+def summary_line(stats: SyncStats, empty: int, failures: list[SyncFailure]) -> str:
+    """Return the counts of an export as one line."""
+    return (
+        f"{stats.added} added, {stats.updated} updated, "
+        f"{stats.moved} moved, {stats.deleted} deleted, {stats.skipped} skipped"
+        + (f", {empty} empty" if empty else "")
+        + (f", [red]{len(failures)} failed[/red]" if failures else "")
+    )
+
+
+def folder_lines(stats: SyncStats) -> list[str]:
+    """Return one "folder: added=1, updated=4" line per folder, for the log."""
+    return [
+        f"{folder}: " + ", ".join(f"{a}={counts[a]}" for a in SYNC_ACTIONS if counts.get(a))
+        for folder, counts in sorted(stats.folders.items())
+    ]
+
+
+def json_report(
+    output_dir: Path,
+    stats: SyncStats,
+    empty: int,
+    failures: list[SyncFailure],
+    timings: Timings,
+) -> dict[str, Any]:
+    """Return an export's summary as a JSON-serializable report (--json-report).
+
+    Counts are given in total and per destination folder (relative to the output
+    directory; absolute for folders mapped outside it). Documents that failed to
+    render or write count as failed, but under no folder.
+    """
+    actions = [action for action in SYNC_ACTIONS if action != "failed"]
+    return {
+        "output": str(output_dir),
+        "finished_at": datetime.now(timezone.utc).isoformat(),
+        **{action: getattr(stats, action) for action in actions},
+        "empty": empty,
+        "failed": len(failures),
+        "folders": {
+            folder: {action: counts.get(action, 0) for action in actions}
+            for folder, counts in sorted(stats.folders.items())
+        },
+        "failures": [
+            {"id": failure.doc_id, "title": failure.title, "error": failure.error}
+            for failure in failures
+        ],
+        "timings": {phase: round(seconds, 3) for phase, seconds in timings.phases.items()},
+    }
+
+
+def _relative(path: Path, output_dir: Path) -> str:
+    """Return a path relative to the output directory, if it is inside it."""
+    return path.relative_to(output_dir).as_posix() if path.is_relative_to(output_dir) else str(path)
+
+
+def _size(size: int) -> str:
+    """Format a number of bytes, e.g. 1.2 MB."""
+    if size < 1024:
+        return f"{size} bytes"
+    if size < 1024 * 1024:
+        return f"{size / 1024:.1f} KB"
+    return f"{size / 1024 / 1024:.1f} MB"
+
+
+def _count(value: int) -> str:
+    """Format a count for the table, leaving zeros blank so changes stand out."""
+    return str(value) if value else ""
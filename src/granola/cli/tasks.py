@@ -0,0 +1,149 @@
+"""Tasks command: create Todoist or Taskwarrior tasks from action items."""
+
+from datetime import datetime
+from typing import Annotated, Optional
+
+import typer
+from rich.console import Console
+
+from granola.api.models import DEEP_LINKS, Document
+from granola.cli.book import period_option
+from granola.cli.completion import complete_folders
+from granola.cli.documents import fetch_documents_with_folders
+from granola.cli.exit_codes import ExitCode
+from granola.source import get_notes_content, is_filtered_out
+from granola.tasks import Task, TaskError, TaskLedger, TaskwarriorClient, TodoistClient
+from granola.tasks.ledger import get_default_ledger_path, item_key
+from granola.utils.dates import parse_timestamp
+from granola.utils.outcomes import extract_outcomes
+
+console = Console()
+
+SERVICES = ("todoist", "taskwarrior")
+
+
+def tasks_cmd(
+    service: Annotated[
+        str,
+        typer.Argument(help="Task manager to create tasks in: todoist or taskwarrior"),
+    ],
+    token: Annotated[
+        Optional[str],
+        typer.Option("--token", envvar="TODOIST_API_TOKEN", help="Todoist API token"),
+    ] = None,
+    project: Annotated[
+        Optional[str],
+        typer.Option(
+            "--project", help="Todoist project ID or Taskwarrior project to add tasks to"
+        ),
+    ] = None,
+    since: Annotated[
+        Optional[str],
+        typer.Option("--since", help="Only meetings from this year, month or day on (2024-01)"),
+    ] = None,
+    folder: Annotated[
+        Optional[list[str]],
+        typer.Option(
+            "--folder",
+            help="Only meetings in this folder (can be used multiple times)",
+            autocompletion=complete_folders,
+        ),
+    ] = None,
+    ledger: Annotated[
+        Optional[str],
+        typer.Option("--ledger", help="File recording the tasks created (default: in ~/.config)"),
+    ] = None,
+    timeout: Annotated[
+        int,
+        typer.Option("--timeout", help="HTTP timeout in seconds"),
+    ] = 120,
+) -> None:
+    """Create Todoist or Taskwarrior tasks from the open action items in meeting notes.
+
+    Action items are found like for granola anki (list items under a heading like
+    "Action items" or "Next steps", "TODO:" lines and task checkboxes), except
+    that ticked checkboxes are left out. Each task is tagged with "granola", the
+    meeting title and its date, and links back to the meeting.
+
+    Tasks created are recorded in a ledger file, so re-running the command only
+    creates tasks for action items that are new since; a task completed or
+    deleted in the task manager isn't created again. Use --since on the first
+    run to leave out action items from older meetings.
+
+    Todoist needs an API token (--token or TODOIST_API_TOKEN); Taskwarrior needs
+    the task command installed.
+    """
+    from granola.cli.main import state, resolve_path
+
+    service = service.lower()
+    if service not in SERVICES:
+        console.print(
+            f"[red]Error:[/red] Unknown task manager '{service}'. Use: {', '.join(SERVICES)}"
+        )
+        raise typer.Exit(ExitCode.ERROR)
+    if service == "todoist" and not token:
+        console.print(
+            "[red]Error:[/red] Todoist API token not set. Use --token or TODOIST_API_TOKEN env."
+        )
+        raise typer.Exit(ExitCode.AUTH)
+
+    start = period_option("--since", since)[0] if since else None
+
+    ledger_path = resolve_path(ledger) if ledger else get_default_ledger_path()
+    if ledger_path is None:
+        console.print("[red]Error:[/red] --ledger must be a file path")
+        raise typer.Exit(ExitCode.ERROR)
+
+    documents, doc_folders, folder_parents = fetch_documents_with_folders(timeout)
+    wanted_folders = set(folder or [])
+
+    task_client: TodoistClient | TaskwarriorClient
+    if service == "todoist":
+        task_client = TodoistClient(token or "", project_id=project, logger=state.logger)
+    else:
+        task_client = TaskwarriorClient(project=project, logger=state.logger)
+
+    task_ledger = TaskLedger.load(ledger_path)
+    created = skipped = 0
+
+    try:
+        for doc in sorted(documents, key=lambda d: d.created_at):
+            meeting_start = parse_timestamp(doc.created_at)
+            if meeting_start is None or (start and meeting_start < start):
+                continue
+            if is_filtered_out(doc_folders.get(doc.id, []), set(), wanted_folders, folder_parents):
+                continue
+
+            for item in extract_outcomes(get_notes_content(doc)).actions:
+                key = item_key(doc.id, item)
+                if task_ledger.created(service, key):
+                    skipped += 1
+                    continue
+                task_id = task_client.add(_task(doc, meeting_start, item))
+                task_ledger.record(service, key, task_id)
+                created += 1
+    except TaskError as e:
+        console.print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(ExitCode.NETWORK if service == "todoist" else ExitCode.ERROR)
+    finally:
+        task_client.close()
+        # Keep what was created before a failure, so it isn't created again
+        if created and not task_ledger.save():
+            console.print(f"[yellow]Warning:[/yellow] Failed to write the ledger {ledger_path}")
+
+    console.print(
+        f"[green]✓[/green] Created {created} {service} tasks ({skipped} already created before)"
+    )
+    state.logger.info(f"Tasks completed: service={service}, created={created}, skipped={skipped}")
+
+
+def _task(doc: Document, meeting_start: datetime, item: str) -> Task:
+    """Build the task for an action item, tagged with its meeting's title and date."""
+    title = doc.title or "Untitled"
+    date = meeting_start.strftime("%Y-%m-%d")
+    link = DEEP_LINKS["web"].format(id=doc.id)
+    return Task(
+        content=item,
+        tags=[title, date],
+        description=f"From {title} on {date}: {link}",
+    )
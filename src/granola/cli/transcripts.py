@@ -1,18 +1,36 @@
 """Transcripts export command."""
 
+import re
 from datetime import datetime, timezone
 from pathlib import Path
-from typing import Annotated, Optional
+from typing import Annotated, Callable, Optional
 
 import typer
 from rich.console import Console
 
-from granola.cache.reader import CacheDocument, get_default_cache_path, read_cache
+from granola.api.auth import AuthError, get_access_token
+from granola.api.client import APIError, GranolaClient
+from granola.cache.reader import (
+    CacheData,
+    CacheDocument,
+    CacheError,
+    get_default_cache_path,
+    read_cache,
+    skipped_summary,
+)
+from granola.cli.exit_codes import ExitCode, api_error_exit_code
+from granola.config.file import ConfigError, get_section
+from granola.config.speakers import SpeakerMap, get_default_speakers_path, load_speakers
 from granola.formatters.transcript import format_transcript
 from granola.utils.filename import make_unique, sanitize_filename
+from granola.utils.selection import ORDERS, SORT_FIELDS, page, sort_documents, title_selected
+from granola.writers.compression import GZIP_SUFFIX, gzip_content
 
 console = Console()
 
+# Where transcripts can be read from (--transcript-source)
+TRANSCRIPT_SOURCES = ("auto", "cache", "api")
+
 
 def transcripts_cmd(
     cache: Annotated[
@@ -23,27 +41,158 @@ def transcripts_cmd(
         Optional[str],
         typer.Option("--output", help="Output directory for exported transcript files"),
     ] = None,
+    transcript_source: Annotated[
+        str,
+        typer.Option(
+            "--transcript-source",
+            help="Where transcripts come from: auto (cache, or API if the cache is missing), "
+            "cache, or api",
+        ),
+    ] = "auto",
+    timeout: Annotated[
+        int,
+        typer.Option("--timeout", help="HTTP timeout in seconds (API transcripts)"),
+    ] = 120,
+    limit: Annotated[
+        Optional[int],
+        typer.Option("--limit", help="Only write the transcripts of the N most recent documents"),
+    ] = None,
+    skip: Annotated[
+        int,
+        typer.Option(
+            "--skip", help="Skip the first N documents (most recently updated, unless --sort)"
+        ),
+    ] = 0,
+    sort: Annotated[
+        Optional[str],
+        typer.Option(
+            "--sort",
+            help=f"Process documents ordered by: {', '.join(SORT_FIELDS)} (default: as fetched)",
+        ),
+    ] = None,
+    order: Annotated[
+        Optional[str],
+        typer.Option(
+            "--order",
+            help="Sort direction: asc or desc (default: desc, or asc for title)",
+        ),
+    ] = None,
+    match: Annotated[
+        Optional[str],
+        typer.Option("--match", help="Only include documents whose title matches this regex"),
+    ] = None,
+    exclude_match: Annotated[
+        Optional[str],
+        typer.Option("--exclude-match", help="Skip documents whose title matches this regex"),
+    ] = None,
+    speakers: Annotated[
+        Optional[str],
+        typer.Option("--speakers", help="speakers.yaml naming transcript speakers"),
+    ] = None,
+    strict: Annotated[
+        bool,
+        typer.Option("--strict", help="Fail if any cache entry can't be parsed"),
+    ] = False,
+    compress: Annotated[
+        bool,
+        typer.Option("--compress", help="Gzip transcript files (written as .txt.gz)"),
+    ] = False,
+    force: Annotated[
+        bool,
+        typer.Option("--force", help="Rewrite every file, even if its document is unchanged"),
+    ] = False,
 ) -> None:
-    """Export Granola transcripts to text files."""
+    """Export Granola transcripts to text files.
+
+    Transcripts are read from the local Granola cache. With --transcript-source api
+    (or auto when the cache is missing) they are fetched from the Granola API
+    instead, one request per document selected (by --match, --limit and so on).
+
+    Use --limit and --skip to write only some of the most recently updated
+    transcripts (documents without one don't count), and --match and
+    --exclude-match to select documents by title (regular expressions).
+
+    Use --speakers (or ~/.config/granola/speakers.yaml) to replace "You" and
+    "System" with real names, per meeting or per calendar attendee.
+
+    Cache entries that can't be parsed are skipped with a warning (details with
+    --debug); use --strict to fail instead.
+
+    Use --compress to write gzipped .txt.gz files; a year of transcripts is
+    several times smaller compressed.
+
+    Files are only rewritten if their document was updated since; use --force to
+    rewrite them all (e.g. after changing speakers.yaml).
+    """
     from granola.cli.main import state, resolve_path
 
-    # Resolve cache path
-    cache_path = resolve_path(cache) if cache else get_default_cache_path()
+    speaker_map = load_speaker_map(speakers)
 
-    if not cache_path.exists():
-        console.print(f"[red]Error:[/red] Cache file not found at {cache_path}")
+    try:
+        title_match = re.compile(match) if match else None
+        title_exclude = re.compile(exclude_match) if exclude_match else None
+    except re.error as e:
+        console.print(f"[red]Error:[/red] Invalid title pattern: {e}")
         raise typer.Exit(1)
 
-    # Read cache
-    console.print("Reading Granola cache file...")
-    state.logger.info(f"Reading Granola cache file from {cache_path}")
+    if sort is not None and sort not in SORT_FIELDS:
+        console.print(
+            f"[red]Error:[/red] Unknown --sort '{sort}'. Choose one of: {', '.join(SORT_FIELDS)}"
+        )
+        raise typer.Exit(1)
 
-    try:
-        cache_data = read_cache(cache_path)
-    except Exception as e:
-        console.print(f"[red]Error:[/red] Failed to read cache file: {e}")
+    if order is not None and order not in ORDERS:
+        console.print(
+            f"[red]Error:[/red] Unknown --order '{order}'. Choose one of: {', '.join(ORDERS)}"
+        )
+        raise typer.Exit(1)
+
+    if (limit is not None and limit < 0) or skip < 0:
+        console.print("[red]Error:[/red] --limit and --skip must not be negative")
+        raise typer.Exit(1)
+
+    if transcript_source not in TRANSCRIPT_SOURCES:
+        console.print(
+            f"[red]Error:[/red] Unknown --transcript-source '{transcript_source}'. "
+            f"Choose one of: {', '.join(TRANSCRIPT_SOURCES)}"
+        )
         raise typer.Exit(1)
 
+    def select(docs: list[CacheDocument]) -> list[CacheDocument]:
+        """Return the documents selected by title, in the order they're paged in."""
+        docs = [doc for doc in docs if title_selected(doc.title, title_match, title_exclude)]
+        if sort or limit is not None or skip:
+            docs = sort_documents(docs, sort or "updated", order)
+        return docs
+
+    # Resolve cache path
+    cache_path = resolve_path(cache) if cache else get_default_cache_path()
+
+    from_api = transcript_source == "api" or (
+        transcript_source == "auto" and not cache_path.exists()
+    )
+    if from_api:
+        # Documents are selected before their transcripts are fetched, and only as
+        # many are fetched as it takes to fill the page
+        cache_data = _fetch_from_api(timeout, select, skip + limit if limit is not None else None)
+    elif not cache_path.exists():
+        console.print(f"[red]Error:[/red] Cache file not found at {cache_path}")
+        raise typer.Exit(ExitCode.CACHE)
+    else:
+        # Read cache
+        console.print("Reading Granola cache file...")
+        state.logger.info(f"Reading Granola cache file from {cache_path}")
+
+        try:
+            cache_data = read_cache(cache_path, strict=strict)
+        except CacheError as e:
+            console.print(f"[red]Error:[/red] {e}")
+            raise typer.Exit(ExitCode.CACHE)
+        except Exception as e:
+            console.print(f"[red]Error:[/red] Failed to read cache file: {e}")
+            raise typer.Exit(ExitCode.CACHE)
+        report_skipped(cache_data)
+
     state.logger.info(
         f"Loaded cache data: {len(cache_data.documents)} documents, "
         f"{len(cache_data.transcripts)} transcripts"
@@ -53,34 +202,45 @@ def transcripts_cmd(
     output_dir = resolve_path(output) if output else Path("./transcripts")
     output_dir.mkdir(parents=True, exist_ok=True)
 
-    console.print(f"Exporting {len(cache_data.transcripts)} transcripts to {output_dir}...")
-    state.logger.info(f"Writing transcripts to {output_dir}")
-
-    # Write transcripts
-    used_filenames: dict[str, int] = {}
-    count = 0
-
+    # Documents with a transcript
+    docs: list[CacheDocument] = []
     for doc_id, segments in cache_data.transcripts.items():
         # Skip if no segments
         if not segments:
             continue
 
-        # Get document info
         doc = cache_data.documents.get(doc_id)
         if not doc:
             doc = CacheDocument(id=doc_id, title=doc_id, created_at="", updated_at="")
+        docs.append(doc)
+    docs = select(docs)
+    if limit is not None or skip:
+        docs = page(docs, limit, skip)
+    entries = [(doc, cache_data.transcripts[doc.id]) for doc in docs]
+
+    console.print(f"Exporting {len(entries)} transcripts to {output_dir}...")
+    state.logger.info(f"Writing transcripts to {output_dir}")
 
+    # Write transcripts
+    used_filenames: dict[str, int] = {}
+    count = 0
+    failed: list[Path] = []
+
+    for doc, segments in entries:
         # Generate filename
         filename = sanitize_filename(doc.title or doc.id, fallback=doc.id)
         filename = make_unique(filename, used_filenames)
         used_filenames[filename] = used_filenames.get(filename, 0) + 1
 
-        file_path = output_dir / f"{filename}.txt"
+        file_path = output_dir / f"{filename}.txt{GZIP_SUFFIX if compress else ''}"
 
         # Check if file needs updating
-        if not _should_update_file(doc, file_path):
+        if not force and not _should_update_file(doc, file_path):
             continue
 
+        if speaker_map:
+            segments = speaker_map.apply(segments, doc.id, doc.attendee_emails)
+
         # Format transcript
         content = format_transcript(doc, segments)
         if not content:
@@ -88,16 +248,128 @@ def transcripts_cmd(
 
         # Write file
         try:
-            file_path.write_text(content)
+            if compress:
+                file_path.write_bytes(gzip_content(content))
+            else:
+                file_path.write_text(content)
             count += 1
         except Exception as e:
+            # One unwritable file shouldn't abort the rest
             console.print(f"[red]Error:[/red] Failed to write {file_path}: {e}")
-            raise typer.Exit(1)
+            failed.append(file_path)
+
+    if failed:
+        console.print(
+            f"[red]✗[/red] {count} files written, {len(failed)} transcript(s) failed to write"
+        )
+        state.logger.info(f"Export finished, {count} files written, {len(failed)} failed")
+        raise typer.Exit(ExitCode.PARTIAL)
 
     console.print(f"[green]✓[/green] Export completed successfully ({count} files written)")
     state.logger.info(f"Export completed successfully, {count} files written")
 
 
+def report_skipped(cache_data: CacheData) -> None:
+    """Warn about cache entries that couldn't be parsed (and log each one)."""
+    from granola.cli.main import state
+
+    if not cache_data.skipped:
+        return
+    for entry in cache_data.skipped:
+        where = f"{entry.section}.{entry.key}" if entry.key else entry.section
+        state.logger.info(f"Skipped cache entry {where}: {entry.reason}")
+    console.print(
+        f"[yellow]Warning:[/yellow] Skipped {len(cache_data.skipped)} cache entries that "
+        f"couldn't be parsed ({skipped_summary(cache_data.skipped)}); some meetings may be "
+        "missing. Use --strict to fail instead."
+    )
+
+
+def load_speaker_map(spec: Optional[str]) -> Optional[SpeakerMap]:
+    """Load the speaker mapping given with --speakers, in the config file, or by default.
+
+    The default file (~/.config/granola/speakers.yaml) is optional; one given
+    explicitly must exist. Exits on an invalid mapping.
+    """
+    from granola.cli.main import state, resolve_path
+
+    spec = spec or get_section(state.config, "export").get("speakers")
+    path = resolve_path(spec) if spec else get_default_speakers_path()
+    if not spec and not path.exists():
+        return None
+
+    try:
+        speaker_map = load_speakers(path)
+    except ConfigError as e:
+        console.print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(ExitCode.ERROR)
+    state.logger.info(f"Loaded speaker names from {path}")
+    return speaker_map
+
+
+def _fetch_from_api(
+    timeout: int,
+    select: Callable[[list[CacheDocument]], list[CacheDocument]],
+    count: int | None,
+) -> CacheData:
+    """Fetch documents and the transcripts of those selected from the Granola API.
+
+    Args:
+        timeout: HTTP timeout in seconds.
+        select: Returns the documents whose transcripts to fetch (one request each),
+            in order.
+        count: Stop once this many transcripts are found (documents without one
+            don't count), or None to fetch them all.
+
+    Returns:
+        Cache-shaped data holding the documents and the selected ones' transcripts.
+    """
+    from granola.cli.main import state
+
+    supabase_path = state.supabase
+    if not supabase_path:
+        console.print(
+            "[red]Error:[/red] supabase.json path not set. "
+            "Use --supabase flag, SUPABASE_FILE env, or config file."
+        )
+        raise typer.Exit(ExitCode.AUTH)
+
+    try:
+        access_token = get_access_token(supabase_path)
+    except (AuthError, FileNotFoundError) as e:
+        console.print(f"[red]Error:[/red] Failed to read supabase.json: {e}")
+        raise typer.Exit(ExitCode.AUTH)
+
+    console.print("Fetching transcripts from Granola API...")
+    try:
+        client = GranolaClient(access_token, timeout=timeout, transport=state.http_transport)
+        api_docs = client.get_documents()
+        documents = {
+            doc.id: CacheDocument(
+                id=doc.id,
+                title=doc.title or "",
+                created_at=doc.created_at,
+                updated_at=doc.updated_at,
+                calendar_event=doc.google_calendar_event,
+            )
+            for doc in api_docs
+        }
+    except APIError as e:
+        console.print(f"[red]Error:[/red] API request failed: {e}")
+        raise typer.Exit(api_error_exit_code(e))
+
+    # Fetch in batches until count transcripts are found, as some documents have none
+    data = CacheData(documents=documents)
+    candidates = select(list(documents.values()))
+    fetched = 0
+    while fetched < len(candidates) and (count is None or len(data.transcripts) < count):
+        end = len(candidates) if count is None else fetched + count - len(data.transcripts)
+        batch = candidates[fetched:end]
+        data.transcripts.update(client.get_transcripts(doc.id for doc in batch))
+        fetched += len(batch)
+    return data
+
+
 def _should_update_file(doc: CacheDocument, file_path: Path) -> bool:
     """Check if the file needs to be updated based on timestamps."""
     if not file_path.exists():
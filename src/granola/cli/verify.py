@@ -0,0 +1,73 @@
+"""Verify command: check exported files against the export's manifest."""
+
+import os
+from pathlib import Path
+from typing import Annotated, Optional
+
+import typer
+from rich.console import Console
+
+from granola.cli.exit_codes import ExitCode
+from granola.writers.manifest import MANIFEST_FILENAME
+from granola.writers.verify import verify_output
+
+console = Console()
+
+
+def verify_cmd(
+    output: Annotated[
+        Optional[str],
+        typer.Option("--output", help="Export output directory to check"),
+    ] = None,
+) -> None:
+    """Report exported files modified, missing, or added outside granola.
+
+    Each export records a checksum of every file it writes in the output's
+    manifest (.granola-manifest.json). This recomputes them, so cloud-sync
+    conflicts and local edits can be found before the next export overwrites
+    them. Exits with code 7 if anything differs.
+
+    Files exported by older versions have no checksum until they are next
+    written; they are counted as unchecked.
+    """
+    from granola.cli.export import export_output_dir
+    from granola.cli.main import state
+
+    output_dir = export_output_dir(output)
+    if not (output_dir / MANIFEST_FILENAME).is_file():
+        console.print(
+            f"[red]Error:[/red] No manifest in {output_dir}; is it a granola export folder?"
+        )
+        raise typer.Exit(ExitCode.ERROR)
+
+    try:
+        report = verify_output(output_dir)
+    except OSError as e:
+        console.print(f"[red]Error:[/red] Failed to read {output_dir}: {e}")
+        raise typer.Exit(ExitCode.ERROR)
+
+    def show(label: str, paths: list[Path]) -> None:
+        for path in paths:
+            console.print(f"  {label} {_display(path, output_dir)}", markup=False)
+
+    show("modified:", report.modified)
+    show("missing: ", report.missing)
+    show("added:   ", report.added)
+    for path in report.unchecked:
+        state.logger.info(f"No checksum recorded for {path}")
+
+    summary = (
+        f"{report.checked} unchanged, {len(report.modified)} modified, "
+        f"{len(report.missing)} missing, {len(report.added)} added"
+        + (f", {len(report.unchecked)} unchecked" if report.unchecked else "")
+    )
+    if report.in_sync:
+        console.print(f"[green]✓[/green] {output_dir} matches its manifest: {summary}")
+        return
+    console.print(f"[yellow]✗[/yellow] {output_dir} differs from its manifest: {summary}")
+    raise typer.Exit(ExitCode.OUT_OF_SYNC)
+
+
+def _display(path: Path, output_dir: Path) -> str:
+    """Return a path relative to the output directory if it is inside it."""
+    return os.path.relpath(path, output_dir) if path.is_relative_to(output_dir) else str(path)
@@ -0,0 +1,162 @@
+"""Version command: build information for bug reports, and a check for updates."""
+
+import json
+import platform
+import re
+import subprocess
+import sys
+from datetime import datetime
+from importlib import metadata
+from pathlib import Path
+from typing import Annotated, Optional
+
+import httpx
+import typer
+from rich.console import Console
+
+from granola import __version__
+from granola.cli.exit_codes import ExitCode
+
+console = Console()
+
+# Distribution name the package is installed under
+DISTRIBUTION = "granola-cli"
+
+# Latest published release (GitHub REST API)
+RELEASES_URL = "https://api.github.com/repos/milesskorpen/granola/releases/latest"
+
+CHECK_TIMEOUT = 10  # seconds
+
+
+def version_cmd(
+    check: Annotated[
+        bool,
+        typer.Option("--check", help="Check GitHub for a newer release"),
+    ] = False,
+) -> None:
+    """Print the version, commit and environment granola runs in.
+
+    Include the output in bug reports. The commit is known when granola was
+    installed from a git URL or runs from a git checkout (e.g. pip install -e);
+    the date is the commit's, or else when the package was installed.
+
+    Use --check to also look up the latest release on GitHub.
+    """
+    for label, value in build_info().items():
+        console.print(f"{label + ':':<10} {value}", markup=False, highlight=False)
+
+    if not check:
+        return
+    try:
+        latest = latest_release()
+    except (httpx.HTTPError, ValueError) as e:
+        console.print(f"[red]Error:[/red] Failed to check for updates: {e}")
+        raise typer.Exit(ExitCode.NETWORK)
+    if latest is None:
+        console.print("No releases published yet")
+    elif _version_key(latest) > _version_key(__version__):
+        console.print(
+            f"[yellow]Update available:[/yellow] {latest} "
+            "(https://github.com/milesskorpen/granola/releases)"
+        )
+    else:
+        console.print(f"[green]✓[/green] Up to date (latest release: {latest})")
+
+
+def build_info() -> dict[str, str]:
+    """Return what identifies this build: version, commit, date, Python and platform."""
+    package_dir = Path(__file__).resolve().parent.parent
+    commit, date = _vcs_info(package_dir)
+    return {
+        "Version": __version__,
+        "Commit": commit or "unknown",
+        "Date": date or "unknown",
+        "Python": f"{platform.python_implementation()} {platform.python_version()}",
+        "Platform": platform.platform(),
+        "Location": str(package_dir),
+        "Executable": sys.executable,
+    }
+
+
+def latest_release() -> Optional[str]:
+    """Return the version of the latest GitHub release, or None if there is none.
+
+    Raises:
+        httpx.HTTPError: If GitHub can't be reached or returns an error.
+        ValueError: If the response isn't a release.
+    """
+    response = httpx.get(
+        RELEASES_URL,
+        headers={"Accept": "application/vnd.github+json"},
+        timeout=CHECK_TIMEOUT,
+        follow_redirects=True,
+    )
+    if response.status_code == 404:
+        return None
+    response.raise_for_status()
+    tag = response.json().get("tag_name")
+    if not isinstance(tag, str) or not tag:
+        raise ValueError("no tag_name in the latest release")
+    return tag.removeprefix("v")
+
+
+def _vcs_info(package_dir: Path) -> tuple[Optional[str], Optional[str]]:
+    """Return the commit and date of this build, if they can be found.
+
+    Installs from a git URL record the commit in the distribution's direct_url.json;
+    otherwise the package may run from a checkout of this repository (src/granola),
+    e.g. an editable install.
+    """
+    try:
+        dist = metadata.distribution(DISTRIBUTION)
+    except metadata.PackageNotFoundError:
+        dist = None
+    if dist is not None:
+        direct_url = json.loads(dist.read_text("direct_url.json") or "{}")
+        commit = direct_url.get("vcs_info", {}).get("commit_id")
+        if commit:
+            return commit[:12], _installed_date(dist)
+
+    checkout = package_dir.parent.parent
+    if not (checkout / ".git").exists():
+        return None, _installed_date(dist) if dist is not None else None
+    try:
+        git = ["git", "-C", str(checkout)]
+        commit = subprocess.run(
+            [*git, "rev-parse", "--short=12", "HEAD"], capture_output=True, text=True, check=True
+        ).stdout.strip()
+        date = subprocess.run(
+            [*git, "log", "-1", "--format=%cs"], capture_output=True, text=True, check=True
+        ).stdout.strip()
+        dirty = subprocess.run(
+            [*git, "status", "--porcelain", "--untracked-files=no"],
+            capture_output=True,
+            text=True,
+            check=True,
+        ).stdout.strip()
+    except (OSError, subprocess.CalledProcessError):
+        return None, _installed_date(dist) if dist is not None else None
+    return commit + ("-dirty" if dirty else ""), date
+
+
+def _installed_date(dist: metadata.Distribution) -> Optional[str]:
+    """Return the date a distribution was installed (its METADATA file's time)."""
+    for file in dist.files or []:
+        if file.name == "METADATA":
+            try:
+                mtime = Path(str(dist.locate_file(file))).stat().st_mtime
+            except OSError:
+                return None
+            return datetime.fromtimestamp(mtime).date().isoformat()
+    return None
+
+
+def _version_key(version: str) -> tuple[int, ...]:
+    """Return a version's numeric parts for comparison, e.g. (0, 2, 0) for 0.2.0."""
+    parts: list[int] = []
+    for part in version.split("."):
+        match = re.match(r"\d+", part)
+        if not match:
+            break
+        parts.append(int(match.group()))
+    return tuple(parts)
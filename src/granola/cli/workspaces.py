@@ -0,0 +1,51 @@
+"""Workspaces listing command."""
+
+from typing import Annotated
+
+import typer
+from rich.console import Console
+
+from granola.api.auth import AuthError, get_access_token
+from granola.api.client import APIError, GranolaClient
+from granola.cli.exit_codes import ExitCode, api_error_exit_code
+
+console = Console()
+
+
+def workspaces_cmd(
+    timeout: Annotated[
+        int,
+        typer.Option("--timeout", help="HTTP timeout in seconds"),
+    ] = 120,
+) -> None:
+    """List the Granola workspaces you belong to (for export --workspace)."""
+    from granola.cli.main import state
+
+    supabase_path = state.supabase
+    if not supabase_path:
+        console.print(
+            "[red]Error:[/red] supabase.json path not set. "
+            "Use --supabase flag, SUPABASE_FILE env, or config file."
+        )
+        raise typer.Exit(ExitCode.AUTH)
+
+    try:
+        access_token = get_access_token(supabase_path)
+    except (AuthError, FileNotFoundError) as e:
+        console.print(f"[red]Error:[/red] Failed to read supabase.json: {e}")
+        raise typer.Exit(ExitCode.AUTH)
+
+    try:
+        client = GranolaClient(access_token, timeout=timeout, transport=state.http_transport)
+        workspaces = client.get_workspaces()
+    except APIError as e:
+        console.print(f"[red]Error:[/red] API request failed: {e}")
+        raise typer.Exit(api_error_exit_code(e))
+
+    if not workspaces:
+        console.print("No workspaces found.")
+        return
+
+    for workspace in workspaces:
+        slug = f" ({workspace.slug})" if workspace.slug else ""
+        console.print(f"{workspace.name or 'Unnamed'}{slug}  [dim]{workspace.id}[/dim]")
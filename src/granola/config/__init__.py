@@ -1,5 +1,22 @@
 """Configuration management for Granola."""
 
+from granola.config.file import (
+    ConfigError,
+    get_default_config_path,
+    get_section,
+    load_config_file,
+)
 from granola.config.settings import Settings, get_settings
+from granola.config.speakers import SpeakerMap, get_default_speakers_path, load_speakers
 
-__all__ = ["Settings", "get_settings"]
+__all__ = [
+    "ConfigError",
+    "Settings",
+    "SpeakerMap",
+    "get_default_config_path",
+    "get_default_speakers_path",
+    "get_section",
+    "get_settings",
+    "load_config_file",
+    "load_speakers",
+]
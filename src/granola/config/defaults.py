@@ -0,0 +1,79 @@
+"""Default directories, used when neither options nor the config file set them.
+
+In portable mode (--portable, or a portable.toml next to the executable) they are
+all inside the portable folder instead, so a whole setup can live on a USB drive
+or in a synced tools folder.
+"""
+
+import os
+import sys
+from pathlib import Path
+
+# Where versions before configurable defaults exported to; still used if it exists,
+# so upgrading doesn't start a second export somewhere else
+LEGACY_EXPORT_DIR = Path.home() / "My Drive" / "z. Granola Notes"
+
+# Subdirectory of the export directory the notes command writes to
+NOTES_SUBDIR = "Markdown"
+
+# File next to the executable that turns on portable mode; it is also the config file
+PORTABLE_CONFIG = "portable.toml"
+
+# Folder of the portable setup, or None if not in portable mode
+_portable_root: Path | None = None
+
+
+def executable_dir() -> Path:
+    """Return the directory of the granola executable (or of the running script)."""
+    if getattr(sys, "frozen", False):
+        return Path(sys.executable).resolve().parent
+    return Path(sys.argv[0]).resolve().parent
+
+
+def find_portable_root() -> Path | None:
+    """Return the executable's directory if it has a portable.toml, else None."""
+    directory = executable_dir()
+    return directory if (directory / PORTABLE_CONFIG).is_file() else None
+
+
+def portable_root() -> Path | None:
+    """Return the folder of the portable setup, or None if not in portable mode."""
+    return _portable_root
+
+
+def set_portable_root(root: Path | None) -> None:
+    """Turn portable mode on (with the folder of the setup) or off."""
+    global _portable_root
+    _portable_root = root
+
+
+def config_dir() -> Path:
+    """Return the directory of granola's own files (config, speakers, tasks ledger).
+
+    ~/.config/granola, or the portable folder in portable mode.
+    """
+    return _portable_root or Path.home() / ".config" / "granola"
+
+
+def documents_dir() -> Path:
+    """Return the user's documents folder (XDG_DOCUMENTS_DIR on Linux, if set)."""
+    if sys.platform.startswith("linux") and os.environ.get("XDG_DOCUMENTS_DIR"):
+        return Path(os.environ["XDG_DOCUMENTS_DIR"]).expanduser()
+    return Path.home() / "Documents"
+
+
+def default_export_dir() -> Path:
+    """Return the default export directory: the legacy one if it exists, else Documents/Granola.
+
+    In portable mode, it's Granola in the portable folder.
+    """
+    if _portable_root:
+        return _portable_root / "Granola"
+    if LEGACY_EXPORT_DIR.is_dir():
+        return LEGACY_EXPORT_DIR
+    return documents_dir() / "Granola"
+
+
+def default_notes_dir() -> Path:
+    """Return the default directory for the notes command, inside the export directory."""
+    return default_export_dir() / NOTES_SUBDIR
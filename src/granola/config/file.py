@@ -0,0 +1,50 @@
+"""TOML config file loading for the CLI."""
+
+import tomllib
+from pathlib import Path
+from typing import Any
+
+from granola.config.defaults import PORTABLE_CONFIG, config_dir, portable_root
+
+
+class ConfigError(Exception):
+    """Raised when the config file cannot be read or parsed."""
+
+    pass
+
+
+def get_default_config_path() -> Path:
+    """Return the default config file path (~/.config/granola/config.toml).
+
+    In portable mode, it's the portable.toml in the portable folder.
+    """
+    if portable_root():
+        return config_dir() / PORTABLE_CONFIG
+    return config_dir() / "config.toml"
+
+
+def load_config_file(path: Path) -> dict[str, Any]:
+    """Load a TOML config file.
+
+    Args:
+        path: Path to the config file.
+
+    Returns:
+        Parsed config as a nested dict.
+
+    Raises:
+        ConfigError: If the file cannot be read or is not valid TOML.
+    """
+    try:
+        with path.open("rb") as f:
+            return tomllib.load(f)
+    except OSError as e:
+        raise ConfigError(f"Failed to read config file {path}: {e}") from e
+    except tomllib.TOMLDecodeError as e:
+        raise ConfigError(f"Invalid config file {path}: {e}") from e
+
+
+def get_section(config: dict[str, Any], name: str) -> dict[str, Any]:
+    """Return a top-level config table, or an empty dict if missing or not a table."""
+    section = config.get(name, {})
+    return section if isinstance(section, dict) else {}
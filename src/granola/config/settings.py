@@ -6,6 +6,8 @@ from typing import Optional
 from pydantic import Field
 from pydantic_settings import BaseSettings, SettingsConfigDict
 
+from granola.config.defaults import default_export_dir, default_notes_dir
+
 
 class Settings(BaseSettings):
     """Application settings with support for env vars, .env files, and TOML config."""
@@ -22,18 +24,14 @@ class Settings(BaseSettings):
 
     # Notes command settings
     timeout: int = Field(default=120, description="HTTP timeout in seconds")
-    notes_output: Path = Field(
-        default_factory=lambda: Path.home() / "My Drive" / "z. Granola Notes" / "Markdown"
-    )
+    notes_output: Path = Field(default_factory=default_notes_dir)
 
     # Transcripts command settings
     cache_file: Optional[Path] = None
     transcripts_output: Path = Field(default_factory=lambda: Path("./transcripts"))
 
     # Export command settings
-    export_output: Path = Field(
-        default_factory=lambda: Path.home() / "My Drive" / "z. Granola Notes"
-    )
+    export_output: Path = Field(default_factory=default_export_dir)
 
     @property
     def default_cache_path(self) -> Path:
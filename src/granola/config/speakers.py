@@ -0,0 +1,117 @@
+"""Speaker mapping file (speakers.yaml): real names for transcript sources.
+
+Granola records two audio sources, the microphone ("You") and the system audio
+("System", everyone else on the call). The mapping names them, e.g.:
+
+    default:
+      microphone: Ada Lovelace
+    attendees:                 # the system audio of meetings with this attendee
+      bob@example.com: Bob Smith
+    documents:                 # one meeting
+      0f1e2d3c-...:
+        system: Interview panel
+
+Per-document names take precedence over attendee names, which take precedence
+over the defaults.
+"""
+
+from dataclasses import dataclass, field, replace
+from pathlib import Path
+from typing import Any
+
+import yaml
+
+from granola.cache.reader import TranscriptSegment
+from granola.config.defaults import config_dir
+from granola.config.file import ConfigError
+
+# Transcript sources that can be named
+SOURCES = ("microphone", "system")
+
+
+def get_default_speakers_path() -> Path:
+    """Return the default speaker mapping path (~/.config/granola/speakers.yaml)."""
+    return config_dir() / "speakers.yaml"
+
+
+@dataclass
+class SpeakerMap:
+    """Real names for the transcript sources of meetings."""
+
+    default: dict[str, str] = field(default_factory=dict)  # source -> name
+    attendees: dict[str, str] = field(default_factory=dict)  # lowercase email -> name
+    documents: dict[str, dict[str, str]] = field(default_factory=dict)  # doc ID -> names
+
+    def names(self, doc_id: str, attendee_emails: list[str]) -> dict[str, str]:
+        """Return source -> speaker name for a meeting."""
+        names = dict(self.default)
+        attendees = [
+            self.attendees[email.lower()]
+            for email in attendee_emails
+            if email and email.lower() in self.attendees
+        ]
+        if attendees:
+            names["system"] = ", ".join(dict.fromkeys(attendees))
+        names.update(self.documents.get(doc_id, {}))
+        return names
+
+    def apply(
+        self, segments: list[TranscriptSegment], doc_id: str, attendee_emails: list[str]
+    ) -> list[TranscriptSegment]:
+        """Return a meeting's transcript segments with their speakers named."""
+        names = self.names(doc_id, attendee_emails)
+        if not names:
+            return segments
+        return [
+            replace(segment, speaker=names[segment.source]) if segment.source in names else segment
+            for segment in segments
+        ]
+
+
+def load_speakers(path: Path) -> SpeakerMap:
+    """Load a speaker mapping file.
+
+    Raises:
+        ConfigError: If the file can't be read or isn't a valid mapping.
+    """
+    try:
+        data = yaml.safe_load(path.read_text(encoding="utf-8")) or {}
+    except OSError as e:
+        raise ConfigError(f"Failed to read speaker mapping {path}: {e}") from e
+    except yaml.YAMLError as e:
+        raise ConfigError(f"Invalid speaker mapping {path}: {e}") from e
+    if not isinstance(data, dict):
+        raise ConfigError(f"Invalid speaker mapping {path}: expected a mapping")
+
+    documents = _table(data, "documents", path)
+    return SpeakerMap(
+        default=_sources(_table(data, "default", path), f"{path}: default"),
+        attendees={
+            str(email).lower(): str(name) for email, name in _table(data, "attendees", path).items()
+        },
+        documents={
+            str(doc_id): _sources(names, f"{path}: documents.{doc_id}")
+            for doc_id, names in documents.items()
+        },
+    )
+
+
+def _table(data: dict[str, Any], key: str, path: Path) -> dict[str, Any]:
+    """Return a section of the mapping file, or an empty dict if it's missing."""
+    value = data.get(key) or {}
+    if not isinstance(value, dict):
+        raise ConfigError(f"Invalid speaker mapping {path}: {key} must be a mapping")
+    return value
+
+
+def _sources(names: Any, where: str) -> dict[str, str]:
+    """Validate a source -> name table."""
+    if not isinstance(names, dict):
+        raise ConfigError(f"Invalid speaker mapping {where}: expected source: name pairs")
+    unknown = sorted(str(source) for source in names if source not in SOURCES)
+    if unknown:
+        raise ConfigError(
+            f"Invalid speaker mapping {where}: unknown source {', '.join(unknown)} "
+            f"(use {' or '.join(SOURCES)})"
+        )
+    return {source: str(name) for source, name in names.items()}
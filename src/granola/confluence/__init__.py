@@ -0,0 +1,9 @@
+"""Confluence publishing support for Granola CLI."""
+
+from granola.confluence.client import ConfluenceClient, ConfluenceError, ConfluencePage
+
+__all__ = [
+    "ConfluenceClient",
+    "ConfluenceError",
+    "ConfluencePage",
+]
@@ -0,0 +1,214 @@
+"""Confluence REST API client for publishing meeting pages."""
+
+import logging
+import ssl
+from dataclasses import dataclass
+from typing import Any, Optional
+
+import certifi
+import httpx
+
+CONFLUENCE_TIMEOUT = 30  # seconds
+
+# Label prefix used to key pages by Granola document ID
+DOC_LABEL_PREFIX = "granola-"
+
+# Content property recording the source Granola document
+DOC_PROPERTY_KEY = "granola-document"
+
+
+def _get_ssl_context() -> ssl.SSLContext:
+    """Create an SSL context using certifi's CA bundle."""
+    return ssl.create_default_context(cafile=certifi.where())
+
+
+class ConfluenceError(Exception):
+    """Raised when a Confluence API request fails."""
+
+    def __init__(self, message: str, status_code: int | None = None):
+        super().__init__(message)
+        self.status_code = status_code
+
+
+@dataclass
+class ConfluencePage:
+    """A Confluence page as returned by the content API."""
+
+    id: str
+    title: str
+    version: int
+    body: str = ""
+
+
+def doc_label(doc_id: str) -> str:
+    """Return the label that identifies the page for a Granola document."""
+    return f"{DOC_LABEL_PREFIX}{doc_id}".lower()
+
+
+class ConfluenceClient:
+    """Client for the Confluence Cloud/Server content REST API."""
+
+    def __init__(
+        self,
+        base_url: str,
+        space_key: str,
+        user: str,
+        token: str,
+        logger: logging.Logger | None = None,
+    ):
+        """Initialize the client.
+
+        Args:
+            base_url: Confluence base URL (e.g. https://example.atlassian.net/wiki).
+            space_key: Key of the space pages are published to.
+            user: Account email or username.
+            token: API token or password.
+            logger: Optional logger for debug output.
+        """
+        self.base_url = base_url.rstrip("/")
+        self.space_key = space_key
+        self.logger = logger or logging.getLogger(__name__)
+        self._client = httpx.Client(
+            base_url=f"{self.base_url}/rest/api",
+            auth=(user, token),
+            timeout=CONFLUENCE_TIMEOUT,
+            verify=_get_ssl_context(),
+            headers={"Accept": "application/json"},
+        )
+
+    def close(self) -> None:
+        """Close the underlying HTTP client."""
+        self._client.close()
+
+    def find_page_by_title(self, title: str) -> Optional[ConfluencePage]:
+        """Find a page in the space by exact title."""
+        data = self._request(
+            "GET",
+            "/content",
+            params={
+                "spaceKey": self.space_key,
+                "title": title,
+                "type": "page",
+                "expand": "version,body.storage",
+            },
+        )
+        results = data.get("results", [])
+        return _parse_page(results[0]) if results else None
+
+    def find_page_by_doc_id(self, doc_id: str) -> Optional[ConfluencePage]:
+        """Find the page previously published for a Granola document."""
+        cql = f'space = "{self.space_key}" and type = page and label = "{doc_label(doc_id)}"'
+        data = self._request(
+            "GET",
+            "/content/search",
+            params={"cql": cql, "expand": "version,body.storage"},
+        )
+        results = data.get("results", [])
+        return _parse_page(results[0]) if results else None
+
+    def create_page(
+        self, title: str, body: str, parent_id: Optional[str] = None
+    ) -> ConfluencePage:
+        """Create a page in the space, optionally under a parent page."""
+        payload: dict[str, Any] = {
+            "type": "page",
+            "title": title,
+            "space": {"key": self.space_key},
+            "body": {"storage": {"value": body, "representation": "storage"}},
+        }
+        if parent_id:
+            payload["ancestors"] = [{"id": parent_id}]
+
+        data = self._request("POST", "/content", json=payload)
+        self.logger.debug(f"Created Confluence page '{title}' ({data.get('id')})")
+        return _parse_page(data)
+
+    def update_page(
+        self,
+        page: ConfluencePage,
+        title: str,
+        body: str,
+        parent_id: Optional[str] = None,
+    ) -> ConfluencePage:
+        """Replace the title and body of an existing page."""
+        payload: dict[str, Any] = {
+            "id": page.id,
+            "type": "page",
+            "title": title,
+            "version": {"number": page.version + 1},
+            "body": {"storage": {"value": body, "representation": "storage"}},
+        }
+        if parent_id:
+            payload["ancestors"] = [{"id": parent_id}]
+
+        data = self._request("PUT", f"/content/{page.id}", json=payload)
+        self.logger.debug(f"Updated Confluence page '{title}' ({page.id})")
+        return _parse_page(data)
+
+    def add_doc_label(self, page_id: str, doc_id: str) -> None:
+        """Attach the label that keys a page to its Granola document."""
+        self._request(
+            "POST",
+            f"/content/{page_id}/label",
+            json=[{"prefix": "global", "name": doc_label(doc_id)}],
+        )
+
+    def get_doc_property(self, page_id: str) -> Optional[dict[str, Any]]:
+        """Return the Granola document property of a page, if set."""
+        try:
+            return self._request("GET", f"/content/{page_id}/property/{DOC_PROPERTY_KEY}")
+        except ConfluenceError as e:
+            if e.status_code == 404:
+                return None
+            raise
+
+    def set_doc_property(self, page_id: str, doc_id: str, updated_at: str) -> None:
+        """Record the source document ID and timestamp on a page."""
+        value = {"id": doc_id, "updated_at": updated_at}
+        existing = self.get_doc_property(page_id)
+        if existing is None:
+            self._request(
+                "POST",
+                f"/content/{page_id}/property",
+                json={"key": DOC_PROPERTY_KEY, "value": value},
+            )
+            return
+
+        version = existing.get("version", {}).get("number", 1)
+        self._request(
+            "PUT",
+            f"/content/{page_id}/property/{DOC_PROPERTY_KEY}",
+            json={"key": DOC_PROPERTY_KEY, "value": value, "version": {"number": version + 1}},
+        )
+
+    def _request(self, method: str, path: str, **kwargs: Any) -> Any:
+        """Send a request and return the decoded JSON body."""
+        try:
+            response = self._client.request(method, path, **kwargs)
+            response.raise_for_status()
+        except httpx.HTTPStatusError as e:
+            body_preview = e.response.text[:200] if e.response.text else ""
+            raise ConfluenceError(
+                f"Confluence request failed: status={e.response.status_code}, body={body_preview}",
+                status_code=e.response.status_code,
+            ) from e
+        except httpx.RequestError as e:
+            raise ConfluenceError(f"Confluence request failed: {e}") from e
+
+        if not response.content:
+            return {}
+
+        try:
+            return response.json()
+        except ValueError as e:
+            raise ConfluenceError(f"Failed to parse Confluence response: {e}") from e
+
+
+def _parse_page(data: dict[str, Any]) -> ConfluencePage:
+    """Build a ConfluencePage from a content API object."""
+    return ConfluencePage(
+        id=str(data.get("id", "")),
+        title=data.get("title", ""),
+        version=data.get("version", {}).get("number", 1),
+        body=data.get("body", {}).get("storage", {}).get("value", ""),
+    )
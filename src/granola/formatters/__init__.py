@@ -3,5 +3,21 @@
 from granola.formatters.markdown import to_markdown_file
 from granola.formatters.transcript import format_transcript
 from granola.formatters.combined import format_combined
+from granola.formatters.ical import format_ical
+from granola.formatters.registry import (
+    Formatter,
+    formatter_names,
+    get_formatter,
+    register_formatter,
+)
 
-__all__ = ["to_markdown_file", "format_transcript", "format_combined"]
+__all__ = [
+    "to_markdown_file",
+    "format_transcript",
+    "format_combined",
+    "format_ical",
+    "Formatter",
+    "formatter_names",
+    "get_formatter",
+    "register_formatter",
+]
@@ -0,0 +1,254 @@
+"""Anki flashcards, as a deck package (.apkg) or a tab-separated text file."""
+
+import hashlib
+import io
+import json
+import os
+import re
+import sqlite3
+import tempfile
+import time
+import zipfile
+from dataclasses import dataclass, field
+
+# Note type of the cards: a front and a back
+_MODEL_NAME = "Granola meeting"
+_CSS = ".card { font-family: arial; font-size: 20px; text-align: left; }"
+
+# Schema of an Anki collection (legacy version 11, which every Anki version imports)
+_SCHEMA = """
+CREATE TABLE col (
+    id integer primary key, crt integer not null, mod integer not null,
+    scm integer not null, ver integer not null, dty integer not null, usn integer not null,
+    ls integer not null, conf text not null, models text not null, decks text not null,
+    dconf text not null, tags text not null
+);
+CREATE TABLE notes (
+    id integer primary key, guid text not null, mid integer not null, mod integer not null,
+    usn integer not null, tags text not null, flds text not null, sfld integer not null,
+    csum integer not null, flags integer not null, data text not null
+);
+CREATE TABLE cards (
+    id integer primary key, nid integer not null, did integer not null, ord integer not null,
+    mod integer not null, usn integer not null, type integer not null, queue integer not null,
+    due integer not null, ivl integer not null, factor integer not null, reps integer not null,
+    lapses integer not null, left integer not null, odue integer not null,
+    odid integer not null, flags integer not null, data text not null
+);
+CREATE TABLE revlog (
+    id integer primary key, cid integer not null, usn integer not null, ease integer not null,
+    ivl integer not null, lastIvl integer not null, factor integer not null,
+    time integer not null, type integer not null
+);
+CREATE TABLE graves (usn integer not null, oid integer not null, type integer not null);
+"""
+
+_DEFAULT_DECK_CONFIG = {
+    "id": 1,
+    "name": "Default",
+    "replayq": True,
+    "lapse": {"leechFails": 8, "minInt": 1, "delays": [10], "leechAction": 0, "mult": 0},
+    "rev": {
+        "perDay": 200,
+        "fuzz": 0.05,
+        "ivlFct": 1,
+        "maxIvl": 36500,
+        "ease4": 1.3,
+        "bury": True,
+        "minSpace": 1,
+    },
+    "timer": 0,
+    "maxTaken": 60,
+    "usn": 0,
+    "new": {
+        "perDay": 20,
+        "delays": [1, 10],
+        "separate": True,
+        "ints": [1, 4, 7],
+        "initialFactor": 2500,
+        "bury": True,
+        "order": 1,
+    },
+    "mod": 0,
+    "autoplay": True,
+}
+
+
+@dataclass
+class Card:
+    """A flashcard.
+
+    guid identifies the card across exports: importing a deck again updates
+    cards with the same guid instead of adding duplicates.
+    """
+
+    guid: str
+    front: str  # HTML
+    back: str  # HTML
+    tags: list[str] = field(default_factory=list)
+
+
+def format_anki_tsv(cards: list[Card]) -> str:
+    """Format cards as a text file for Anki's File > Import (front, back, tags).
+
+    Header lines tell Anki the columns (Anki 2.1.54 and later); older versions
+    need the separator and columns picked in the import dialog.
+    """
+    lines = ["#separator:tab", "#html:true", "#guid column:1", "#tags column:4"]
+    for card in cards:
+        fields = [card.guid, card.front, card.back, " ".join(card.tags)]
+        lines.append("\t".join(_tsv_field(f) for f in fields))
+    return "\n".join(lines) + "\n"
+
+
+def format_apkg(deck_name: str, cards: list[Card]) -> bytes:
+    """Build an Anki deck package of cards.
+
+    Args:
+        deck_name: Name of the deck the cards are imported into ("::" nests decks).
+        cards: Cards to include.
+
+    Returns:
+        The .apkg file's bytes.
+    """
+    now = int(time.time())
+    deck_id = _stable_id(f"deck:{deck_name}")
+    model_id = _stable_id(f"model:{_MODEL_NAME}")
+
+    model = {
+        "id": model_id,
+        "name": _MODEL_NAME,
+        "type": 0,
+        "mod": now,
+        "usn": -1,
+        "sortf": 0,
+        "did": deck_id,
+        "tmpls": [
+            {
+                "name": "Card 1",
+                "ord": 0,
+                "qfmt": "{{Front}}",
+                "afmt": "{{FrontSide}}<hr id=answer>{{Back}}",
+                "did": None,
+                "bqfmt": "",
+                "bafmt": "",
+            }
+        ],
+        "flds": [
+            {"name": name, "ord": i, "sticky": False, "rtl": False, "font": "Arial", "size": 20}
+            for i, name in enumerate(("Front", "Back"))
+        ],
+        "css": _CSS,
+        "latexPre": "\\documentclass[12pt]{article}\n\\begin{document}\n",
+        "latexPost": "\\end{document}",
+        "tags": [],
+        "vers": [],
+        "req": [[0, "all", [0]]],
+    }
+    decks = {
+        str(deck_id): _deck(deck_id, deck_name, now),
+        "1": _deck(1, "Default", now),
+    }
+    conf = {"activeDecks": [1], "curDeck": 1, "nextPos": len(cards) + 1, "sortType": "noteFld"}
+
+    fd, db_name = tempfile.mkstemp(suffix=".anki2")
+    os.close(fd)
+    try:
+        connection = sqlite3.connect(db_name)
+        try:
+            connection.executescript(_SCHEMA)
+            connection.execute(
+                "INSERT INTO col VALUES (1, ?, ?, ?, 11, 0, 0, 0, ?, ?, ?, ?, '{}')",
+                (
+                    now,
+                    now * 1000,
+                    now * 1000,
+                    json.dumps(conf),
+                    json.dumps({str(model_id): model}),
+                    json.dumps(decks),
+                    json.dumps({"1": _DEFAULT_DECK_CONFIG}),
+                ),
+            )
+            for position, card in enumerate(cards):
+                note_id = now * 1000 + position
+                tags = f" {' '.join(card.tags)} " if card.tags else ""
+                connection.execute(
+                    "INSERT INTO notes VALUES (?, ?, ?, ?, -1, ?, ?, ?, ?, 0, '')",
+                    (
+                        note_id,
+                        card.guid,
+                        model_id,
+                        now,
+                        tags,
+                        f"{card.front}\x1f{card.back}",
+                        _strip_html(card.front),
+                        _checksum(card.front),
+                    ),
+                )
+                # A new card (type and queue 0), due in the order the cards were given
+                connection.execute(
+                    "INSERT INTO cards VALUES "
+                    "(?, ?, ?, 0, ?, -1, 0, 0, ?, 0, 0, 0, 0, 0, 0, 0, 0, '')",
+                    (note_id, note_id, deck_id, now, position + 1),
+                )
+            connection.commit()
+        finally:
+            connection.close()
+
+        return _package(db_name)
+    finally:
+        os.unlink(db_name)
+
+
+def card_guid(*parts: str) -> str:
+    """Return a stable card guid from the values that identify a card."""
+    return hashlib.sha1("\x1f".join(parts).encode("utf-8")).hexdigest()[:16]
+
+
+def _package(collection_path: str) -> bytes:
+    """Zip a collection into a deck package, with an empty media map."""
+    buffer = io.BytesIO()
+    with zipfile.ZipFile(buffer, "w", zipfile.ZIP_DEFLATED) as zf:
+        zf.write(collection_path, "collection.anki2")
+        zf.writestr("media", "{}")
+    return buffer.getvalue()
+
+
+def _deck(deck_id: int, name: str, now: int) -> dict[str, object]:
+    """Return the definition of a deck."""
+    return {
+        "id": deck_id,
+        "name": name,
+        "mod": now,
+        "usn": -1,
+        "desc": "",
+        "dyn": 0,
+        "conf": 1,
+        "collapsed": False,
+        "newToday": [0, 0],
+        "revToday": [0, 0],
+        "lrnToday": [0, 0],
+        "timeToday": [0, 0],
+        "extendNew": 10,
+        "extendRev": 50,
+    }
+
+
+def _stable_id(key: str) -> int:
+    """Return an ID that is the same on every export (Anki IDs are 64-bit)."""
+    return int(hashlib.sha1(key.encode("utf-8")).hexdigest()[:12], 16)
+
+
+def _strip_html(text: str) -> str:
+    """Return the text of an HTML field (Anki sorts and checksums it)."""
+    return re.sub(r"<[^>]+>", "", text).strip()
+
+
+def _checksum(field_html: str) -> int:
+    """Return Anki's checksum of a field: the first 8 hex digits of its text's SHA-1."""
+    return int(hashlib.sha1(_strip_html(field_html).encode("utf-8")).hexdigest()[:8], 16)
+
+
+def _tsv_field(value: str) -> str:
+    """Make a value safe for a tab-separated field."""
+    return " ".join(value.replace("\t", " ").splitlines())
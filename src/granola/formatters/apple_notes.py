@@ -0,0 +1,48 @@
+"""Apple Notes formatting: notes bodies are HTML."""
+
+import html
+from typing import Any
+
+from granola.cache.reader import TranscriptSegment
+from granola.formatters.combined import format_transcript
+from granola.prosemirror.converter import from_markdown, to_html
+
+
+def format_apple_notes(
+    title: str,
+    doc_id: str,
+    created_at: str,
+    updated_at: str,
+    notes_content: str,
+    segments: list[TranscriptSegment],
+    folders: list[str],
+    metadata: dict[str, Any] | None = None,
+) -> str:
+    """Format notes and transcript as the HTML body of an Apple Notes note.
+
+    Notes takes a note's name from its first line, so the body starts with the
+    title as a heading.
+
+    Args:
+        title: Document title.
+        doc_id: Document ID.
+        created_at: Creation timestamp.
+        updated_at: Update timestamp.
+        notes_content: Markdown notes content.
+        segments: Transcript segments.
+        folders: List of folder names.
+        metadata: Extra fields (unused).
+
+    Returns:
+        HTML string.
+    """
+    parts = [f"<h1>{html.escape(title or 'Untitled')}</h1>"]
+    if notes_content and notes_content.strip():
+        parts.append(to_html(from_markdown(notes_content)))
+    if segments:
+        parts.append("<h2>Transcript</h2>")
+        parts.extend(
+            f"<div>{html.escape(line, quote=False) or '<br>'}</div>"
+            for line in format_transcript(segments).splitlines()
+        )
+    return "\n".join(parts)
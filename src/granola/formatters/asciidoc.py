@@ -0,0 +1,88 @@
+"""AsciiDoc formatting with document attributes, for Asciidoctor and Antora."""
+
+from typing import Any
+
+from granola.cache.reader import TranscriptSegment
+from granola.prosemirror.converter import from_markdown, to_asciidoc
+from granola.utils.dates import format_time, parse_timestamp
+
+
+def format_asciidoc(
+    title: str,
+    doc_id: str,
+    created_at: str,
+    updated_at: str,
+    notes_content: str,
+    segments: list[TranscriptSegment],
+    folders: list[str],
+    metadata: dict[str, Any] | None = None,
+) -> str:
+    """Format notes and transcript as an AsciiDoc document.
+
+    Metadata goes in document attributes (:granola-id:, :revdate:, ...) in the
+    header instead of YAML frontmatter; folders become :page-tags: for Antora.
+    Headings in the notes become sections below the document title.
+
+    Args:
+        title: Document title.
+        doc_id: Document ID.
+        created_at: Creation timestamp.
+        updated_at: Update timestamp.
+        notes_content: Markdown notes content.
+        segments: Transcript segments.
+        folders: List of folder names.
+        metadata: Extra fields for the document attributes (e.g. owner), if any.
+
+    Returns:
+        AsciiDoc string.
+    """
+    attributes: dict[str, object] = {"granola-id": doc_id}
+    created = parse_timestamp(created_at)
+    if created:
+        attributes["revdate"] = created.strftime("%Y-%m-%d")
+    attributes["created"] = created_at
+    attributes["updated"] = updated_at
+    if folders:
+        attributes["page-tags"] = ", ".join(folders)
+    for key, value in (metadata or {}).items():
+        attributes.setdefault(key.replace("_", "-"), value)
+
+    lines = [f"= {title or 'Untitled'}"]
+    lines.extend(f":{name}: {_attribute_value(value)}" for name, value in attributes.items())
+
+    lines.extend(["", "== Notes", ""])
+    notes = to_asciidoc(from_markdown(notes_content), heading_offset=1) if notes_content else ""
+    lines.append(notes.rstrip() or "_No notes_")
+
+    lines.extend(["", "== Transcript", ""])
+    lines.append(_format_transcript(segments) if segments else "_No transcript available_")
+
+    return "\n".join(lines) + "\n"
+
+
+def _attribute_value(value: Any) -> str:
+    """Format a value for a document attribute (attributes are single-line)."""
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    if isinstance(value, (list, tuple)):
+        return "; ".join(_attribute_value(v) for v in value)
+    if isinstance(value, dict):
+        return ", ".join(str(v) for v in value.values() if v)
+    return " ".join(str(value).split())
+
+
+def _format_transcript(segments: list[TranscriptSegment]) -> str:
+    """Format transcript segments as a labeled list of timestamped speakers."""
+    lines: list[str] = []
+    for segment in segments:
+        if segment.heading:
+            # Time-blocked transcript (see formatters.sections)
+            if lines:
+                lines.append("")
+            lines.extend([f"=== {segment.heading}", ""])
+            if segment.summary:
+                lines.extend([f"_{segment.summary}_", ""])
+        started = parse_timestamp(segment.start_timestamp)
+        time = format_time(started) if started else segment.start_timestamp
+        lines.append(f"{time} {segment.speaker_name}:: {segment.text}")
+    return "\n".join(lines)
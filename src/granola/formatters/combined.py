@@ -1,8 +1,10 @@
 """Combined notes and transcript formatting."""
 
 from datetime import datetime
+from typing import Any
 
 from granola.cache.reader import TranscriptSegment
+from granola.utils.dates import format_time, format_timestamp
 
 
 def format_combined(
@@ -13,6 +15,7 @@ def format_combined(
     notes_content: str,
     segments: list[TranscriptSegment],
     folders: list[str],
+    metadata: dict[str, Any] | None = None,
 ) -> str:
     """Format notes and transcript into a single text file.
 
@@ -24,6 +27,7 @@ def format_combined(
         notes_content: Plain text notes content.
         segments: Transcript segments.
         folders: List of folder names.
+        metadata: Extra fields for the header (e.g. owner), if any.
 
     Returns:
         Combined formatted string.
@@ -39,14 +43,22 @@ def format_combined(
     lines.append(f"ID: {doc_id}")
 
     if created_at:
-        lines.append(f"Created: {created_at}")
+        lines.append(f"Created: {format_timestamp(created_at)}")
 
     if updated_at:
-        lines.append(f"Updated: {updated_at}")
+        lines.append(f"Updated: {format_timestamp(updated_at)}")
 
     if folders:
         lines.append(f"Folders: {', '.join(folders)}")
 
+    for key, value in (metadata or {}).items():
+        if isinstance(value, list) and any(isinstance(v, dict) for v in value):
+            # Records (e.g. attendees) get a line each
+            lines.append(f"{_header_label(key)}:")
+            lines.extend(f"  - {_header_value(v)}" for v in value)
+        else:
+            lines.append(f"{_header_label(key)}: {_header_value(value)}")
+
     lines.append("=" * 80)
 
     # Notes section
@@ -67,16 +79,30 @@ def format_combined(
     lines.append("")
 
     if segments:
-        for segment in segments:
-            timestamp = _parse_timestamp(segment.start_timestamp)
-            speaker = "You" if segment.source == "microphone" else "System"
-            lines.append(f"[{timestamp}] {speaker}: {segment.text}")
+        lines.append(format_transcript(segments))
     else:
         lines.append("(No transcript available)")
 
     return "\n".join(lines)
 
 
+def _header_label(key: str) -> str:
+    """Turn a metadata key into a header label (share_url -> Share URL)."""
+    words = [w.upper() if w in ("id", "uid", "url") else w for w in key.split("_")]
+    return " ".join([words[0][:1].upper() + words[0][1:], *words[1:]])
+
+
+def _header_value(value: Any) -> str:
+    """Format a metadata value for the header."""
+    if isinstance(value, bool):
+        return "yes" if value else "no"
+    if isinstance(value, (list, tuple)):
+        return ", ".join(str(v) for v in value)
+    if isinstance(value, dict):
+        return ", ".join(str(v) for v in value.values() if v)
+    return str(value)
+
+
 def format_transcript(segments: list[TranscriptSegment]) -> str:
     """Format transcript segments into plain text.
 
@@ -91,15 +117,21 @@ def format_transcript(segments: list[TranscriptSegment]) -> str:
 
     lines: list[str] = []
     for segment in segments:
+        if segment.heading:
+            # Time-blocked transcript (see formatters.sections)
+            if lines:
+                lines.append("")
+            lines.extend([f"### {segment.heading}", ""])
+            if segment.summary:
+                lines.extend([f"> {segment.summary}", ""])
         timestamp = _parse_timestamp(segment.start_timestamp)
-        speaker = "You" if segment.source == "microphone" else "System"
-        lines.append(f"[{timestamp}] {speaker}: {segment.text}")
+        lines.append(f"[{timestamp}] {segment.speaker_name}: {segment.text}")
 
     return "\n".join(lines)
 
 
 def _parse_timestamp(timestamp: str) -> str:
-    """Convert ISO 8601 timestamp to HH:MM:SS format.
+    """Convert ISO 8601 timestamp to the time format (HH:MM:SS by default).
 
     Args:
         timestamp: ISO 8601 timestamp string.
@@ -110,6 +142,6 @@ def _parse_timestamp(timestamp: str) -> str:
     try:
         ts = timestamp.replace("Z", "+00:00")
         dt = datetime.fromisoformat(ts)
-        return dt.strftime("%H:%M:%S")
+        return format_time(dt)
     except ValueError:
         return timestamp
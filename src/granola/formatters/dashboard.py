@@ -0,0 +1,168 @@
+"""Dashboard: a self-contained HTML page summarizing meetings.
+
+The page has no external assets: charts are inline SVG and the meeting table is
+filtered by a few lines of inline JavaScript, so it can be opened from disk,
+mailed, or put on any static host.
+"""
+
+import html
+from collections import Counter
+from datetime import date, datetime, timedelta
+
+from granola.reports.meetings import Meeting
+from granola.reports.time import time_report
+
+_STYLE = """
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto;
+       max-width: 60em; padding: 0 1em; color: #222; }
+h1 { margin-bottom: 0.2em; }
+.summary { color: #666; margin-top: 0; }
+.charts { display: flex; flex-wrap: wrap; gap: 2em; }
+.chart { flex: 1 1 26em; }
+.chart svg { width: 100%; height: auto; }
+.bar { fill: #4a7fd6; }
+.label { font-size: 11px; fill: #555; }
+input[type=search] { width: 100%; padding: 0.5em; font-size: 1em; margin: 1em 0;
+                     box-sizing: border-box; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 0.35em 0.6em; border-bottom: 1px solid #eee; }
+td.num { text-align: right; }
+"""
+
+_SCRIPT = """
+document.getElementById("search").addEventListener("input", function (e) {
+  var words = e.target.value.toLowerCase().split(/\\s+/).filter(Boolean);
+  document.querySelectorAll("#meetings tbody tr").forEach(function (row) {
+    var text = row.textContent.toLowerCase();
+    row.hidden = !words.every(function (w) { return text.indexOf(w) >= 0; });
+  });
+});
+"""
+
+
+def format_dashboard(
+    meetings: list[Meeting],
+    links: dict[str, str] | None = None,
+    title: str = "Granola meetings",
+    weeks: int = 26,
+) -> str:
+    """Format meetings as a dashboard page.
+
+    Args:
+        meetings: Meetings to include, oldest first.
+        links: URL of each meeting's exported file (document ID -> URL), if any.
+        title: Page title.
+        weeks: Number of weeks the meetings-per-week chart covers, up to the
+            last meeting.
+
+    Returns:
+        The HTML page.
+    """
+    links = links or {}
+    recorded = time_report(meetings, "folder")
+    summary = f"{len(meetings)} meetings, {recorded.total.hours:.1f} recorded hours"
+    if meetings:
+        first, last = meetings[0].start.astimezone(), meetings[-1].start.astimezone()
+        summary += f", {first:%Y-%m-%d} to {last:%Y-%m-%d}"
+
+    week_counts = _meetings_per_week(meetings, weeks)
+    charts = [
+        _chart(
+            "Meetings per week",
+            [(f"{week:%b %d}", count, str(count)) for week, count in week_counts],
+        ),
+        _chart(
+            "Recorded hours by folder",
+            [(row.group, row.hours, f"{row.hours:.1f}") for row in recorded.rows[:12]],
+        ),
+    ]
+
+    rows = []
+    for meeting in reversed(meetings):
+        name = html.escape(meeting.title)
+        link = links.get(meeting.id)
+        cell = f'<a href="{html.escape(link)}">{name}</a>' if link else name
+        people = ", ".join(attendee.name for attendee in meeting.attendees)
+        minutes = "" if meeting.minutes is None else str(meeting.minutes)
+        rows.append(
+            "<tr>"
+            f"<td>{meeting.start.astimezone():%Y-%m-%d %H:%M}</td>"
+            f"<td>{cell}</td>"
+            f"<td>{html.escape(', '.join(meeting.folders))}</td>"
+            f"<td>{html.escape(people)}</td>"
+            f'<td class="num">{minutes}</td>'
+            "</tr>"
+        )
+
+    return f"""<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{html.escape(title)}</title>
+<style>{_STYLE}</style>
+</head>
+<body>
+<h1>{html.escape(title)}</h1>
+<p class="summary">{html.escape(summary)}</p>
+<div class="charts">
+{"".join(charts)}
+</div>
+<input type="search" id="search" placeholder="Search meetings, folders, people…">
+<table id="meetings">
+<thead><tr><th>Date</th><th>Meeting</th><th>Folders</th><th>With</th><th>Minutes</th></tr></thead>
+<tbody>
+{chr(10).join(rows)}
+</tbody>
+</table>
+<script>{_SCRIPT}</script>
+</body>
+</html>
+"""
+
+
+def _meetings_per_week(meetings: list[Meeting], weeks: int) -> list[tuple[date, int]]:
+    """Count meetings per week (starting Monday) for the weeks up to the last meeting."""
+    if not meetings:
+        return []
+    counts: Counter[date] = Counter(_week_start(m.start) for m in meetings)
+    last = _week_start(meetings[-1].start)
+    return [
+        (week, counts[week])
+        for week in (last - timedelta(weeks=i) for i in range(weeks - 1, -1, -1))
+    ]
+
+
+def _week_start(moment: datetime) -> date:
+    """Return the Monday of a moment's week, in local time."""
+    day = moment.astimezone().date()
+    return day - timedelta(days=day.weekday())
+
+
+def _chart(title: str, bars: list[tuple[str, float, str]]) -> str:
+    """Draw a vertical bar chart as inline SVG (label, value, value text per bar)."""
+    width, height, base = 480, 200, 170
+    top = max((value for _, value, _ in bars), default=0) or 1
+    step = width / max(len(bars), 1)
+    # Label every bar if they fit, else every few
+    every = max(1, round(len(bars) / 12))
+    parts = []
+    for i, (label, value, text) in enumerate(bars):
+        x = i * step
+        bar_height = value / top * (base - 20)
+        parts.append(
+            f'<rect class="bar" x="{x + step * 0.1:.1f}" y="{base - bar_height:.1f}" '
+            f'width="{step * 0.8:.1f}" height="{bar_height:.1f}">'
+            f"<title>{html.escape(label)}: {html.escape(text)}</title></rect>"
+        )
+        if i % every == 0:
+            parts.append(
+                f'<text class="label" x="{x + step / 2:.1f}" y="{base + 15}" '
+                f'text-anchor="middle">{html.escape(label[:14])}</text>'
+            )
+    return (
+        f'<div class="chart"><h2>{html.escape(title)}</h2>'
+        f'<svg viewBox="0 0 {width} {height}" role="img" aria-label="{html.escape(title)}">'
+        + "".join(parts)
+        + "</svg></div>"
+    )
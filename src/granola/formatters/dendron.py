@@ -0,0 +1,83 @@
+"""Dendron note formatting with dot-hierarchy filenames."""
+
+from pathlib import Path
+from typing import Any
+
+import yaml
+
+from granola.cache.reader import TranscriptSegment
+from granola.formatters.markdown import format_markdown_body
+from granola.utils.dates import parse_timestamp
+from granola.utils.filename import slugify
+from granola.writers.sync_writer import ExportDoc
+
+# Root of the Dendron hierarchy that meeting notes are placed under
+DENDRON_ROOT = "meetings"
+
+
+def format_dendron(
+    title: str,
+    doc_id: str,
+    created_at: str,
+    updated_at: str,
+    notes_content: str,
+    segments: list[TranscriptSegment],
+    folders: list[str],
+    metadata: dict[str, Any] | None = None,
+) -> str:
+    """Format notes and transcript as a Dendron note.
+
+    Dendron expects created/updated as epoch milliseconds.
+
+    Args:
+        title: Document title.
+        doc_id: Document ID.
+        created_at: Creation timestamp.
+        updated_at: Update timestamp.
+        notes_content: Markdown notes content.
+        segments: Transcript segments.
+        folders: List of folder names.
+        metadata: Extra fields for the frontmatter (e.g. owner), if any.
+
+    Returns:
+        Markdown string with Dendron frontmatter.
+    """
+    fields: dict[str, object] = {
+        "id": doc_id,
+        "title": title or "Untitled",
+        "desc": "",
+        "updated": _epoch_ms(updated_at),
+        "created": _epoch_ms(created_at),
+    }
+    tags = [slugify(folder) for folder in folders] + (metadata or {}).get("tags", [])
+    if tags:
+        fields["tags"] = list(dict.fromkeys(tags))
+    for key, value in (metadata or {}).items():
+        fields.setdefault(key, value)
+
+    frontmatter = yaml.dump(
+        fields, default_flow_style=False, allow_unicode=True, sort_keys=False
+    ).strip()
+
+    return f"---\n{frontmatter}\n---\n\n" + format_markdown_body(notes_content, segments)
+
+
+def dendron_layout(doc: ExportDoc) -> list[Path]:
+    """Return the note path for a document.
+
+    Format: meetings.<folder>.YYYY.MM.DD.<slug>-<short_id>.md
+
+    Dendron hierarchies are trees, so a document in several folders is filed
+    under its first folder only.
+    """
+    folder = slugify(doc.folders[0], fallback="unnamed") if doc.folders else "uncategorized"
+    short_id = doc.id[:8] if len(doc.id) >= 8 else doc.id
+    date_part = doc.local_start.strftime("%Y.%m.%d")
+    slug = slugify(doc.title or "")
+    return [Path(f"{DENDRON_ROOT}.{folder}.{date_part}.{slug}-{short_id}.md")]
+
+
+def _epoch_ms(timestamp: str) -> int:
+    """Convert an ISO 8601 timestamp to epoch milliseconds (0 if unparseable)."""
+    dt = parse_timestamp(timestamp)
+    return int(dt.timestamp() * 1000) if dt else 0
@@ -0,0 +1,200 @@
+"""EPUB 3 compilation of meeting notes, one chapter per meeting."""
+
+import html
+import io
+import uuid
+import zipfile
+from dataclasses import dataclass
+from datetime import datetime, timezone
+from html.parser import HTMLParser
+from itertools import groupby
+
+# Elements written without a closing tag in XHTML (<br/>)
+_VOID_ELEMENTS = frozenset(
+    "area base br col embed hr img input link meta source track wbr".split()
+)
+
+_STYLESHEET = """\
+body { font-family: serif; line-height: 1.4; }
+h1 { font-size: 1.5em; margin-bottom: 0.2em; }
+.meta { color: #666; font-size: 0.9em; margin-top: 0; }
+"""
+
+
+@dataclass
+class Chapter:
+    """A meeting to be written as a chapter."""
+
+    title: str
+    created_at: datetime
+    folder: str  # the part of the book the chapter is in
+    body: str  # HTML fragment (made well-formed XHTML when written)
+
+
+def format_epub(title: str, chapters: list[Chapter], language: str = "en") -> bytes:
+    """Build an EPUB 3 book of meetings, grouped into parts by folder.
+
+    Parts are ordered by folder name and chapters by meeting time, oldest first.
+    The table of contents nests each part's meetings under it.
+
+    Args:
+        title: Book title.
+        chapters: Meetings to include.
+        language: Language of the book (BCP 47 code).
+
+    Returns:
+        The .epub file's bytes.
+    """
+    ordered = sorted(chapters, key=lambda c: (c.folder.casefold(), c.created_at))
+    files = [(f"chapter-{i:04d}.xhtml", chapter) for i, chapter in enumerate(ordered, 1)]
+    parts = [
+        (folder, list(items)) for folder, items in groupby(files, key=lambda f: f[1].folder)
+    ]
+
+    buffer = io.BytesIO()
+    with zipfile.ZipFile(buffer, "w", zipfile.ZIP_DEFLATED) as zf:
+        # The mimetype must come first, uncompressed
+        zf.writestr("mimetype", "application/epub+zip", compress_type=zipfile.ZIP_STORED)
+        zf.writestr(
+            "META-INF/container.xml",
+            '<?xml version="1.0" encoding="UTF-8"?>\n'
+            '<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">'
+            '<rootfiles><rootfile full-path="OEBPS/content.opf" '
+            'media-type="application/oebps-package+xml"/></rootfiles></container>',
+        )
+        zf.writestr("OEBPS/style.css", _STYLESHEET)
+        zf.writestr("OEBPS/nav.xhtml", _nav(title, parts, language))
+        for name, chapter in files:
+            zf.writestr(f"OEBPS/{name}", _chapter(chapter, language))
+        zf.writestr("OEBPS/content.opf", _package(title, [name for name, _ in files], language))
+    return buffer.getvalue()
+
+
+def _page(title: str, body: str, language: str, extra_ns: str = "") -> str:
+    """Wrap body in an XHTML page."""
+    return (
+        '<?xml version="1.0" encoding="UTF-8"?>\n<!DOCTYPE html>\n'
+        f'<html xmlns="http://www.w3.org/1999/xhtml"{extra_ns} '
+        f'xml:lang="{language}" lang="{language}">\n'
+        f"<head><title>{html.escape(title)}</title>"
+        '<link rel="stylesheet" type="text/css" href="style.css"/></head>\n'
+        f"<body>\n{body}\n</body>\n</html>\n"
+    )
+
+
+def _chapter(chapter: Chapter, language: str) -> str:
+    """Render a meeting's chapter page."""
+    meta = f"{chapter.created_at.strftime('%Y-%m-%d %H:%M')} · {chapter.folder}"
+    body = (
+        f"<h1>{html.escape(chapter.title)}</h1>\n"
+        f'<p class="meta">{html.escape(meta)}</p>\n'
+        f"{xhtml(chapter.body)}"
+    )
+    return _page(chapter.title, body, language)
+
+
+def _nav(title: str, parts: list[tuple[str, list[tuple[str, Chapter]]]], language: str) -> str:
+    """Render the navigation document (table of contents).
+
+    Args:
+        title: Book title.
+        parts: Folder name -> its chapters, as (file name, chapter) pairs.
+        language: Language of the book.
+    """
+    items = []
+    for folder, chapters in parts:
+        links = "".join(
+            f'<li><a href="{name}">{html.escape(_toc_title(chapter))}</a></li>'
+            for name, chapter in chapters
+        )
+        # A part links to its first meeting (EPUB requires every entry to link)
+        first = chapters[0][0]
+        items.append(f'<li><a href="{first}">{html.escape(folder)}</a><ol>{links}</ol></li>')
+    body = (
+        f'<nav epub:type="toc" id="toc"><h1>{html.escape(title)}</h1>'
+        f"<ol>{''.join(items)}</ol></nav>"
+    )
+    return _page(title, body, language, ' xmlns:epub="http://www.idpf.org/2007/ops"')
+
+
+def _toc_title(chapter: Chapter) -> str:
+    """Return a chapter's table of contents entry: its date and title."""
+    return f"{chapter.created_at.strftime('%Y-%m-%d')} {chapter.title}"
+
+
+def _package(title: str, files: list[str], language: str) -> str:
+    """Render the package document (metadata, manifest and reading order)."""
+    modified = datetime.now(timezone.utc).strftime("%Y-%m-%dT%H:%M:%SZ")
+    manifest = "".join(
+        f'<item id="c{i}" href="{name}" media-type="application/xhtml+xml"/>'
+        for i, name in enumerate(files)
+    )
+    spine = "".join(f'<itemref idref="c{i}"/>' for i in range(len(files)))
+    return (
+        '<?xml version="1.0" encoding="UTF-8"?>\n'
+        '<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="uid">'
+        '<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">'
+        f'<dc:identifier id="uid">urn:uuid:{uuid.uuid4()}</dc:identifier>'
+        f"<dc:title>{html.escape(title)}</dc:title>"
+        f"<dc:language>{html.escape(language)}</dc:language>"
+        f'<meta property="dcterms:modified">{modified}</meta>'
+        "</metadata><manifest>"
+        '<item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>'
+        '<item id="css" href="style.css" media-type="text/css"/>'
+        f"{manifest}</manifest>"
+        f'<spine><itemref idref="nav"/>{spine}</spine></package>'
+    )
+
+
+def xhtml(fragment: str) -> str:
+    """Return an HTML fragment as well-formed XHTML.
+
+    Void elements are self-closed, unclosed elements are closed, stray end tags
+    are dropped, and text and attributes are escaped. Notes pasted from the web
+    are often loose HTML that e-readers would reject.
+    """
+    parser = _XHTMLWriter()
+    parser.feed(fragment)
+    parser.close()
+    return parser.result()
+
+
+class _XHTMLWriter(HTMLParser):
+    """Re-serializes parsed HTML as XHTML."""
+
+    def __init__(self) -> None:
+        super().__init__(convert_charrefs=True)
+        self.parts: list[str] = []
+        self.open: list[str] = []
+
+    def handle_starttag(self, tag: str, attrs: list[tuple[str, str | None]]) -> None:
+        rendered = "".join(
+            f' {name}="{html.escape(value if value is not None else name)}"'
+            for name, value in attrs
+        )
+        if tag in _VOID_ELEMENTS:
+            self.parts.append(f"<{tag}{rendered}/>")
+        else:
+            self.parts.append(f"<{tag}{rendered}>")
+            self.open.append(tag)
+
+    def handle_startendtag(self, tag: str, attrs: list[tuple[str, str | None]]) -> None:
+        self.handle_starttag(tag, attrs)
+        if tag not in _VOID_ELEMENTS:
+            self.handle_endtag(tag)
+
+    def handle_endtag(self, tag: str) -> None:
+        if tag not in self.open:
+            return
+        while self.open:
+            current = self.open.pop()
+            self.parts.append(f"</{current}>")
+            if current == tag:
+                break
+
+    def handle_data(self, data: str) -> None:
+        self.parts.append(html.escape(data, quote=False))
+
+    def result(self) -> str:
+        """Return the XHTML, closing any elements left open."""
+        return "".join(self.parts) + "".join(f"</{tag}>" for tag in reversed(self.open))
@@ -0,0 +1,67 @@
+"""Hugo page bundle formatting."""
+
+from pathlib import Path
+from typing import Any
+
+import yaml
+
+from granola.cache.reader import TranscriptSegment
+from granola.formatters.markdown import format_markdown_body
+from granola.utils.filename import slugify
+from granola.writers.sync_writer import ExportDoc
+
+# Hugo content section that meeting bundles are written to
+HUGO_SECTION = Path("content") / "meetings"
+
+
+def format_hugo(
+    title: str,
+    doc_id: str,
+    created_at: str,
+    updated_at: str,
+    notes_content: str,
+    segments: list[TranscriptSegment],
+    folders: list[str],
+    metadata: dict[str, Any] | None = None,
+) -> str:
+    """Format notes and transcript as a Hugo page with YAML frontmatter.
+
+    Granola folders become Hugo tags, followed by any tags in metadata; the document
+    ID and any other extra metadata are kept under params.
+
+    Args:
+        title: Document title.
+        doc_id: Document ID.
+        created_at: Creation timestamp.
+        updated_at: Update timestamp.
+        notes_content: Markdown notes content.
+        segments: Transcript segments.
+        folders: List of folder names.
+        metadata: Extra fields for the frontmatter (e.g. owner), if any.
+
+    Returns:
+        Markdown string with Hugo frontmatter.
+    """
+    params = dict(metadata or {})
+    fields: dict[str, object] = {
+        "title": title or "Untitled",
+        "date": created_at,
+        "lastmod": updated_at,
+        "draft": False,
+        "tags": list(dict.fromkeys([*folders, *params.pop("tags", [])])),
+        "params": {"granola_id": doc_id, **params},
+    }
+
+    frontmatter = yaml.dump(
+        fields, default_flow_style=False, allow_unicode=True, sort_keys=False
+    ).strip()
+
+    return f"---\n{frontmatter}\n---\n\n" + format_markdown_body(notes_content, segments)
+
+
+def hugo_layout(doc: ExportDoc) -> list[Path]:
+    """Return the page bundle path for a document: content/meetings/<slug>/index.md."""
+    short_id = doc.id[:8] if len(doc.id) >= 8 else doc.id
+    date_prefix = doc.local_start.strftime("%Y-%m-%d")
+    slug = f"{date_prefix}-{slugify(doc.title or '')}-{short_id}"
+    return [HUGO_SECTION / slug / "index.md"]
@@ -0,0 +1,201 @@
+"""iCalendar (RFC 5545) formatting of meetings, and reading of calendar exports."""
+
+from dataclasses import dataclass
+from datetime import datetime, timezone
+from zoneinfo import ZoneInfo, ZoneInfoNotFoundError
+
+PRODID = "-//Granola CLI//Meetings//EN"
+
+
+@dataclass
+class CalendarEvent:
+    """A meeting to be written as a VEVENT."""
+
+    uid: str
+    title: str
+    start: datetime
+    end: datetime
+    description: str = ""
+    url: str = ""
+    recurring: bool = False  # a series (RRULE); start is its first occurrence
+
+
+def format_ical(events: list[CalendarEvent]) -> str:
+    """Format meetings as an iCalendar document.
+
+    Args:
+        events: Meetings to include.
+
+    Returns:
+        iCalendar text with CRLF line endings.
+    """
+    stamp = _format_datetime(datetime.now(timezone.utc))
+
+    lines: list[str] = [
+        "BEGIN:VCALENDAR",
+        "VERSION:2.0",
+        f"PRODID:{PRODID}",
+        "CALSCALE:GREGORIAN",
+    ]
+
+    for event in sorted(events, key=lambda e: e.start):
+        lines.append("BEGIN:VEVENT")
+        lines.append(f"UID:{event.uid}")
+        lines.append(f"DTSTAMP:{stamp}")
+        lines.append(f"DTSTART:{_format_datetime(event.start)}")
+        lines.append(f"DTEND:{_format_datetime(event.end)}")
+        lines.append(f"SUMMARY:{_escape_text(event.title or 'Untitled')}")
+        if event.description:
+            lines.append(f"DESCRIPTION:{_escape_text(event.description)}")
+        if event.url:
+            lines.append(f"URL:{event.url}")
+        lines.append("END:VEVENT")
+
+    lines.append("END:VCALENDAR")
+
+    return "\r\n".join(_fold_line(line) for line in lines) + "\r\n"
+
+
+def parse_ical(text: str) -> list[CalendarEvent]:
+    """Read the events of an iCalendar document (e.g. a calendar app's .ics export).
+
+    Recurring events are not expanded: a series is one event starting at its
+    first occurrence, and moved or changed occurrences are further events with
+    the same UID. Cancelled events and events without a UID or start are left out.
+
+    Args:
+        text: iCalendar text.
+
+    Returns:
+        The events, in the order they appear.
+    """
+    events: list[CalendarEvent] = []
+    properties: dict[str, tuple[dict[str, str], str]] | None = None
+    for line in _unfold(text):
+        name, params, value = _parse_line(line)
+        if name == "BEGIN" and value.upper() == "VEVENT":
+            properties = {}
+        elif name == "END" and value.upper() == "VEVENT" and properties is not None:
+            event = _event(properties)
+            if event:
+                events.append(event)
+            properties = None
+        elif properties is not None and name not in properties:
+            properties[name] = (params, value)
+    return events
+
+
+def _event(properties: dict[str, tuple[dict[str, str], str]]) -> CalendarEvent | None:
+    """Build an event from a VEVENT's properties (None if it can't be used)."""
+    uid = properties.get("UID", ({}, ""))[1].strip()
+    status = properties.get("STATUS", ({}, ""))[1]
+    start = _parse_datetime(*properties["DTSTART"]) if "DTSTART" in properties else None
+    if not uid or start is None or status.upper() == "CANCELLED":
+        return None
+    end = _parse_datetime(*properties["DTEND"]) if "DTEND" in properties else None
+    return CalendarEvent(
+        uid=uid,
+        title=_unescape_text(properties.get("SUMMARY", ({}, ""))[1]),
+        start=start,
+        end=end or start,
+        description=_unescape_text(properties.get("DESCRIPTION", ({}, ""))[1]),
+        url=properties.get("URL", ({}, ""))[1],
+        recurring="RRULE" in properties,
+    )
+
+
+def _unfold(text: str) -> list[str]:
+    """Split iCalendar text into content lines, joining folded lines."""
+    lines: list[str] = []
+    for line in text.replace("\r\n", "\n").replace("\r", "\n").split("\n"):
+        if line[:1] in (" ", "\t") and lines:
+            lines[-1] += line[1:]
+        elif line:
+            lines.append(line)
+    return lines
+
+
+def _parse_line(line: str) -> tuple[str, dict[str, str], str]:
+    """Split a content line into its name, parameters and value."""
+    head, _, value = line.partition(":")
+    name, *param_parts = head.split(";")
+    params = {}
+    for part in param_parts:
+        key, _, param = part.partition("=")
+        params[key.upper()] = param.strip('"')
+    return name.upper(), params, value
+
+
+def _parse_datetime(params: dict[str, str], value: str) -> datetime | None:
+    """Parse a DATE or DATE-TIME value; floating times are taken as local time."""
+    value = value.strip()
+    try:
+        if params.get("VALUE") == "DATE" or len(value) == 8:
+            return datetime.strptime(value[:8], "%Y%m%d").astimezone()
+        if value.endswith("Z"):
+            parsed = datetime.strptime(value[:-1], "%Y%m%dT%H%M%S")
+            return parsed.replace(tzinfo=timezone.utc)
+        parsed = datetime.strptime(value, "%Y%m%dT%H%M%S")
+    except ValueError:
+        return None
+    if "TZID" in params:
+        try:
+            return parsed.replace(tzinfo=ZoneInfo(params["TZID"]))
+        except (ZoneInfoNotFoundError, ValueError):
+            pass  # e.g. a Windows zone name; fall back to local time
+    return parsed.astimezone()
+
+
+def _format_datetime(dt: datetime) -> str:
+    """Format a datetime as an iCalendar UTC date-time."""
+    if dt.tzinfo is None:
+        dt = dt.replace(tzinfo=timezone.utc)
+    return dt.astimezone(timezone.utc).strftime("%Y%m%dT%H%M%SZ")
+
+
+def _escape_text(text: str) -> str:
+    """Escape a TEXT property value."""
+    return (
+        text.replace("\\", "\\\\")
+        .replace(";", "\\;")
+        .replace(",", "\\,")
+        .replace("\r\n", "\\n")
+        .replace("\n", "\\n")
+    )
+
+
+def _fold_line(line: str, limit: int = 75) -> str:
+    """Fold a content line to at most `limit` octets per physical line."""
+    encoded = line.encode("utf-8")
+    if len(encoded) <= limit:
+        return line
+
+    parts: list[str] = []
+    current = ""
+    current_len = 0
+    max_len = limit
+    for char in line:
+        char_len = len(char.encode("utf-8"))
+        if current_len + char_len > max_len:
+            parts.append(current)
+            current = ""
+            current_len = 0
+            max_len = limit - 1  # continuation lines start with a space
+        current += char
+        current_len += char_len
+    parts.append(current)
+
+    return "\r\n ".join(parts)
+
+
+def _unescape_text(text: str) -> str:
+    """Unescape a TEXT property value."""
+    result = []
+    chars = iter(text)
+    for char in chars:
+        if char == "\\":
+            escaped = next(chars, "")
+            result.append("\n" if escaped in ("n", "N") else escaped)
+        else:
+            result.append(char)
+    return "".join(result)
@@ -0,0 +1,74 @@
+"""Interleaved layout: typed notes placed in the transcript where they were written."""
+
+from datetime import datetime, timezone
+
+from granola.api.models import ProseMirrorDoc, ProseMirrorNode
+from granola.cache.reader import TranscriptSegment
+from granola.prosemirror.converter import to_markdown
+from granola.utils.dates import parse_timestamp
+
+# How notes and transcript are arranged in exported files (--layout)
+LAYOUTS = ("sections", "interleaved")
+
+# Transcript source of the notes merged into a transcript
+NOTES_SOURCE = "notes"
+
+# Node attributes the editor may record when a block was written
+_TIME_ATTRS = ("timestamp", "created_at", "createdAt", "time")
+
+
+def node_timestamp(node: ProseMirrorNode) -> str | None:
+    """Return when a notes block was written (ISO 8601), if its attrs record it."""
+    for key in _TIME_ATTRS:
+        value = node.attrs.get(key)
+        if isinstance(value, (int, float)) and not isinstance(value, bool) and value > 0:
+            # Epoch milliseconds (or seconds, for small values)
+            seconds = value / 1000 if value > 1e11 else value
+            return datetime.fromtimestamp(seconds, timezone.utc).isoformat()
+        if isinstance(value, str) and parse_timestamp(value):
+            return value
+    return None
+
+
+def interleave(
+    notes: ProseMirrorDoc, segments: list[TranscriptSegment], doc_id: str
+) -> tuple[ProseMirrorDoc, list[TranscriptSegment]]:
+    """Move the timestamped blocks of a document's notes into its transcript.
+
+    Each top-level block with a timestamp becomes a segment with source "notes",
+    placed in time order among the transcript's segments.
+
+    Returns:
+        The notes without the moved blocks, and the merged timeline.
+    """
+    untimed: list[ProseMirrorNode] = []
+    timed: list[TranscriptSegment] = []
+    for i, node in enumerate(notes.content):
+        timestamp = node_timestamp(node)
+        text = to_markdown(ProseMirrorDoc(content=[node])).strip()
+        if timestamp is None or not text:
+            untimed.append(node)
+            continue
+        timed.append(
+            TranscriptSegment(
+                id=f"{doc_id}-notes-{i}",
+                document_id=doc_id,
+                start_timestamp=timestamp,
+                end_timestamp=timestamp,
+                text=text,
+                source=NOTES_SOURCE,
+                is_final=True,
+            )
+        )
+
+    if not timed:
+        return notes, segments
+    # sorted() is stable, so segments at the same time keep their order
+    timeline = sorted([*segments, *timed], key=_sort_key)
+    return ProseMirrorDoc(type=notes.type, content=untimed), timeline
+
+
+def _sort_key(segment: TranscriptSegment) -> tuple[bool, datetime]:
+    """Sort segments by start time; ones without a valid time go last."""
+    start = parse_timestamp(segment.start_timestamp)
+    return (start is None, start or datetime.min.replace(tzinfo=timezone.utc))
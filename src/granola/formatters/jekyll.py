@@ -0,0 +1,68 @@
+"""Jekyll post formatting."""
+
+from pathlib import Path
+from typing import Any
+
+import yaml
+
+from granola.cache.reader import TranscriptSegment
+from granola.formatters.markdown import format_markdown_body
+from granola.utils.filename import slugify
+from granola.writers.sync_writer import ExportDoc
+
+# Jekyll collection directory that posts are written to
+JEKYLL_POSTS_DIR = Path("_posts")
+
+
+def format_jekyll(
+    title: str,
+    doc_id: str,
+    created_at: str,
+    updated_at: str,
+    notes_content: str,
+    segments: list[TranscriptSegment],
+    folders: list[str],
+    metadata: dict[str, Any] | None = None,
+) -> str:
+    """Format notes and transcript as a Jekyll post with YAML frontmatter.
+
+    Granola folders become post tags, followed by any tags in metadata; all posts
+    share the "meetings" category.
+
+    Args:
+        title: Document title.
+        doc_id: Document ID.
+        created_at: Creation timestamp.
+        updated_at: Update timestamp.
+        notes_content: Markdown notes content.
+        segments: Transcript segments.
+        folders: List of folder names.
+        metadata: Extra fields for the frontmatter (e.g. owner), if any.
+
+    Returns:
+        Markdown string with Jekyll frontmatter.
+    """
+    fields: dict[str, object] = {
+        "layout": "post",
+        "title": title or "Untitled",
+        "date": created_at,
+        "last_modified_at": updated_at,
+        "categories": ["meetings"],
+        "tags": list(dict.fromkeys([*folders, *(metadata or {}).get("tags", [])])),
+        "granola_id": doc_id,
+    }
+    for key, value in (metadata or {}).items():
+        fields.setdefault(key, value)
+
+    frontmatter = yaml.dump(
+        fields, default_flow_style=False, allow_unicode=True, sort_keys=False
+    ).strip()
+
+    return f"---\n{frontmatter}\n---\n\n" + format_markdown_body(notes_content, segments)
+
+
+def jekyll_layout(doc: ExportDoc) -> list[Path]:
+    """Return the post path for a document: _posts/YYYY-MM-DD-<slug>-<short_id>.md."""
+    short_id = doc.id[:8] if len(doc.id) >= 8 else doc.id
+    date_prefix = doc.local_start.strftime("%Y-%m-%d")
+    return [JEKYLL_POSTS_DIR / f"{date_prefix}-{slugify(doc.title or '')}-{short_id}.md"]
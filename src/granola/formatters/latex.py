@@ -0,0 +1,86 @@
+"""LaTeX formatting, for including meeting notes in typeset reports."""
+
+from typing import Any
+
+from granola.cache.reader import TranscriptSegment
+from granola.prosemirror.converter import escape_latex, from_markdown, to_latex
+from granola.utils.dates import format_time, parse_timestamp
+
+
+def format_latex(
+    title: str,
+    doc_id: str,
+    created_at: str,
+    updated_at: str,
+    notes_content: str,
+    segments: list[TranscriptSegment],
+    folders: list[str],
+    metadata: dict[str, Any] | None = None,
+) -> str:
+    """Format notes and transcript as a LaTeX fragment.
+
+    The meeting is a \\section; headings in the notes become subsections below
+    it, and the transcript is a description list. The fragment uses no packages
+    and has no preamble, so it can be \\input into a report. The document ID and
+    any extra metadata are kept as comments.
+
+    Args:
+        title: Document title.
+        doc_id: Document ID.
+        created_at: Creation timestamp.
+        updated_at: Update timestamp.
+        notes_content: Markdown notes content.
+        segments: Transcript segments.
+        folders: List of folder names.
+        metadata: Extra fields for the header comments (e.g. owner), if any.
+
+    Returns:
+        LaTeX string.
+    """
+    lines = [f"% granola_id: {doc_id}", f"% updated: {updated_at}"]
+    for key, value in (metadata or {}).items():
+        lines.append(f"% {key}: {' '.join(str(value).split())}")
+    lines.append(f"\\section{{{escape_latex(title or 'Untitled')}}}")
+
+    details = []
+    created = parse_timestamp(created_at)
+    if created:
+        details.append(created.strftime("%Y-%m-%d %H:%M"))
+    if folders:
+        details.append(", ".join(folders))
+    if details:
+        lines.extend(["", f"\\noindent\\emph{{{escape_latex(' · '.join(details))}}}"])
+
+    notes = to_latex(from_markdown(notes_content), heading_offset=1) if notes_content else ""
+    if notes:
+        lines.extend(["", notes.rstrip()])
+
+    if segments:
+        lines.extend(["", "\\subsection{Transcript}", "", _format_transcript(segments)])
+
+    return "\n".join(lines) + "\n"
+
+
+def _format_transcript(segments: list[TranscriptSegment]) -> str:
+    """Format transcript segments as description lists, one per section."""
+    blocks: list[str] = []
+    items: list[str] = []
+
+    def close_list() -> None:
+        if items:
+            blocks.append("\\begin{description}\n" + "\n".join(items) + "\n\\end{description}")
+            items.clear()
+
+    for segment in segments:
+        if segment.heading:
+            # Time-blocked transcript (see formatters.sections)
+            close_list()
+            blocks.append(f"\\subsubsection{{{escape_latex(segment.heading)}}}")
+            if segment.summary:
+                blocks.append(f"\\emph{{{escape_latex(segment.summary)}}}")
+        started = parse_timestamp(segment.start_timestamp)
+        time = format_time(started) if started else segment.start_timestamp
+        label = escape_latex(f"{time} {segment.speaker_name}")
+        items.append(f"\\item[{{{label}}}] {escape_latex(segment.text)}")
+    close_list()
+    return "\n\n".join(blocks)
@@ -3,10 +3,14 @@
 import yaml
 
 from granola.api.models import Document
+from granola.cache.reader import TranscriptSegment
+from granola.formatters.combined import format_transcript
 from granola.prosemirror.converter import to_markdown
+from granola.utils.dates import format_timestamp
+from granola.utils.filename import zettel_id
 
 
-def to_markdown_file(doc: Document) -> str:
+def to_markdown_file(doc: Document, zettel: bool = False) -> str:
     """Convert a Document to Markdown format with YAML frontmatter.
 
     Content priority:
@@ -17,16 +21,19 @@ def to_markdown_file(doc: Document) -> str:
 
     Args:
         doc: The Document to convert.
+        zettel: Use a timestamp-based zettel ID as `id` (the Granola ID moves
+            to `granola_id`).
 
     Returns:
         Markdown string with YAML frontmatter.
     """
     # Build metadata
-    metadata: dict[str, str | list[str]] = {
-        "id": doc.id,
-        "created": doc.created_at,
-        "updated": doc.updated_at,
-    }
+    metadata: dict[str, str | list[str]] = {"id": doc.id}
+    started = doc.local_start
+    if zettel and started:
+        metadata = {"id": zettel_id(started), "granola_id": doc.id}
+    metadata["created"] = format_timestamp(doc.created_at)
+    metadata["updated"] = format_timestamp(doc.updated_at)
     if doc.tags:
         metadata["tags"] = doc.tags
 
@@ -67,3 +74,32 @@ def to_markdown_file(doc: Document) -> str:
             parts.append("")
 
     return "\n".join(parts)
+
+
+def format_markdown_body(notes_content: str, segments: list[TranscriptSegment]) -> str:
+    """Format notes and transcript as Markdown sections (no frontmatter).
+
+    Args:
+        notes_content: Markdown notes content.
+        segments: Transcript segments.
+
+    Returns:
+        Markdown string with a Notes section and, if available, a Transcript section.
+    """
+    lines: list[str] = ["## Notes", ""]
+
+    if notes_content and notes_content.strip():
+        lines.append(notes_content.strip())
+    else:
+        lines.append("_No notes._")
+
+    if segments:
+        lines.extend(["", "## Transcript", ""])
+        # Hard line breaks keep one transcript line per rendered line (section
+        # headings, their summaries and the blank lines around them are left alone)
+        lines.extend(
+            f"{line}  " if line and not line.startswith(("### ", "> ")) else line
+            for line in format_transcript(segments).splitlines()
+        )
+
+    return "\n".join(lines) + "\n"
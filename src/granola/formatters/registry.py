@@ -0,0 +1,87 @@
+"""Registry of output formats, looked up by name from --format and destinations."""
+
+from dataclasses import dataclass
+from pathlib import Path
+from typing import Callable
+
+from granola.formatters.asciidoc import format_asciidoc
+from granola.formatters.combined import format_combined
+from granola.formatters.dendron import dendron_layout, format_dendron
+from granola.formatters.hugo import format_hugo, hugo_layout
+from granola.formatters.jekyll import format_jekyll, jekyll_layout
+from granola.formatters.latex import format_latex
+from granola.formatters.rst import format_rst
+from granola.formatters.textbundle import format_textbundle
+from granola.interfaces import Renderer
+from granola.writers.sync_writer import ExportDoc
+from granola.writers.textbundle import TEXTPACK_EXTENSION, TextPacker
+
+
+@dataclass(frozen=True)
+class Formatter:
+    """An output format: how a document is rendered and where its files go.
+
+    Attributes:
+        name: Name used to select the format (--format, destinations).
+        render: Renders a document's file content.
+        extension: File extension of rendered files.
+        layout: Optional function returning a document's file paths relative to the
+            output directory (see SyncWriter). Defaults to one file per folder.
+        package: Optional function turning rendered content into the bytes of its
+            file, for formats whose files are packages rather than text.
+    """
+
+    name: str
+    render: Renderer
+    extension: str
+    layout: Callable[[ExportDoc], list[Path]] | None = None
+    package: Callable[[str], bytes] | None = None
+
+
+_FORMATTERS: dict[str, Formatter] = {}
+
+
+def register_formatter(formatter: Formatter, replace: bool = False) -> None:
+    """Make a format available to the export and notes commands by name.
+
+    Args:
+        formatter: Format to register.
+        replace: Replace an existing format with the same name instead of failing.
+
+    Raises:
+        ValueError: If the name is already registered and replace is False.
+    """
+    if formatter.name in _FORMATTERS and not replace:
+        raise ValueError(f"Format '{formatter.name}' is already registered")
+    _FORMATTERS[formatter.name] = formatter
+
+
+def get_formatter(name: str) -> Formatter:
+    """Look up a registered format.
+
+    Raises:
+        ValueError: If no format with that name is registered.
+    """
+    try:
+        return _FORMATTERS[name]
+    except KeyError:
+        raise ValueError(
+            f"Unknown format '{name}'. Choose one of: {', '.join(formatter_names())}"
+        ) from None
+
+
+def formatter_names() -> list[str]:
+    """Return the names of all registered formats, in registration order."""
+    return list(_FORMATTERS)
+
+
+register_formatter(Formatter("txt", format_combined, ".txt"))
+register_formatter(Formatter("hugo", format_hugo, ".md", hugo_layout))
+register_formatter(Formatter("jekyll", format_jekyll, ".md", jekyll_layout))
+register_formatter(Formatter("dendron", format_dendron, ".md", dendron_layout))
+register_formatter(Formatter("tex", format_latex, ".tex"))
+register_formatter(Formatter("adoc", format_asciidoc, ".adoc"))
+register_formatter(Formatter("rst", format_rst, ".rst"))
+register_formatter(
+    Formatter("textbundle", format_textbundle, TEXTPACK_EXTENSION, package=TextPacker())
+)
@@ -0,0 +1,88 @@
+"""reStructuredText formatting, for Sphinx documentation sites."""
+
+from typing import Any
+
+from granola.cache.reader import TranscriptSegment
+from granola.prosemirror.converter import escape_rst, from_markdown, rst_heading, to_rst
+from granola.utils.dates import format_time, parse_timestamp
+
+
+def format_rst(
+    title: str,
+    doc_id: str,
+    created_at: str,
+    updated_at: str,
+    notes_content: str,
+    segments: list[TranscriptSegment],
+    folders: list[str],
+    metadata: dict[str, Any] | None = None,
+) -> str:
+    """Format notes and transcript as a reStructuredText document.
+
+    Metadata goes in a field list at the top of the file, which Sphinx reads as
+    the page's metadata, instead of YAML frontmatter. Headings in the notes
+    become sections below the Notes section.
+
+    Args:
+        title: Document title.
+        doc_id: Document ID.
+        created_at: Creation timestamp.
+        updated_at: Update timestamp.
+        notes_content: Markdown notes content.
+        segments: Transcript segments.
+        folders: List of folder names.
+        metadata: Extra fields for the field list (e.g. owner), if any.
+
+    Returns:
+        reStructuredText string.
+    """
+    fields: dict[str, object] = {
+        "granola_id": doc_id,
+        "created": created_at,
+        "updated": updated_at,
+    }
+    if folders:
+        fields["tags"] = ", ".join(folders)
+    for key, value in (metadata or {}).items():
+        fields.setdefault(key, value)
+
+    heading = escape_rst(title or "Untitled")
+    rule = "=" * max(len(heading), 1)
+    lines = [f":{name}: {_field_value(value)}" for name, value in fields.items()]
+    lines.extend(["", rule, heading, rule])
+
+    lines.extend(["", rst_heading("Notes", 1), ""])
+    notes = to_rst(from_markdown(notes_content), heading_offset=1) if notes_content else ""
+    lines.append(notes.rstrip() or "*No notes*")
+
+    lines.extend(["", rst_heading("Transcript", 1), ""])
+    lines.append(_format_transcript(segments) if segments else "*No transcript available*")
+
+    return "\n".join(lines) + "\n"
+
+
+def _field_value(value: Any) -> str:
+    """Format a value for a field list entry (kept on one line)."""
+    if isinstance(value, bool):
+        return "yes" if value else "no"
+    if isinstance(value, (list, tuple)):
+        return "; ".join(_field_value(v) for v in value)
+    if isinstance(value, dict):
+        return ", ".join(_field_value(v) for v in value.values() if v)
+    return escape_rst(" ".join(str(value).split()))
+
+
+def _format_transcript(segments: list[TranscriptSegment]) -> str:
+    """Format transcript segments as paragraphs of timestamped speakers."""
+    blocks: list[str] = []
+    for segment in segments:
+        if segment.heading:
+            # Time-blocked transcript (see formatters.sections)
+            blocks.append(rst_heading(escape_rst(segment.heading), 2))
+            if segment.summary:
+                blocks.append(f"*{escape_rst(segment.summary)}*")
+        started = parse_timestamp(segment.start_timestamp)
+        time = format_time(started) if started else segment.start_timestamp
+        speaker = escape_rst(segment.speaker_name)
+        blocks.append(f"``{time}`` **{speaker}**: {escape_rst(segment.text)}")
+    return "\n\n".join(blocks)
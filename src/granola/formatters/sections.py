@@ -0,0 +1,89 @@
+"""Time-blocked transcripts: headings that split a long transcript into sections."""
+
+from dataclasses import replace
+from datetime import datetime, timedelta
+from typing import Callable
+
+from granola.cache.reader import TranscriptSegment
+from granola.utils.dates import parse_timestamp
+
+
+def time_sections(
+    segments: list[TranscriptSegment],
+    every_minutes: int | None = None,
+    gap_seconds: int | None = None,
+) -> list[TranscriptSegment]:
+    """Mark where a transcript's sections start, with an HH:MM heading.
+
+    A section starts at the first segment, then every every_minutes (counted from
+    the start of the current section) and after every silence of at least
+    gap_seconds, unless that would repeat the previous heading. Segments without
+    a valid start time never start a section.
+
+    Returns:
+        The segments, with heading set on each one that starts a section.
+    """
+    if not segments or not (every_minutes or gap_seconds):
+        return segments
+
+    result: list[TranscriptSegment] = []
+    section_start: datetime | None = None
+    previous_end: datetime | None = None
+    heading = ""
+    for segment in segments:
+        start = parse_timestamp(segment.start_timestamp)
+        if (
+            start is not None
+            # Two sections under the same heading would be hard to tell apart
+            and start.strftime("%H:%M") != heading
+            and _starts_section(start, section_start, previous_end, every_minutes, gap_seconds)
+        ):
+            section_start = start
+            heading = start.strftime("%H:%M")
+            segment = replace(segment, heading=heading)
+        if start is not None:
+            previous_end = max(
+                filter(None, (previous_end, start, parse_timestamp(segment.end_timestamp)))
+            )
+        result.append(segment)
+    return result
+
+
+def summarize_sections(
+    segments: list[TranscriptSegment],
+    summarize: Callable[[list[TranscriptSegment]], str | None],
+) -> list[TranscriptSegment]:
+    """Add a one-line summary to each section of a time-blocked transcript.
+
+    Args:
+        segments: Segments with section headings (see time_sections).
+        summarize: Returns the summary of a section's segments, or None for none.
+            Exceptions propagate, failing the document.
+
+    Returns:
+        The segments, with summary set on each one that starts a section.
+    """
+    starts = [i for i, segment in enumerate(segments) if segment.heading]
+    result = list(segments)
+    for start, end in zip(starts, [*starts[1:], len(segments)]):
+        summary = summarize(segments[start:end])
+        if summary and summary.strip():
+            result[start] = replace(result[start], summary=" ".join(summary.split()))
+    return result
+
+
+def _starts_section(
+    start: datetime,
+    section_start: datetime | None,
+    previous_end: datetime | None,
+    every_minutes: int | None,
+    gap_seconds: int | None,
+) -> bool:
+    """Return True if a segment starting at start begins a new section."""
+    if section_start is None:
+        return True
+    if every_minutes and start - section_start >= timedelta(minutes=every_minutes):
+        return True
+    if gap_seconds and previous_end and start - previous_end >= timedelta(seconds=gap_seconds):
+        return True
+    return False
@@ -0,0 +1,167 @@
+"""User-supplied Jinja2 templates for rendering documents."""
+
+from pathlib import Path
+from typing import Any
+
+import jinja2
+
+from granola.api.models import Document
+from granola.cache.reader import TranscriptSegment
+from granola.formatters.combined import format_transcript
+from granola.interfaces import Renderer
+
+# Suffixes that mark a file as a template rather than part of the output name
+TEMPLATE_SUFFIXES = {".tmpl", ".j2", ".jinja", ".jinja2"}
+
+
+class TemplateError(Exception):
+    """Raised when a template can't be read or parsed."""
+
+
+class TemplateRenderer:
+    """Renders documents through a user-supplied Jinja2 template (a Renderer).
+
+    Templates can use these variables:
+        title, id, created_at, updated_at: Document metadata (timestamps as ISO 8601).
+        notes: Notes as Markdown.
+        folders: Names of the document's folders.
+        segments: Transcript segments (start_timestamp, end_timestamp, text, source,
+            speaker_name).
+        transcript: The transcript formatted as "[HH:MM:SS] Speaker: text" lines.
+        document: The full API document (None for shared documents from the cache).
+        panel: The document's last viewed panel (document.last_viewed_panel), or None.
+        template_slug: The Granola note template the document was written with, or None.
+        metadata: Extra fields (e.g. owner); empty if there are none.
+
+    Undefined variables are errors, so a typo fails the document instead of
+    silently rendering blanks.
+    """
+
+    def __init__(self, template: jinja2.Template, documents: dict[str, Document] | None = None):
+        """Initialize the renderer.
+
+        Args:
+            template: Compiled template.
+            documents: API documents by ID, exposed to the template as document/panel.
+                The dict is kept by reference, so it can be filled in after loading.
+        """
+        self.template = template
+        self.documents = documents if documents is not None else {}
+
+    @classmethod
+    def load(cls, path: Path, documents: dict[str, Document] | None = None) -> "TemplateRenderer":
+        """Load a template file.
+
+        Args:
+            path: Template file.
+            documents: API documents by ID (see __init__).
+
+        Raises:
+            TemplateError: If the file can't be read or isn't a valid template.
+        """
+        try:
+            source = path.read_text(encoding="utf-8")
+        except OSError as e:
+            raise TemplateError(f"Failed to read template {path}: {e}") from e
+
+        env = jinja2.Environment(undefined=jinja2.StrictUndefined, keep_trailing_newline=True)
+        try:
+            return cls(env.from_string(source), documents)
+        except jinja2.TemplateSyntaxError as e:
+            raise TemplateError(f"Invalid template {path}, line {e.lineno}: {e.message}") from e
+
+    def __call__(
+        self,
+        title: str,
+        doc_id: str,
+        created_at: str,
+        updated_at: str,
+        notes_content: str,
+        segments: list[TranscriptSegment],
+        folders: list[str],
+        metadata: dict[str, Any] | None = None,
+    ) -> str:
+        document = self.documents.get(doc_id)
+        return self.template.render(
+            title=title,
+            id=doc_id,
+            created_at=created_at,
+            updated_at=updated_at,
+            notes=notes_content or "",
+            folders=folders,
+            segments=segments,
+            transcript=format_transcript(segments) if segments else "",
+            document=document,
+            panel=document.last_viewed_panel if document else None,
+            template_slug=template_slug(document),
+            metadata=metadata or {},
+        )
+
+
+class SlugRenderer:
+    """Picks a renderer by the Granola note template a document was written with.
+
+    Lets e.g. 1:1 meetings and interviews be rendered with different templates.
+    Documents with another (or no) template slug use the default renderer.
+    """
+
+    def __init__(
+        self,
+        renderers: dict[str, Renderer],
+        default: Renderer,
+        documents: dict[str, Document],
+    ):
+        """Initialize the renderer.
+
+        Args:
+            renderers: Map of template slug -> renderer.
+            default: Renderer for documents with no matching slug.
+            documents: API documents by ID, used to look up template slugs. Kept by
+                reference, so it can be filled in after construction.
+        """
+        self.renderers = renderers
+        self.default = default
+        self.documents = documents
+
+    def __call__(
+        self,
+        title: str,
+        doc_id: str,
+        created_at: str,
+        updated_at: str,
+        notes_content: str,
+        segments: list[TranscriptSegment],
+        folders: list[str],
+        metadata: dict[str, Any] | None = None,
+    ) -> str:
+        slug = template_slug(self.documents.get(doc_id))
+        renderer = self.renderers.get(slug, self.default) if slug else self.default
+        return renderer(
+            title=title,
+            doc_id=doc_id,
+            created_at=created_at,
+            updated_at=updated_at,
+            notes_content=notes_content,
+            segments=segments,
+            folders=folders,
+            **({"metadata": metadata} if metadata else {}),
+        )
+
+
+def template_slug(document: Document | None) -> str | None:
+    """Return the slug of the Granola note template a document was written with."""
+    if document and document.last_viewed_panel:
+        return document.last_viewed_panel.template_slug
+    return None
+
+
+def template_extension(path: Path, default: str) -> str:
+    """Return the extension of files rendered from a template.
+
+    A template named like its output plus a template suffix (meeting.md.j2) renders
+    .md files; otherwise the default is used.
+    """
+    suffixes = path.suffixes
+    if len(suffixes) >= 2 and suffixes[-1].lower() in TEMPLATE_SUFFIXES:
+        return suffixes[-2]
+    return default
@@ -0,0 +1,37 @@
+"""TextBundle note formatting (packed into a .textpack by writers.textbundle)."""
+
+from typing import Any
+
+from granola.cache.reader import TranscriptSegment
+from granola.formatters.markdown import format_markdown_body
+
+
+def format_textbundle(
+    title: str,
+    doc_id: str,
+    created_at: str,
+    updated_at: str,
+    notes_content: str,
+    segments: list[TranscriptSegment],
+    folders: list[str],
+    metadata: dict[str, Any] | None = None,
+) -> str:
+    """Format notes and transcript as the Markdown of a TextBundle.
+
+    Bear, Ulysses and Craft show frontmatter as text, so there is none: the
+    title is the first heading. The metadata is not included.
+
+    Args:
+        title: Document title.
+        doc_id: Document ID.
+        created_at: Creation timestamp.
+        updated_at: Update timestamp.
+        notes_content: Markdown notes content.
+        segments: Transcript segments.
+        folders: List of folder names.
+        metadata: Extra fields (unused).
+
+    Returns:
+        Markdown string.
+    """
+    return f"# {title or 'Untitled'}\n\n" + format_markdown_body(notes_content, segments)
@@ -3,6 +3,7 @@
 from datetime import datetime
 
 from granola.cache.reader import CacheDocument, TranscriptSegment
+from granola.utils.dates import format_time, format_timestamp
 
 
 def format_transcript(doc: CacheDocument, segments: list[TranscriptSegment]) -> str:
@@ -29,10 +30,10 @@ def format_transcript(doc: CacheDocument, segments: list[TranscriptSegment]) ->
     lines.append(f"ID: {doc.id}")
 
     if doc.created_at:
-        lines.append(f"Created: {doc.created_at}")
+        lines.append(f"Created: {format_timestamp(doc.created_at)}")
 
     if doc.updated_at:
-        lines.append(f"Updated: {doc.updated_at}")
+        lines.append(f"Updated: {format_timestamp(doc.updated_at)}")
 
     lines.append(f"Segments: {len(segments)}")
     lines.append("=" * 80)
@@ -41,14 +42,13 @@ def format_transcript(doc: CacheDocument, segments: list[TranscriptSegment]) ->
     # Transcript segments
     for segment in segments:
         timestamp = _parse_timestamp(segment.start_timestamp)
-        speaker = "You" if segment.source == "microphone" else "System"
-        lines.append(f"[{timestamp}] {speaker}: {segment.text}")
+        lines.append(f"[{timestamp}] {segment.speaker_name}: {segment.text}")
 
     return "\n".join(lines)
 
 
 def _parse_timestamp(timestamp: str) -> str:
-    """Convert ISO 8601 timestamp to HH:MM:SS format.
+    """Convert ISO 8601 timestamp to the time format (HH:MM:SS by default).
 
     Args:
         timestamp: ISO 8601 timestamp string.
@@ -60,6 +60,6 @@ def _parse_timestamp(timestamp: str) -> str:
         # Handle both 'Z' suffix and timezone offsets
         ts = timestamp.replace("Z", "+00:00")
         dt = datetime.fromisoformat(ts)
-        return dt.strftime("%H:%M:%S")
+        return format_time(dt)
     except ValueError:
         return timestamp
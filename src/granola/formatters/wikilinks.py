@@ -0,0 +1,28 @@
+"""Obsidian wikilinks for people mentioned in notes."""
+
+import re
+from typing import Iterable
+
+# Text that must not be linked again: existing wikilinks, Markdown links, code
+_PROTECTED = re.compile(r"\[\[.*?\]\]|\[[^\]]*\]\([^)]*\)|`[^`]*`")
+
+
+def link_names(text: str, names: Iterable[str]) -> str:
+    """Turn whole-word occurrences of names into [[Name]] links.
+
+    Longer names win over names they contain ("Ada Lovelace" over "Ada").
+    Existing links and code spans are left alone.
+    """
+    unique = sorted({n.strip() for n in names if len(n.strip()) > 1}, key=len, reverse=True)
+    if not unique or not text:
+        return text
+    pattern = re.compile(r"(?<![\w@])(" + "|".join(re.escape(n) for n in unique) + r")(?![\w@])")
+
+    parts: list[str] = []
+    end = 0
+    for protected in _PROTECTED.finditer(text):
+        parts.append(pattern.sub(r"[[\1]]", text[end : protected.start()]))
+        parts.append(protected.group())
+        end = protected.end()
+    parts.append(pattern.sub(r"[[\1]]", text[end:]))
+    return "".join(parts)
@@ -0,0 +1,89 @@
+"""Interfaces between the parts of an export.
+
+An export fetches documents from a Client, reads transcripts and folders from a
+Store, renders each document with a Renderer and writes the results with a
+Syncer. GranolaClient, CacheData, the formatters and SyncWriter are the built-in
+implementations; programs embedding Granola can substitute their own.
+"""
+
+from pathlib import Path
+from typing import Any, Callable, Iterable, Protocol
+
+from granola.api.models import Document, DocumentList
+from granola.cache.reader import CacheDocument, SharedDocument, TranscriptSegment
+from granola.writers.sync_writer import ExportDoc, SyncFailure, SyncResult, SyncStats
+
+
+class Client(Protocol):
+    """Source of documents and folders from the Granola API."""
+
+    def get_documents(self) -> list[Document]:
+        """Fetch all documents."""
+        ...
+
+    def get_document_lists(self) -> list[DocumentList]:
+        """Fetch all document lists (folders)."""
+        ...
+
+    def get_doc_folder_mapping(self) -> tuple[dict[str, str], dict[str, list[str]]]:
+        """Return (folder_id -> folder title, doc_id -> folder titles)."""
+        ...
+
+
+class Store(Protocol):
+    """Local data that isn't available from the API (transcripts, shared documents)."""
+
+    documents: dict[str, CacheDocument]
+    transcripts: dict[str, list[TranscriptSegment]]
+    shared_documents: dict[str, SharedDocument]
+
+    def get_folder_names(self, doc_id: str) -> list[str]:
+        """Return the names of the folders a document is in."""
+        ...
+
+    def get_folder_parents(self) -> dict[str, str]:
+        """Return folder name -> parent folder name for nested folders."""
+        ...
+
+
+class Renderer(Protocol):
+    """Renders one document to the content of its exported file.
+
+    metadata holds extra fields for the frontmatter or header (e.g. owner). It is
+    only passed when there are any, so renderers written before it existed work
+    until a feature that adds metadata is used.
+    """
+
+    def __call__(
+        self,
+        title: str,
+        doc_id: str,
+        created_at: str,
+        updated_at: str,
+        notes_content: str,
+        segments: list[TranscriptSegment],
+        folders: list[str],
+        metadata: dict[str, Any] | None = None,
+    ) -> str: ...
+
+
+class Syncer(Protocol):
+    """Writes rendered documents to an output and removes ones that are gone."""
+
+    output_dir: Path
+    failures: list[SyncFailure]
+    interrupted: bool
+
+    def sync(
+        self,
+        docs: Iterable[ExportDoc],
+        all_doc_ids: set[str],
+        outline: Iterable[ExportDoc] | None = None,
+        on_result: Callable[[SyncResult], None] | None = None,
+    ) -> tuple[SyncStats, list[SyncResult]]:
+        """Sync documents to the output; see SyncWriter.sync."""
+        ...
+
+    def request_stop(self) -> None:
+        """Ask a running sync to stop after the current document."""
+        ...
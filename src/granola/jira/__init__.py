@@ -0,0 +1,8 @@
+"""Jira ticket filing support for Granola CLI."""
+
+from granola.jira.client import JiraClient, JiraError
+
+__all__ = [
+    "JiraClient",
+    "JiraError",
+]
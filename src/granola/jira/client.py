@@ -0,0 +1,146 @@
+"""Jira REST API client for filing tickets from meeting notes."""
+
+import logging
+import ssl
+from typing import Any, Optional
+
+import certifi
+import httpx
+
+JIRA_TIMEOUT = 30  # seconds
+
+# Label every ticket filed from Granola carries
+GRANOLA_LABEL = "granola"
+
+# Label prefix used to key tickets by the action item they were filed for
+ITEM_LABEL_PREFIX = "granola-"
+
+
+def _get_ssl_context() -> ssl.SSLContext:
+    """Create an SSL context using certifi's CA bundle."""
+    return ssl.create_default_context(cafile=certifi.where())
+
+
+class JiraError(Exception):
+    """Raised when a Jira API request fails."""
+
+    def __init__(self, message: str, status_code: int | None = None):
+        super().__init__(message)
+        self.status_code = status_code
+
+
+def item_label(key: str) -> str:
+    """Return the label that identifies the ticket filed for an action item."""
+    return f"{ITEM_LABEL_PREFIX}{key}".lower()
+
+
+class JiraClient:
+    """Client for the Jira Cloud/Server issue REST API (version 2)."""
+
+    def __init__(
+        self,
+        base_url: str,
+        project_key: str,
+        token: str,
+        user: Optional[str] = None,
+        logger: logging.Logger | None = None,
+    ):
+        """Initialize the client.
+
+        Args:
+            base_url: Jira base URL (e.g. https://example.atlassian.net).
+            project_key: Key of the project tickets are filed in (e.g. OPS).
+            token: API token (Jira Cloud) or personal access token (Jira Server).
+            user: Account email for Jira Cloud; without it the token is sent as
+                a bearer token, as Jira Server/Data Center expects.
+            logger: Optional logger for debug output.
+        """
+        self.base_url = base_url.rstrip("/")
+        self.project_key = project_key
+        self.logger = logger or logging.getLogger(__name__)
+        auth = (user, token) if user else None
+        headers = {"Accept": "application/json"}
+        if not user:
+            headers["Authorization"] = f"Bearer {token}"
+        self._client = httpx.Client(
+            base_url=f"{self.base_url}/rest/api/2",
+            auth=auth,
+            timeout=JIRA_TIMEOUT,
+            verify=_get_ssl_context(),
+            headers=headers,
+        )
+
+    def close(self) -> None:
+        """Close the underlying HTTP client."""
+        self._client.close()
+
+    def find_issue_by_label(self, label: str) -> Optional[str]:
+        """Return the key of an issue in the project with a label, if there is one."""
+        params = {
+            "jql": f'project = "{self.project_key}" AND labels = "{label}"',
+            "fields": "summary",
+            "maxResults": 1,
+        }
+        try:
+            data = self._request("GET", "/search/jql", params=params)
+        except JiraError as e:
+            # Jira Server/Data Center only has the older search endpoint
+            if e.status_code != 404:
+                raise
+            data = self._request("GET", "/search", params=params)
+        issues = data.get("issues", [])
+        return str(issues[0].get("key", "")) if issues else None
+
+    def create_issue(
+        self,
+        summary: str,
+        description: str,
+        issue_type: str = "Task",
+        labels: Optional[list[str]] = None,
+    ) -> str:
+        """File an issue in the project and return its key (e.g. OPS-123).
+
+        Args:
+            summary: Issue summary (single line; Jira limits it to 255 characters).
+            description: Description in Jira wiki markup.
+            issue_type: Name of the issue type.
+            labels: Labels to add (no spaces).
+        """
+        payload: dict[str, Any] = {
+            "fields": {
+                "project": {"key": self.project_key},
+                "summary": " ".join(summary.split())[:255],
+                "description": description,
+                "issuetype": {"name": issue_type},
+                "labels": labels or [],
+            }
+        }
+        data = self._request("POST", "/issue", json=payload)
+        key = str(data.get("key", ""))
+        self.logger.debug(f"Created Jira issue {key}: {summary}")
+        return key
+
+    def issue_url(self, key: str) -> str:
+        """Return the browser URL of an issue."""
+        return f"{self.base_url}/browse/{key}"
+
+    def _request(self, method: str, path: str, **kwargs: Any) -> Any:
+        """Send a request and return the decoded JSON body."""
+        try:
+            response = self._client.request(method, path, **kwargs)
+            response.raise_for_status()
+        except httpx.HTTPStatusError as e:
+            body_preview = e.response.text[:200] if e.response.text else ""
+            raise JiraError(
+                f"Jira request failed: status={e.response.status_code}, body={body_preview}",
+                status_code=e.response.status_code,
+            ) from e
+        except httpx.RequestError as e:
+            raise JiraError(f"Jira request failed: {e}") from e
+
+        if not response.content:
+            return {}
+        try:
+            return response.json()
+        except ValueError as e:
+            raise JiraError(f"Failed to parse Jira response: {e}") from e
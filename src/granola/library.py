@@ -0,0 +1,73 @@
+"""Public API for using Granola export from other Python programs.
+
+Everything exported here is kept stable across releases; other modules are
+internal and may change. A minimal export looks like:
+
+    from pathlib import Path
+    from granola.library import (
+        ExportSource, GranolaClient, SyncWriter, format_combined,
+        get_access_token, get_default_cache_path, read_cache,
+    )
+
+    client = GranolaClient(get_access_token(supabase_path))
+    store = read_cache(get_default_cache_path())
+    _, doc_folders = client.get_doc_folder_mapping()
+    source = ExportSource(client.get_documents(), store, api_doc_folders=doc_folders)
+    writer = SyncWriter(Path("~/Granola").expanduser())
+    stats, _ = writer.sync(source.iter_docs(format_combined), source.doc_ids())
+
+Client, Store, Renderer and Syncer describe what each part needs from the
+others, so any of them can be replaced with your own implementation. Register a
+Formatter to make a new output format available to `granola export --format`
+and destinations in the same process.
+"""
+
+from granola.api.auth import AuthError, get_access_token
+from granola.api.client import APIError, GranolaClient
+from granola.api.models import Document
+from granola.cache.reader import CacheData, TranscriptSegment, get_default_cache_path, read_cache
+from granola.formatters.combined import format_combined
+from granola.formatters.registry import (
+    Formatter,
+    formatter_names,
+    get_formatter,
+    register_formatter,
+)
+from granola.interfaces import Client, Renderer, Store, Syncer
+from granola.prosemirror.converter import to_markdown
+from granola.source import ExportSource, is_filtered_out
+from granola.writers.sync_writer import ExportDoc, SyncFailure, SyncResult, SyncStats, SyncWriter
+
+__all__ = [
+    # Interfaces
+    "Client",
+    "Store",
+    "Renderer",
+    "Syncer",
+    # Client
+    "GranolaClient",
+    "APIError",
+    "AuthError",
+    "get_access_token",
+    "Document",
+    # Store
+    "CacheData",
+    "TranscriptSegment",
+    "read_cache",
+    "get_default_cache_path",
+    # Rendering
+    "ExportSource",
+    "ExportDoc",
+    "format_combined",
+    "Formatter",
+    "register_formatter",
+    "get_formatter",
+    "formatter_names",
+    "to_markdown",
+    "is_filtered_out",
+    # Syncing
+    "SyncWriter",
+    "SyncStats",
+    "SyncResult",
+    "SyncFailure",
+]
@@ -276,6 +276,8 @@ class WholesailManagerApp(rumps.App):
                         parts.append(f"{result.moved} moved")
                     if result.deleted > 0:
                         parts.append(f"{result.deleted} deleted")
+                    if result.failed > 0:
+                        parts.append(f"{result.failed} failed")
                     if parts:
                         self.store.last_sync_message = ", ".join(parts)
                     else:
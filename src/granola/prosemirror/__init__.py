@@ -1,5 +1,27 @@
 """ProseMirror document conversion."""
 
-from granola.prosemirror.converter import to_markdown, to_plain_text
+from granola.prosemirror.converter import (
+    escape_latex,
+    escape_rst,
+    from_markdown,
+    to_asciidoc,
+    to_html,
+    to_json,
+    to_latex,
+    to_markdown,
+    to_plain_text,
+    to_rst,
+)
 
-__all__ = ["to_markdown", "to_plain_text"]
+__all__ = [
+    "escape_latex",
+    "escape_rst",
+    "from_markdown",
+    "to_asciidoc",
+    "to_html",
+    "to_json",
+    "to_latex",
+    "to_markdown",
+    "to_plain_text",
+    "to_rst",
+]
@@ -1,7 +1,8 @@
-"""ProseMirror document to Markdown/plain text conversion."""
+"""ProseMirror document to Markdown, plain text, HTML or LaTeX, and Markdown to ProseMirror."""
 
+import html
 import re
-from typing import Optional
+from typing import Any, Optional
 
 from granola.api.models import ProseMirrorDoc, ProseMirrorNode
 
@@ -110,10 +111,132 @@ def _process_node(node: ProseMirrorNode, indent_level: int, is_top_level: bool)
     elif node.type == "text":
         return node.text
 
+    elif node.type == "image":
+        src = node.attrs.get("src")
+        if not src:
+            return ""
+        alt = str(node.attrs.get("alt") or node.attrs.get("title") or "")
+        suffix = "\n\n" if is_top_level else ""
+        return f"![{alt}]({src})" + suffix
+
+    elif node.type == "mention":
+        # @-mentions of people carry the name in their attrs, not as text
+        return str(node.attrs.get("label") or node.attrs.get("name") or text_content)
+
     else:
         return text_content
 
 
+_HEADING = re.compile(r"^(#{1,6})\s+(.*?)\s*#*\s*$")
+_LIST_ITEM = re.compile(r"^([ \t]*)([-*+]|\d+[.)])\s+(.*)$")
+
+
+def from_markdown(text: str) -> ProseMirrorDoc:
+    """Convert Markdown to a ProseMirror document.
+
+    Supports the structure to_markdown() produces: headings, paragraphs, and
+    bullet and numbered lists (nested by indentation). Inline formatting is kept
+    as literal text.
+
+    Args:
+        text: Markdown to convert.
+
+    Returns:
+        ProseMirror document.
+    """
+    blocks: list[ProseMirrorNode] = []
+    paragraph: list[str] = []
+    # Open lists, outermost first, with the indentation of their items
+    lists: list[tuple[int, ProseMirrorNode]] = []
+
+    def flush_paragraph() -> None:
+        if paragraph:
+            blocks.append(_paragraph(" ".join(paragraph)))
+            paragraph.clear()
+
+    for line in text.splitlines():
+        item = _LIST_ITEM.match(line)
+        if item:
+            flush_paragraph()
+            indent = _indent_width(item.group(1))
+            list_type = "orderedList" if item.group(2)[0].isdigit() else "bulletList"
+            while lists and lists[-1][0] > indent:
+                lists.pop()
+            if lists and lists[-1][0] == indent and lists[-1][1].type != list_type:
+                lists.pop()
+            if not lists or lists[-1][0] < indent:
+                new_list = ProseMirrorNode(type=list_type)
+                if lists:
+                    # Nested under the last item of the enclosing list
+                    lists[-1][1].content[-1].content.append(new_list)
+                else:
+                    blocks.append(new_list)
+                lists.append((indent, new_list))
+            lists[-1][1].content.append(
+                ProseMirrorNode(type="listItem", content=[_paragraph(item.group(3).strip())])
+            )
+            continue
+
+        if not line.strip():
+            flush_paragraph()
+            continue
+
+        if lists and line[:1].isspace():
+            # Continuation of the last list item
+            item_paragraph = lists[-1][1].content[-1].content[0]
+            if item_paragraph.content:
+                item_paragraph.content[0].text += " " + line.strip()
+            else:
+                item_paragraph.content.append(ProseMirrorNode(type="text", text=line.strip()))
+            continue
+        lists.clear()
+
+        heading = _HEADING.match(line)
+        if heading:
+            flush_paragraph()
+            node = _paragraph(heading.group(2))
+            node.type = "heading"
+            node.attrs = {"level": len(heading.group(1))}
+            blocks.append(node)
+            continue
+
+        paragraph.append(line.strip())
+
+    flush_paragraph()
+    return ProseMirrorDoc(content=blocks)
+
+
+def to_json(doc: ProseMirrorDoc) -> dict[str, Any]:
+    """Convert a ProseMirror document to the JSON the Granola API stores.
+
+    Unlike model_dump(), empty fields are left out, as the editor does.
+    """
+    return {"type": doc.type, "content": [_node_json(node) for node in doc.content]}
+
+
+def _node_json(node: ProseMirrorNode) -> dict[str, Any]:
+    """Convert a ProseMirror node to JSON, leaving out empty fields."""
+    data: dict[str, Any] = {"type": node.type}
+    if node.attrs:
+        data["attrs"] = node.attrs
+    if node.type == "text":
+        data["text"] = node.text
+    elif node.content:
+        data["content"] = [_node_json(child) for child in node.content]
+    return data
+
+
+def _paragraph(text: str) -> ProseMirrorNode:
+    """Create a paragraph node (ProseMirror doesn't allow empty text nodes)."""
+    content = [ProseMirrorNode(type="text", text=text)] if text else []
+    return ProseMirrorNode(type="paragraph", content=content)
+
+
+def _indent_width(indent: str) -> int:
+    """Return the width of a list item's indentation (a tab counts as 4 spaces)."""
+    return len(indent.replace("\t", "    "))
+
+
 def to_plain_text(doc: Optional[ProseMirrorDoc]) -> str:
     """Convert a ProseMirror document to plain text (no formatting).
 
@@ -162,3 +285,306 @@ def _extract_text(node: ProseMirrorNode) -> str:
         separator = "\n"
 
     return separator.join(texts)
+
+
+def to_html(doc: Optional[ProseMirrorDoc]) -> str:
+    """Convert a ProseMirror document to an HTML fragment.
+
+    The output is well-formed XHTML, suitable for embedding in feeds or
+    Confluence storage format.
+
+    Args:
+        doc: The ProseMirror document to convert.
+
+    Returns:
+        HTML string representation.
+    """
+    if doc is None or doc.type != "doc" or not doc.content:
+        return ""
+
+    return "\n".join(_render_html(node) for node in doc.content)
+
+
+def _render_html(node: ProseMirrorNode) -> str:
+    """Recursively render a ProseMirror node as HTML.
+
+    Args:
+        node: The node to render.
+
+    Returns:
+        HTML string for this node.
+    """
+    if node.type == "text":
+        return html.escape(node.text, quote=False)
+
+    inner = "".join(_render_html(child) for child in node.content)
+    if not node.content and node.text:
+        inner = html.escape(node.text, quote=False)
+
+    if node.type == "heading":
+        level = 1
+        lvl = node.attrs.get("level") if node.attrs else None
+        if isinstance(lvl, (int, float)):
+            level = min(max(int(lvl), 1), 6)
+        return f"<h{level}>{inner}</h{level}>"
+
+    elif node.type == "paragraph":
+        return f"<p>{inner}</p>"
+
+    elif node.type == "bulletList":
+        return f"<ul>{inner}</ul>"
+
+    elif node.type == "orderedList":
+        return f"<ol>{inner}</ol>"
+
+    elif node.type == "listItem":
+        return f"<li>{inner}</li>"
+
+    elif node.type == "hardBreak":
+        return "<br/>"
+
+    return inner
+
+
+# Sectioning commands for heading levels, outermost first
+_LATEX_SECTIONS = ("section", "subsection", "subsubsection", "paragraph", "subparagraph")
+
+_LATEX_SPECIAL = {
+    "\\": r"\textbackslash{}",
+    "&": r"\&",
+    "%": r"\%",
+    "$": r"\$",
+    "#": r"\#",
+    "_": r"\_",
+    "{": r"\{",
+    "}": r"\}",
+    "~": r"\textasciitilde{}",
+    "^": r"\textasciicircum{}",
+}
+_LATEX_SPECIAL_RE = re.compile("|".join(re.escape(c) for c in _LATEX_SPECIAL))
+
+
+def escape_latex(text: str) -> str:
+    """Escape the characters LaTeX treats specially (&, %, $, #, _, braces, ...)."""
+    return _LATEX_SPECIAL_RE.sub(lambda m: _LATEX_SPECIAL[m.group()], text)
+
+
+def to_latex(doc: Optional[ProseMirrorDoc], heading_offset: int = 0) -> str:
+    """Convert a ProseMirror document to a LaTeX fragment.
+
+    Headings become sectioning commands (level 1 is \\section), bullet and
+    numbered lists become itemize and enumerate environments, and text is
+    escaped. The fragment needs no packages, so it can be \\input into any
+    document.
+
+    Args:
+        doc: The ProseMirror document to convert.
+        heading_offset: Levels to move headings down by (1 makes a level 1
+            heading a \\subsection, for notes placed under a section).
+
+    Returns:
+        LaTeX string representation.
+    """
+    if doc is None or doc.type != "doc" or not doc.content:
+        return ""
+
+    blocks = (_render_latex(node, heading_offset) for node in doc.content)
+    return "\n\n".join(block for block in blocks if block.strip()) + "\n"
+
+
+def _render_latex(node: ProseMirrorNode, heading_offset: int) -> str:
+    """Recursively render a ProseMirror node as LaTeX.
+
+    Args:
+        node: The node to render.
+        heading_offset: Levels to move headings down by.
+
+    Returns:
+        LaTeX string for this node.
+    """
+    if node.type == "text":
+        return escape_latex(node.text)
+
+    if node.type == "hardBreak":
+        return "\\\\\n"
+
+    if node.type == "mention":
+        return escape_latex(str(node.attrs.get("label") or node.attrs.get("name") or ""))
+
+    inner = "".join(_render_latex(child, heading_offset) for child in node.content)
+    if not node.content and node.text:
+        inner = escape_latex(node.text)
+
+    if node.type == "heading":
+        level = 1
+        lvl = node.attrs.get("level") if node.attrs else None
+        if isinstance(lvl, (int, float)):
+            level = int(lvl)
+        index = min(max(level + heading_offset, 1), len(_LATEX_SECTIONS)) - 1
+        return f"\\{_LATEX_SECTIONS[index]}{{{inner.strip()}}}"
+
+    elif node.type in ("bulletList", "orderedList"):
+        environment = "itemize" if node.type == "bulletList" else "enumerate"
+        items = [_render_latex(child, heading_offset) for child in node.content]
+        if not items:
+            return ""
+        body = "\n".join(items)
+        return f"\\begin{{{environment}}}\n{body}\n\\end{{{environment}}}"
+
+    elif node.type == "listItem":
+        parts = [_render_latex(child, heading_offset) for child in node.content]
+        return "\\item " + "\n".join(part for part in parts if part.strip())
+
+    return inner
+
+
+def to_asciidoc(doc: Optional[ProseMirrorDoc], heading_offset: int = 0) -> str:
+    """Convert a ProseMirror document to AsciiDoc.
+
+    Args:
+        doc: The ProseMirror document to convert.
+        heading_offset: Levels to move headings down by (1 makes a level 1
+            heading a == section, for notes in a document with a = title).
+
+    Returns:
+        AsciiDoc string representation.
+    """
+    if doc is None or doc.type != "doc" or not doc.content:
+        return ""
+
+    blocks = (_render_asciidoc(node, heading_offset, depth=0) for node in doc.content)
+    return "\n\n".join(block for block in blocks if block.strip()) + "\n"
+
+
+def _render_asciidoc(node: ProseMirrorNode, heading_offset: int, depth: int) -> str:
+    """Recursively render a ProseMirror node as AsciiDoc.
+
+    Args:
+        node: The node to render.
+        heading_offset: Levels to move headings down by.
+        depth: Nesting depth of the enclosing list items.
+
+    Returns:
+        AsciiDoc string for this node.
+    """
+    if node.type == "text":
+        return node.text
+
+    if node.type == "hardBreak":
+        return " +\n"
+
+    if node.type == "mention":
+        return str(node.attrs.get("label") or node.attrs.get("name") or "")
+
+    if node.type in ("bulletList", "orderedList"):
+        marker = ("*" if node.type == "bulletList" else ".") * (depth + 1)
+        items = []
+        for item in node.content:
+            text = ""
+            nested = []
+            for child in item.content:
+                if child.type in ("bulletList", "orderedList"):
+                    nested.append(_render_asciidoc(child, heading_offset, depth + 1))
+                elif not text:
+                    text = _render_asciidoc(child, heading_offset, depth)
+            items.append("\n".join([f"{marker} {text.strip()}", *nested]))
+        return "\n".join(items)
+
+    inner = "".join(_render_asciidoc(child, heading_offset, depth) for child in node.content)
+    if not node.content and node.text:
+        inner = node.text
+
+    if node.type == "heading":
+        level = 1
+        lvl = node.attrs.get("level") if node.attrs else None
+        if isinstance(lvl, (int, float)):
+            level = int(lvl)
+        # AsciiDoc has section levels 0 (the title) to 5
+        return "=" * (min(max(level + heading_offset, 1), 5) + 1) + " " + inner.strip()
+
+    return inner
+
+
+# Underline characters for section levels; the document title uses "=" above and below
+RST_SECTION_CHARS = "=-~^\""
+
+_RST_SPECIAL_RE = re.compile(r"([\\*`_|])")
+
+
+def escape_rst(text: str) -> str:
+    """Escape the characters reStructuredText uses for inline markup."""
+    return _RST_SPECIAL_RE.sub(r"\\\1", text)
+
+
+def rst_heading(text: str, level: int) -> str:
+    """Return a reStructuredText section heading (level 1 is the top section)."""
+    char = RST_SECTION_CHARS[min(max(level, 1), len(RST_SECTION_CHARS)) - 1]
+    return f"{text}\n{char * max(len(text), 1)}"
+
+
+def to_rst(doc: Optional[ProseMirrorDoc], heading_offset: int = 0) -> str:
+    """Convert a ProseMirror document to reStructuredText.
+
+    Args:
+        doc: The ProseMirror document to convert.
+        heading_offset: Levels to move headings down by (1 makes a level 1
+            heading a "-" section, for notes under a "=" section).
+
+    Returns:
+        reStructuredText string representation.
+    """
+    if doc is None or doc.type != "doc" or not doc.content:
+        return ""
+
+    blocks = (_render_rst(node, heading_offset) for node in doc.content)
+    return "\n\n".join(block for block in blocks if block.strip()) + "\n"
+
+
+def _render_rst(node: ProseMirrorNode, heading_offset: int, indent: str = "") -> str:
+    """Recursively render a ProseMirror node as reStructuredText.
+
+    Args:
+        node: The node to render.
+        heading_offset: Levels to move headings down by.
+        indent: Indentation of the enclosing list item's text.
+
+    Returns:
+        reStructuredText string for this node.
+    """
+    if node.type == "text":
+        return escape_rst(node.text)
+
+    if node.type == "hardBreak":
+        return " "
+
+    if node.type == "mention":
+        return escape_rst(str(node.attrs.get("label") or node.attrs.get("name") or ""))
+
+    if node.type in ("bulletList", "orderedList"):
+        marker = "-" if node.type == "bulletList" else "#."
+        child_indent = indent + " " * (len(marker) + 1)
+        items = []
+        for item in node.content:
+            text = ""
+            nested = []
+            for child in item.content:
+                if child.type in ("bulletList", "orderedList"):
+                    # Nested lists are separated from the item text by blank lines
+                    nested.append("\n" + _render_rst(child, heading_offset, child_indent) + "\n")
+                elif not text:
+                    text = _render_rst(child, heading_offset, child_indent)
+            items.append("\n".join([f"{indent}{marker} {text.strip()}", *nested]))
+        return "\n".join(items).rstrip("\n")
+
+    inner = "".join(_render_rst(child, heading_offset, indent) for child in node.content)
+    if not node.content and node.text:
+        inner = escape_rst(node.text)
+
+    if node.type == "heading":
+        level = 1
+        lvl = node.attrs.get("level") if node.attrs else None
+        if isinstance(lvl, (int, float)):
+            level = int(lvl)
+        return rst_heading(inner.strip(), level + heading_offset)
+
+    return inner
@@ -0,0 +1,9 @@
+"""Reports on meetings in the Granola cache."""
+
+from granola.reports.meetings import Attendee, Meeting, load_meetings
+
+__all__ = [
+    "Attendee",
+    "Meeting",
+    "load_meetings",
+]
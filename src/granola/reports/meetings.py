@@ -0,0 +1,79 @@
+"""Meetings as reports see them: when, how long, in which folders, and with whom."""
+
+from dataclasses import dataclass, field
+from datetime import datetime
+
+from granola.cache.reader import CacheData, CacheDocument
+from granola.utils.dates import parse_timestamp
+from granola.utils.stats import transcript_minutes
+
+
+@dataclass
+class Attendee:
+    """A person invited to a meeting."""
+
+    name: str
+    email: str = ""
+
+    @property
+    def key(self) -> str:
+        """Return what identifies the person across meetings (email, else name)."""
+        return (self.email or self.name).lower()
+
+
+@dataclass
+class Meeting:
+    """A meeting from the cache, with the facts reports aggregate."""
+
+    id: str
+    title: str
+    start: datetime
+    minutes: int | None  # transcript length; None without a transcript
+    folders: list[str] = field(default_factory=list)
+    attendees: list[Attendee] = field(default_factory=list)  # without yourself
+
+
+def load_meetings(cache_data: CacheData) -> list[Meeting]:
+    """Build the meetings of the cached documents, oldest first.
+
+    A meeting starts when its calendar event does, else when its transcript
+    does, else when the document was created. Its length is the transcript's,
+    so only recorded time counts.
+    """
+    meetings = []
+    for doc in cache_data.documents.values():
+        segments = cache_data.transcripts.get(doc.id, [])
+        start = (
+            parse_timestamp(doc.event_start)
+            or (parse_timestamp(segments[0].start_timestamp) if segments else None)
+            or parse_timestamp(doc.created_at)
+        )
+        if start is None:
+            continue
+        meetings.append(
+            Meeting(
+                id=doc.id,
+                title=doc.title or "Untitled",
+                start=start,
+                minutes=transcript_minutes(segments) if segments else None,
+                folders=cache_data.get_folder_names(doc.id),
+                attendees=_attendees(doc),
+            )
+        )
+    return sorted(meetings, key=lambda m: m.start)
+
+
+def _attendees(doc: CacheDocument) -> list[Attendee]:
+    """Return the people invited to a document's calendar event, except yourself.
+
+    Rooms and other resources booked for the event are left out.
+    """
+    attendees = []
+    for entry in (doc.calendar_event or {}).get("attendees") or []:
+        if not isinstance(entry, dict) or entry.get("self") or entry.get("resource"):
+            continue
+        email = str(entry.get("email") or "")
+        name = str(entry.get("displayName") or email.split("@")[0])
+        if name:
+            attendees.append(Attendee(name=name, email=email))
+    return attendees
@@ -0,0 +1,127 @@
+"""People report: who you meet with, how often, and whether that's changing."""
+
+import csv
+import io
+import json
+from dataclasses import dataclass, field
+from datetime import datetime
+
+from granola.reports.meetings import Meeting
+
+# Characters of a sparkline, lowest to highest
+_SPARKS = "▁▂▃▄▅▆▇█"
+
+
+@dataclass
+class PersonStats:
+    """Meetings with one person."""
+
+    name: str
+    email: str
+    meetings: int = 0
+    minutes: int = 0  # recorded (transcript) time
+    last_met: datetime | None = None
+    monthly: list[int] = field(default_factory=list)  # meetings per month, oldest first
+
+    @property
+    def hours(self) -> float:
+        """Return the recorded time in hours, to two decimals."""
+        return round(self.minutes / 60, 2)
+
+    @property
+    def trend(self) -> str:
+        """Return "up", "down" or "flat": the recent half of the months against the older."""
+        half = len(self.monthly) // 2
+        older, recent = sum(self.monthly[:half]), sum(self.monthly[len(self.monthly) - half :])
+        if recent > older:
+            return "up"
+        if recent < older:
+            return "down"
+        return "flat"
+
+
+def month_starts(end: datetime, months: int) -> list[datetime]:
+    """Return the first days of the `months` months up to and including end's, oldest first."""
+    first = end.year * 12 + end.month - months
+    return [
+        datetime(index // 12, index % 12 + 1, 1, tzinfo=end.tzinfo)
+        for index in range(first, first + months)
+    ]
+
+
+def people_stats(meetings: list[Meeting], end: datetime, months: int = 6) -> list[PersonStats]:
+    """Add up the meetings with each person, most met first.
+
+    People are told apart by email (or name, without one); each meeting counts
+    for everyone else invited to it.
+
+    Args:
+        meetings: Meetings to report on.
+        end: End of the period the monthly trend covers (usually now).
+        months: Number of months the trend covers, ending with end's month.
+    """
+    end = end.astimezone()
+    first_month = end.year * 12 + end.month - months
+    people: dict[str, PersonStats] = {}
+    for meeting in meetings:
+        local_start = meeting.start.astimezone()
+        month = local_start.year * 12 + local_start.month - 1 - first_month
+        for attendee in meeting.attendees:
+            stats = people.setdefault(
+                attendee.key, PersonStats(attendee.name, attendee.email, monthly=[0] * months)
+            )
+            stats.meetings += 1
+            stats.minutes += meeting.minutes or 0
+            if stats.last_met is None or meeting.start > stats.last_met:
+                stats.last_met = meeting.start
+            if 0 <= month < months and local_start <= end:
+                stats.monthly[month] += 1
+    return sorted(people.values(), key=lambda p: (-p.meetings, -p.minutes, p.name.lower()))
+
+
+def sparkline(values: list[int]) -> str:
+    """Draw values as a sparkline of block characters (▁ for zero)."""
+    top = max(values, default=0)
+    if not top:
+        return _SPARKS[0] * len(values)
+    return "".join(_SPARKS[round(v / top * (len(_SPARKS) - 1))] for v in values)
+
+
+def format_people_csv(people: list[PersonStats], starts: list[datetime]) -> str:
+    """Format people stats as CSV, with a column of meetings per month."""
+    buffer = io.StringIO()
+    writer = csv.writer(buffer, lineterminator="\n")
+    months = [start.strftime("%Y-%m") for start in starts]
+    writer.writerow(["name", "email", "meetings", "hours", "last_met", "trend", *months])
+    for person in people:
+        writer.writerow(
+            [
+                person.name,
+                person.email,
+                person.meetings,
+                f"{person.hours:.2f}",
+                person.last_met.astimezone().strftime("%Y-%m-%d") if person.last_met else "",
+                person.trend,
+                *person.monthly,
+            ]
+        )
+    return buffer.getvalue()
+
+
+def format_people_json(people: list[PersonStats], starts: list[datetime]) -> str:
+    """Format people stats as JSON."""
+    months = [start.strftime("%Y-%m") for start in starts]
+    data = [
+        {
+            "name": person.name,
+            "email": person.email,
+            "meetings": person.meetings,
+            "minutes": person.minutes,
+            "hours": person.hours,
+            "last_met": person.last_met.isoformat() if person.last_met else None,
+            "trend": person.trend,
+            "monthly": dict(zip(months, person.monthly)),
+        }
+        for person in people
+    ]
+    return json.dumps(data, indent=2, ensure_ascii=False) + "\n"
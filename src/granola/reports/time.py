@@ -0,0 +1,123 @@
+"""Time report: recorded meeting time per week, person or folder.
+
+Made for reconciling meeting time against invoices: each meeting's transcript
+length can be rounded up to a billing increment, and the report is available as
+CSV or JSON for spreadsheets and invoicing tools.
+"""
+
+import csv
+import io
+import json
+import math
+from dataclasses import dataclass
+from datetime import timedelta
+
+from granola.reports.meetings import Meeting
+
+# Ways meetings can be grouped
+GROUP_BY = ("week", "person", "folder")
+
+# Group of meetings without a folder or without other attendees
+NO_FOLDER = "(no folder)"
+NO_ATTENDEES = "(no attendees)"
+
+
+@dataclass
+class TimeRow:
+    """Recorded meeting time of one group."""
+
+    group: str
+    meetings: int
+    minutes: int
+
+    @property
+    def hours(self) -> float:
+        """Return the time in hours, to two decimals."""
+        return round(self.minutes / 60, 2)
+
+
+@dataclass
+class TimeReport:
+    """Meeting time grouped by week, person or folder."""
+
+    group_by: str
+    rows: list[TimeRow]
+    total: TimeRow  # every meeting once, even ones in several groups
+    untimed: int = 0  # meetings left out for having no transcript
+
+
+def billable_minutes(minutes: int, round_to: int = 0) -> int:
+    """Round a meeting's minutes up to a billing increment (0 keeps them as they are)."""
+    if round_to <= 0:
+        return minutes
+    return math.ceil(minutes / round_to) * round_to
+
+
+def time_report(meetings: list[Meeting], group_by: str, round_to: int = 0) -> TimeReport:
+    """Add up the recorded time of meetings per group.
+
+    Meetings in several folders, or with several attendees, count towards each of
+    their groups, so grouped by person or folder the rows can add up to more
+    than the total.
+
+    Args:
+        meetings: Meetings to report on.
+        group_by: "week" (weeks starting on Monday), "person" or "folder".
+        round_to: Billing increment in minutes each meeting is rounded up to.
+    """
+    rows: dict[str, TimeRow] = {}
+    total = TimeRow("Total", 0, 0)
+    untimed = 0
+    for meeting in meetings:
+        if meeting.minutes is None:
+            untimed += 1
+            continue
+        minutes = billable_minutes(meeting.minutes, round_to)
+        total.meetings += 1
+        total.minutes += minutes
+        for group in _groups(meeting, group_by):
+            row = rows.setdefault(group, TimeRow(group, 0, 0))
+            row.meetings += 1
+            row.minutes += minutes
+
+    if group_by == "week":
+        ordered = sorted(rows.values(), key=lambda r: r.group)
+    else:
+        ordered = sorted(rows.values(), key=lambda r: (-r.minutes, r.group.lower()))
+    return TimeReport(group_by, ordered, total, untimed)
+
+
+def format_time_csv(report: TimeReport) -> str:
+    """Format a time report as CSV, one row per group (without the total)."""
+    buffer = io.StringIO()
+    writer = csv.writer(buffer, lineterminator="\n")
+    writer.writerow([report.group_by, "meetings", "minutes", "hours"])
+    for row in report.rows:
+        writer.writerow([row.group, row.meetings, row.minutes, f"{row.hours:.2f}"])
+    return buffer.getvalue()
+
+
+def format_time_json(report: TimeReport) -> str:
+    """Format a time report as JSON, with its rows and total."""
+
+    def entry(row: TimeRow) -> dict[str, object]:
+        return {"meetings": row.meetings, "minutes": row.minutes, "hours": row.hours}
+
+    data = {
+        "group_by": report.group_by,
+        "rows": [{report.group_by: row.group, **entry(row)} for row in report.rows],
+        "total": entry(report.total),
+        "untimed_meetings": report.untimed,
+    }
+    return json.dumps(data, indent=2, ensure_ascii=False) + "\n"
+
+
+def _groups(meeting: Meeting, group_by: str) -> list[str]:
+    """Return the groups a meeting counts towards."""
+    if group_by == "week":
+        start = meeting.start.astimezone()
+        return [(start - timedelta(days=start.weekday())).strftime("%Y-%m-%d")]
+    if group_by == "person":
+        names = {attendee.key: attendee.name for attendee in meeting.attendees}
+        return list(names.values()) or [NO_ATTENDEES]
+    return meeting.folders or [NO_FOLDER]
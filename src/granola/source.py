@@ -0,0 +1,913 @@
+"""Render Granola documents for export.
+
+ExportSource combines documents fetched from the API with transcripts and
+folders from the local cache, and renders them lazily for a Syncer.
+"""
+
+import html
+import logging
+import re
+from dataclasses import dataclass, field
+from datetime import datetime, timezone
+from functools import partial
+from typing import Any, Callable, Iterator
+
+from granola.api.models import (
+    DEEP_LINKS,
+    Document,
+    GeneratedLine,
+    LastViewedPanel,
+    Person,
+    ProseMirrorDoc,
+    SuggestedQuestion,
+    parse_attendees,
+    parse_generated_lines,
+    parse_suggested_questions,
+)
+from granola.cache.reader import SharedDocument, TranscriptSegment
+from granola.config.speakers import SpeakerMap
+from granola.formatters.combined import format_transcript
+from granola.formatters.interleaved import interleave
+from granola.formatters.sections import summarize_sections, time_sections
+from granola.formatters.wikilinks import link_names
+from granola.interfaces import Renderer, Store
+from granola.prosemirror.converter import to_html, to_markdown
+from granola.utils.dates import latest_timestamp, parse_timestamp
+from granola.utils.duplicates import duplicate_groups
+from granola.utils.filename import slugify
+from granola.utils.language import detect_language
+from granola.utils.selection import sort_documents, title_selected
+from granola.utils.stats import (
+    DEFAULT_READING_WPM,
+    content_word_count,
+    reading_minutes,
+    transcript_minutes,
+    word_count,
+)
+from granola.utils.timing import Timings
+from granola.writers.run_state import RunState
+from granola.writers.sync_writer import ExportDoc, SyncFailure, folder_ancestry
+
+
+@dataclass
+class ExportSource:
+    """Fetched API and cache data that export documents are rendered from.
+
+    Documents are rendered lazily by iter_docs(), so only one formatted document
+    needs to be held in memory at a time.
+    """
+
+    api_docs: list[Document]
+    cache_data: Store
+    api_doc_folders: dict[str, list[str]] = field(default_factory=dict)
+    excluded: set[str] = field(default_factory=set)
+    included: set[str] = field(default_factory=set)
+    folder_parents: dict[str, str] = field(default_factory=dict)
+    # If set, only API documents in these workspaces are exported (shared documents
+    # from the cache have no workspace and are skipped)
+    workspace_ids: set[str] | None = None
+    # Documents shared with the user by teammates (--include-shared), marked as
+    # shared with their owner in the frontmatter
+    shared_ids: set[str] = field(default_factory=set)
+    # If set, attendees are added to the frontmatter, with missing details (name,
+    # company) filled in from these people by lowercase email
+    people: dict[str, Person] | None = None
+    # Every panel of each document (--all-panels); documents listed here have all
+    # their panels exported as notes instead of just the last viewed one
+    panels: dict[str, list[LastViewedPanel]] = field(default_factory=dict)
+    # Partial export (--limit, --skip): the page of documents, most recent first
+    limit: int | None = None
+    skip: int = 0
+    # Order documents are processed in (--sort, --order; see sort_documents). By
+    # default, as fetched (or most recently updated first for a partial export)
+    sort: str | None = None
+    order: str | None = None
+    # Kind of link back to the document in Granola to add (--deep-link; a key of
+    # DEEP_LINKS), if any
+    deep_link: str | None = None
+    # Link attendee names in the notes as [[Person Name]] (--wikilinks)
+    wikilinks: bool = False
+    # Add the AI chat's answers and suggested questions after the notes, in an
+    # "## AI Q&A" section (--ai-qa)
+    ai_qa: bool = False
+    # Title patterns (--match, --exclude-match; see title_selected)
+    title_match: re.Pattern[str] | None = None
+    title_exclude: re.Pattern[str] | None = None
+    # Leave out documents without a title (--skip-untitled)
+    skip_untitled: bool = False
+    # If set, add each document's folders to the frontmatter as tags with this prefix,
+    # e.g. "meeting/" for meeting/clients/acme (folder_tags in [export])
+    folder_tag_prefix: str | None = None
+    # Tags added to every document's frontmatter, after any folder tags (--add-tag)
+    extra_tags: list[str] = field(default_factory=list)
+    # Add the notes' word count, the transcript's length and an estimated reading
+    # time of both (at reading_wpm words per minute) to the frontmatter (--stats)
+    stats: bool = False
+    reading_wpm: int = DEFAULT_READING_WPM
+    # Add the detected language of the notes and transcript to the frontmatter
+    # (--detect-lang), and if languages is set, export only documents in them (--lang)
+    detect_lang: bool = False
+    languages: set[str] | None = None
+    # If set, only export documents with at least this many words in their notes and
+    # transcript together (--min-words), leaving out accidental recordings
+    min_words: int | None = None
+    # Real names for transcript speakers (speakers.yaml)
+    speakers: SpeakerMap | None = None
+    # How notes and transcript are arranged (--layout; see LAYOUTS). "interleaved"
+    # moves notes that record when they were written into the transcript
+    layout: str = "sections"
+    # Split transcripts into sections with HH:MM headings every section_minutes
+    # (--section-minutes) and at silences of section_gap seconds (--section-gap)
+    section_minutes: int | None = None
+    section_gap: int | None = None
+    # Returns a one-line summary of a transcript section's segments (e.g. from an
+    # LLM), shown as a blockquote under its heading
+    section_summarizer: Callable[[list[TranscriptSegment]], str | None] | None = None
+    # Audio recording of each document, as shown in the frontmatter (--audio)
+    audio: dict[str, str] = field(default_factory=dict)
+    # State of the last successful run (--changed-only); documents not updated since
+    # are left out when rendering, but still part of the outline
+    changed_since: RunState | None = None
+    # Documents created before archived_before that already have files in the archive
+    # (--archive-older-than); they are neither rendered nor part of the outline
+    archived_before: datetime | None = None
+    archived: set[str] = field(default_factory=set)
+    # Skip documents with no transcript and no words in their notes (--skip-empty, the
+    # default) rather than writing files with just a header; their IDs are collected
+    # in empty as they are skipped
+    skip_empty: bool = True
+    empty: set[str] = field(default_factory=set)
+    # Merge duplicates of a meeting (see duplicate_groups) into one document (--merge),
+    # keeping those merged by earlier exports (merged document ID -> IDs merged) together
+    # under the same ID
+    merge: bool = False
+    merged: dict[str, list[str]] = field(default_factory=dict)
+    # Fetches extra data of documents by ID (API transcripts, all panels, audio) into
+    # this source. iter_docs calls it just before documents are first rendered, with
+    # only as many as it takes to fill the page, so nothing is fetched for documents
+    # the filters, --changed-only, --limit or --skip leave out
+    prepare: Callable[[list[str]], None] | None = None
+    prepared: set[str] = field(default_factory=set)
+    timings: Timings = field(default_factory=Timings)
+
+    def folder_names(self, doc_id: str) -> list[str]:
+        """Get folder names for a document, preferring API over cache."""
+        if doc_id in self.api_doc_folders:
+            return self.api_doc_folders[doc_id]
+        return self.cache_data.get_folder_names(doc_id)
+
+    def doc_ids(self) -> set[str]:
+        """Return the IDs of all documents that exist in Granola (for orphan detection).
+
+        Filtered documents still exist in Granola, so they are not orphans.
+        """
+        ids = {doc.id for doc in self.api_docs}
+        ids.update(doc.id for doc in self.cache_data.shared_documents.values())
+        return ids
+
+    def iter_docs(
+        self,
+        formatter: Renderer | None,
+        logger: logging.Logger | None = None,
+        on_error: Callable[[SyncFailure], None] | None = None,
+        stop: Callable[[], bool] | None = None,
+    ) -> Iterator[ExportDoc]:
+        """Yield export documents from API documents, then shared cache documents.
+
+        A document that fails to render is skipped (and passed to on_error) rather
+        than ending the iteration. With limit or skip set, only that page of the
+        documents is yielded, most recently updated first unless sort is set. With
+        changed_since set, documents not updated since that run aren't rendered.
+        With prepare set, it is called for documents before they're first rendered
+        (see _prepare).
+
+        Args:
+            formatter: Renders a document's file content. If None, documents are
+                yielded without content (an outline for planning folder renames).
+            logger: Optional logger for debug output.
+            on_error: Called with each document that failed to render.
+            stop: Checked before each document; once it returns True, no more
+                documents are yielded (e.g. after too many errors).
+        """
+        logger = logger or logging.getLogger(__name__)
+
+        candidates = self._ordered_candidates(logger)
+
+        # Documents without notes or transcript aren't exported, so they don't count
+        # towards skip and limit
+        skipped = 0
+        yielded = 0
+        done: set[str] = set()
+        index = 0
+        while index < len(candidates):
+            candidate = candidates[index]
+            index += 1
+            if candidate.doc_id in done:
+                continue
+            done.add(candidate.doc_id)
+            if self.limit is not None and yielded >= self.limit:
+                break
+            if stop and stop():
+                break
+            if self._is_archived(candidate):
+                logger.debug(f"Skipping document '{candidate.title}' - archived")
+                continue
+            if formatter and self._is_unchanged(candidate):
+                logger.debug(f"Skipping document '{candidate.title}' - unchanged since last run")
+                continue
+            if self._needs_preparing(candidate):
+                needed = None if self.limit is None else self.skip + self.limit - skipped - yielded
+                self._prepare(candidates[index - 1 :], needed)
+                # Fetched panels can change documents' content and when they were
+                # updated, so start over (skipping those already done)
+                candidates = self._ordered_candidates(logger)
+                index = 0
+                prepared = next((c for c in candidates if c.doc_id == candidate.doc_id), None)
+                if prepared is None:
+                    continue
+                candidate = prepared
+            selected = skipped >= self.skip
+            try:
+                with self.timings.phase("render" if formatter and selected else "outline"):
+                    notes = self._notes(candidate)
+                    segments = self._segments(candidate)
+                    if self.speakers:
+                        segments = self.speakers.apply(
+                            segments, candidate.doc_id, self._attendee_emails(candidate)
+                        )
+                    if self.min_words is not None:
+                        words = word_count(notes) + sum(word_count(s.text) for s in segments)
+                        if words < self.min_words:
+                            logger.debug(
+                                f"Skipping document '{candidate.title}' - {words} words"
+                            )
+                            continue
+                    metadata = candidate.metadata
+                    tags = list(self.extra_tags)
+                    if self.folder_tag_prefix is not None:
+                        tags = folder_tags(
+                            candidate.folders, self.folder_tag_prefix, self.folder_parents
+                        ) + tags
+                    if tags:
+                        metadata = {**metadata, "tags": list(dict.fromkeys(tags))}
+                    if self.stats:
+                        metadata = {**metadata, **self._stats(notes, segments)}
+                    if self.detect_lang or self.languages is not None:
+                        lang = _document_language(notes, segments)
+                        if self.languages is not None and lang not in self.languages:
+                            logger.debug(
+                                f"Skipping document '{candidate.title}' - language {lang}"
+                            )
+                            continue
+                        if lang:
+                            metadata = {**metadata, "lang": lang}
+                    if self.layout == "interleaved":
+                        notes, segments = self._interleave(candidate, notes, segments)
+                    if self.ai_qa:
+                        notes = _with_ai_qa(notes, candidate)
+                    segments = time_sections(segments, self.section_minutes, self.section_gap)
+                    if self.section_summarizer and selected:
+                        segments = summarize_sections(segments, self.section_summarizer)
+                    export_doc = _make_export_doc(
+                        doc_id=candidate.doc_id,
+                        title=candidate.title,
+                        created_at=candidate.created_at,
+                        updated_at=candidate.updated_at,
+                        notes_content=notes,
+                        segments=segments,
+                        folders=candidate.folders,
+                        tags=candidate.tags,
+                        formatter=formatter if selected else None,
+                        metadata=metadata,
+                        merged_from=candidate.merged_from,
+                        started_at=candidate.started_at,
+                        skip_empty=self.skip_empty,
+                    )
+            except Exception as e:
+                _report_render_error(candidate.doc_id, candidate.title, e, logger, on_error)
+                continue
+            if export_doc is None:
+                logger.debug(f"Skipping document '{candidate.title}' - no notes or transcript")
+                self.empty.add(candidate.doc_id)
+                continue
+            if not selected:
+                skipped += 1
+                continue
+            yielded += 1
+            yield export_doc
+
+    def _ordered_candidates(self, logger: logging.Logger) -> list["_Candidate"]:
+        """Return the documents that pass the filters, merged and in the order they're paged."""
+        candidates = list(self._candidates(logger))
+        if self.merge:
+            candidates = self._merge_duplicates(candidates, logger)
+        if self.sort or self.limit is not None or self.skip:
+            candidates = sort_documents(candidates, self.sort or "updated", self.order)
+        return candidates
+
+    def _is_unchanged(self, candidate: "_Candidate") -> bool:
+        """Return True if a candidate hasn't been updated since the last run (--changed-only)."""
+        return self.changed_since is not None and not self.changed_since.changed(
+            candidate.doc_id, candidate.updated_at
+        )
+
+    def _needs_preparing(self, candidate: "_Candidate") -> bool:
+        """Return True if a candidate's extra data is still to be fetched (see prepare)."""
+        return (
+            self.prepare is not None
+            and candidate.doc_id not in self.prepared
+            and not self._is_archived(candidate)
+            and not self._is_unchanged(candidate)
+        )
+
+    def _prepare(self, candidates: list["_Candidate"], needed: int | None) -> None:
+        """Call prepare for the next candidates to be rendered.
+
+        Args:
+            candidates: Candidates in paging order, from the first one not prepared.
+            needed: Documents still needed to fill the page (None for no limit).
+                Some may turn out to be empty or filtered by their content, in
+                which case iter_docs prepares the next ones when it gets to them.
+        """
+        if self.prepare is None:
+            return
+        batch = [c for c in candidates if self._needs_preparing(c)][:needed]
+        doc_ids = list(dict.fromkeys(i for c in batch for i in [c.doc_id, *c.merged_from]))
+        self.prepare(doc_ids)
+        self.prepared.update(doc_ids)
+
+    def _candidates(self, logger: logging.Logger) -> Iterator["_Candidate"]:
+        """Yield the documents that pass the filters, API documents first."""
+        seen: set[str] = set()
+
+        for api_doc in self.api_docs:
+            seen.add(api_doc.id)
+
+            if self.workspace_ids is not None and api_doc.workspace_id not in self.workspace_ids:
+                logger.debug(f"Skipping document '{api_doc.title}' - other workspace")
+                continue
+
+            # Get folder names for this document (from API, not cache)
+            folders = self.folder_names(api_doc.id)
+
+            # Skip if document is in an excluded folder (or outside the allow-list)
+            if is_filtered_out(folders, self.excluded, self.included, self.folder_parents):
+                logger.debug(f"Skipping document '{api_doc.title}' - folder filtered")
+                continue
+
+            if not title_selected(
+                api_doc.title, self.title_match, self.title_exclude, self.skip_untitled
+            ):
+                logger.debug(f"Skipping document '{api_doc.title}' - title filtered")
+                continue
+
+            panels = self._api_panels(api_doc)
+            yield _Candidate(
+                doc_id=api_doc.id,
+                title=api_doc.title,
+                created_at=api_doc.created_at,
+                updated_at=self._updated_at(api_doc),
+                folders=folders,
+                tags=api_doc.tags or [],
+                metadata=self._metadata(
+                    api_doc.id,
+                    api_doc.owner,
+                    api_doc.attendees,
+                    api_doc.link,
+                    api_doc.meeting_url,
+                    api_doc.event_uid,
+                ),
+                notes=partial(self._api_notes_content, api_doc),
+                attendees=api_doc.attendees,
+                notes_doc=api_doc.notes,
+                questions=[q for p in panels for q in p.suggested_questions],
+                generated_lines=[line for p in panels for line in p.generated_lines],
+                started_at=self._event_start(api_doc.id, api_doc.event_start),
+            )
+
+        # Process shared documents from cache
+        if self.workspace_ids is not None:
+            return
+        for shared_doc in self.cache_data.shared_documents.values():
+            # Skip if we already have this document from the API
+            if shared_doc.id in seen:
+                continue
+
+            folders = self.folder_names(shared_doc.id)
+            if is_filtered_out(folders, self.excluded, self.included, self.folder_parents):
+                logger.debug(f"Skipping shared document '{shared_doc.title}' - folder filtered")
+                continue
+
+            if not title_selected(
+                shared_doc.title, self.title_match, self.title_exclude, self.skip_untitled
+            ):
+                logger.debug(f"Skipping shared document '{shared_doc.title}' - title filtered")
+                continue
+
+            yield _Candidate(
+                doc_id=shared_doc.id,
+                title=shared_doc.title,
+                created_at=shared_doc.created_at,
+                updated_at=shared_doc.updated_at,
+                folders=folders,
+                tags=[],
+                metadata=self._metadata(
+                    shared_doc.id, shared_doc.owner, parse_attendees(shared_doc.people)
+                ),
+                notes=partial(_get_shared_notes_content, shared_doc),
+                attendees=parse_attendees(shared_doc.people),
+                questions=parse_suggested_questions(
+                    (shared_doc.last_viewed_panel or {}).get("suggested_questions")
+                ),
+                generated_lines=parse_generated_lines(
+                    (shared_doc.last_viewed_panel or {}).get("generated_lines")
+                ),
+                started_at=self._event_start(shared_doc.id),
+            )
+
+    def _merge_duplicates(
+        self, candidates: list["_Candidate"], logger: logging.Logger
+    ) -> list["_Candidate"]:
+        """Replace each set of duplicate candidates with one merged candidate (--merge).
+
+        A merged candidate takes the ID of the earliest duplicate, or the ID it was
+        merged under before. Once one of the documents merged before is deleted,
+        the merged files hold the only copy of its notes, so they are left as they
+        are (and the rest of its duplicates aren't exported separately).
+        """
+        existing = self.doc_ids()
+        merged_into = {
+            source_id: doc_id for doc_id, sources in self.merged.items() for source_id in sources
+        }
+        keys = {c.doc_id: merged_into[c.doc_id] for c in candidates if c.doc_id in merged_into}
+        for group in duplicate_groups(
+            candidates,
+            title=lambda c: c.title,
+            created_at=lambda c: c.created_at,
+            event_uid=lambda c: c.metadata.get("event_uid"),
+        ):
+            key = next((keys[c.doc_id] for c in group if c.doc_id in keys), group[0].doc_id)
+            for candidate in group:
+                keys.setdefault(candidate.doc_id, key)
+
+        groups: dict[str, list[_Candidate]] = {}
+        for candidate in candidates:
+            groups.setdefault(keys.get(candidate.doc_id, candidate.doc_id), []).append(candidate)
+
+        merged: list[_Candidate] = []
+        for doc_id, group in groups.items():
+            if len(group) == 1 and group[0].doc_id == doc_id and doc_id not in self.merged:
+                merged.append(group[0])
+                continue
+            if not existing.issuperset(self.merged.get(doc_id, [])):
+                logger.debug(
+                    f"Keeping merged document '{group[0].title}' as it is - "
+                    f"some of its duplicates were deleted"
+                )
+                continue
+            candidate = _merge_candidates(doc_id, group, self.merged.get(doc_id, []))
+            logger.debug(
+                f"Merging {len(candidate.merged_from)} duplicates of '{candidate.title}' "
+                f"into {doc_id}"
+            )
+            merged.append(candidate)
+        return merged
+
+    def _is_archived(self, candidate: "_Candidate") -> bool:
+        """Return True if a candidate is old and already in the archive."""
+        if self.archived_before is None or candidate.doc_id not in self.archived:
+            return False
+        created = parse_timestamp(candidate.created_at)
+        return created is not None and created < self.archived_before
+
+    def _segments(self, candidate: "_Candidate") -> list[TranscriptSegment]:
+        """Return a candidate's transcript (all its duplicates' segments, if merged)."""
+        if not candidate.merged_from:
+            return self.cache_data.transcripts.get(candidate.doc_id, [])
+        segments: dict[tuple[str, str], TranscriptSegment] = {}
+        for doc_id in candidate.merged_from:
+            for segment in self.cache_data.transcripts.get(doc_id, []):
+                segments.setdefault((segment.start_timestamp, segment.text.strip()), segment)
+        return sorted(segments.values(), key=lambda segment: segment.start_timestamp)
+
+    def _notes(self, candidate: "_Candidate", notes: str | None = None) -> str | None:
+        """Return a candidate's notes (or the notes given), with attendees linked (--wikilinks)."""
+        notes = candidate.notes() if notes is None else notes
+        if not self.wikilinks or not notes:
+            return notes
+        names = [self._enrich(attendee).name for attendee in candidate.attendees]
+        return link_names(notes, names)
+
+    def _interleave(
+        self, candidate: "_Candidate", notes: str | None, segments: list[TranscriptSegment]
+    ) -> tuple[str | None, list[TranscriptSegment]]:
+        """Move a candidate's timestamped notes into its transcript (interleaved layout).
+
+        Only typed notes (an API document's notes) record when they were written.
+        Notes from panels are left as they are.
+        """
+        if not segments or candidate.notes_doc is None:
+            return notes, segments
+        untimed, timeline = interleave(candidate.notes_doc, segments, candidate.doc_id)
+        if timeline is segments:
+            return notes, segments
+        if candidate.doc_id not in self.panels:
+            notes = self._notes(candidate, to_markdown(untimed))
+        return notes, timeline
+
+    def _attendee_emails(self, candidate: "_Candidate") -> list[str]:
+        """Return the emails of a candidate's attendees (from the cache if not fetched)."""
+        emails = [attendee.email for attendee in candidate.attendees if attendee.email]
+        if not emails and candidate.doc_id in self.cache_data.documents:
+            emails = self.cache_data.documents[candidate.doc_id].attendee_emails
+        return emails
+
+    def _enrich(self, attendee: Person) -> Person:
+        """Fill in an attendee's missing details from the people directory, if fetched."""
+        if self.people is None:
+            return attendee
+        return attendee.merge(self.people.get(attendee.email.lower(), Person()))
+
+    def _event_start(self, doc_id: str, event_start: str | None = None) -> str | None:
+        """Return the start of a document's calendar event (from the cache if not fetched)."""
+        if not event_start and doc_id in self.cache_data.documents:
+            event_start = self.cache_data.documents[doc_id].event_start
+        return event_start
+
+    def _updated_at(self, doc: Document) -> str:
+        """Return when an API document's content last changed, including all its panels."""
+        panels = self.panels.get(doc.id, [])
+        return (
+            latest_timestamp(
+                doc.content_updated_at,
+                *(timestamp for p in panels for timestamp in (p.updated_at, p.content_updated_at)),
+            )
+            or doc.updated_at
+        )
+
+    def _api_panels(self, doc: Document) -> list[LastViewedPanel]:
+        """Return the panels of an API document (all of them with --all-panels)."""
+        if doc.id in self.panels:
+            return self.panels[doc.id]
+        return [doc.last_viewed_panel] if doc.last_viewed_panel else []
+
+    def _api_notes_content(self, doc: Document) -> str | None:
+        """Return the notes of an API document (all its panels with --all-panels)."""
+        if doc.id in self.panels:
+            return get_panels_content(self.panels[doc.id])
+        return get_notes_content(doc)
+
+    def _metadata(
+        self,
+        doc_id: str,
+        owner: str | None,
+        attendees: list[Person],
+        share_url: str | None = None,
+        meeting_url: str | None = None,
+        event_uid: str | None = None,
+    ) -> dict[str, Any]:
+        """Return the extra frontmatter fields of a document (see Renderer)."""
+        metadata: dict[str, Any] = {}
+        if doc_id in self.cache_data.documents:
+            # Shared documents (and API documents without their event) only have
+            # the calendar event in the cache
+            cached = self.cache_data.documents[doc_id]
+            meeting_url = meeting_url or cached.meeting_url
+            event_uid = event_uid or cached.event_uid
+        if self.deep_link:
+            metadata["granola_url"] = DEEP_LINKS[self.deep_link].format(id=doc_id)
+        if share_url:
+            metadata["share_url"] = share_url
+        if meeting_url:
+            metadata["meeting_url"] = meeting_url
+        if event_uid:
+            metadata["event_uid"] = event_uid
+        if doc_id in self.audio:
+            metadata["audio"] = self.audio[doc_id]
+        if doc_id in self.shared_ids:
+            metadata["shared"] = True
+            if owner:
+                metadata["owner"] = owner
+        if self.people is not None and attendees:
+            metadata["attendees"] = [self._enrich(a).to_metadata() for a in attendees]
+        return metadata
+
+    def _stats(self, notes: str | None, segments: list[TranscriptSegment]) -> dict[str, Any]:
+        """Return the size fields of a document's frontmatter (--stats)."""
+        words = word_count(notes)
+        fields: dict[str, Any] = {"words": words}
+        if segments:
+            fields["transcript_minutes"] = transcript_minutes(segments)
+            words += sum(word_count(segment.text) for segment in segments)
+        fields["reading_minutes"] = reading_minutes(words, self.reading_wpm)
+        return fields
+
+
+@dataclass
+class _Candidate:
+    """A document that passed the filters, with what's needed to render it."""
+
+    doc_id: str
+    title: str
+    created_at: str
+    updated_at: str
+    folders: list[str]
+    tags: list[str]
+    metadata: dict[str, Any]
+    notes: Callable[[], str | None]  # Notes are converted only when rendered
+    attendees: list[Person] = field(default_factory=list)
+    notes_doc: ProseMirrorDoc | None = None  # typed notes, for the interleaved layout
+    # The AI chat's suggested questions and answer lines, for --ai-qa
+    questions: list[SuggestedQuestion] = field(default_factory=list)
+    generated_lines: list[GeneratedLine] = field(default_factory=list)
+    merged_from: list[str] = field(default_factory=list)  # duplicates merged in (--merge)
+    started_at: str | None = None  # calendar event start, in the event's timezone
+
+
+def _merge_candidates(doc_id: str, group: list[_Candidate], earlier: list[str]) -> _Candidate:
+    """Merge duplicate candidates into one, named after the earliest.
+
+    The merged candidate has the notes and folders of all of them, their earliest
+    created and latest updated time, and the earliest's other details. Merged
+    notes aren't timestamped, so they aren't moved into an interleaved transcript.
+
+    Args:
+        doc_id: ID of the merged candidate.
+        group: The duplicates.
+        earlier: IDs of the documents merged under doc_id before, some perhaps deleted.
+    """
+    group = sort_documents(group, "created", "asc")
+    first = group[0]
+    merged_from = sorted({doc_id, *earlier, *(c.doc_id for c in group)})
+    metadata: dict[str, Any] = {}
+    for candidate in reversed(group):
+        metadata.update(candidate.metadata)
+    attendees: dict[str, Person] = {}
+    for candidate in group:
+        for attendee in candidate.attendees:
+            attendees.setdefault((attendee.email or attendee.name).lower(), attendee)
+    return _Candidate(
+        doc_id=doc_id,
+        title=first.title,
+        created_at=first.created_at,
+        updated_at=sort_documents(group, "updated", "desc")[0].updated_at,
+        folders=list(dict.fromkeys(f for c in group for f in c.folders)),
+        tags=list(dict.fromkeys(t for c in group for t in c.tags)),
+        metadata={**metadata, "merged_from": merged_from},
+        notes=partial(_merged_notes, [c.notes for c in group]),
+        attendees=list(attendees.values()),
+        questions=[q for c in group for q in c.questions],
+        generated_lines=[line for c in group for line in c.generated_lines],
+        merged_from=merged_from,
+        started_at=first.started_at,
+    )
+
+
+def _with_ai_qa(notes: str | None, candidate: _Candidate) -> str | None:
+    """Return notes followed by a candidate's AI Q&A section, if it has one."""
+    qa = get_ai_qa_content(candidate.questions, candidate.generated_lines)
+    if qa is None:
+        return notes
+    return f"{notes.strip()}\n\n{qa}" if notes and notes.strip() else qa
+
+
+def _merged_notes(notes: list[Callable[[], str | None]]) -> str | None:
+    """Return the notes of duplicates one after another, leaving out empty and repeated ones."""
+    parts = list(dict.fromkeys(text.strip() for get in notes if (text := get()) and text.strip()))
+    return "\n\n".join(parts) or None
+
+
+def is_filtered_out(
+    folders: list[str],
+    excluded: set[str],
+    included: set[str],
+    folder_parents: dict[str, str] | None = None,
+) -> bool:
+    """Return True if a document's folders exclude it from the export.
+
+    A document is skipped if it is in any excluded folder, or if an allow-list is
+    set and it is in none of the included folders. Subfolders of an excluded (or
+    included) folder count as excluded (or included).
+    """
+    ancestries = [folder_ancestry(f, folder_parents or {}) for f in folders]
+    if excluded and any(f in excluded for ancestry in ancestries for f in ancestry):
+        return True
+    if included and not any(f in included for ancestry in ancestries for f in ancestry):
+        return True
+    return False
+
+
+def folder_tags(
+    folders: list[str], prefix: str, folder_parents: dict[str, str] | None = None
+) -> list[str]:
+    """Return a document's folders as tags, e.g. ["meeting/clients/acme"].
+
+    Folder names are slugified, as tags can't contain spaces, and nested folders
+    are tagged with their full path (Obsidian shows those as nested tags).
+    """
+    tags = []
+    for folder in folders:
+        path = reversed(folder_ancestry(folder, folder_parents or {}))
+        tags.append(prefix + "/".join(slugify(name) for name in path))
+    return list(dict.fromkeys(tags))
+
+
+def _make_export_doc(
+    doc_id: str,
+    title: str,
+    created_at: str,
+    updated_at: str,
+    notes_content: str | None,
+    segments: list[TranscriptSegment],
+    folders: list[str],
+    tags: list[str],
+    formatter: Renderer | None,
+    metadata: dict[str, Any] | None = None,
+    merged_from: list[str] | None = None,
+    started_at: str | None = None,
+    skip_empty: bool = True,
+) -> ExportDoc | None:
+    """Render a single document, or return None if it is empty and skip_empty is set.
+
+    A document is empty if it has no transcript and no words in its notes
+    outside headings (just a template's headings and empty list items, say).
+    Without a formatter, the document is returned without content or transcript.
+    metadata is passed to the formatter only if non-empty (see Renderer).
+    """
+    has_notes = bool(notes_content and notes_content.strip())
+    has_transcript = len(segments) > 0
+    if skip_empty and not has_transcript and not content_word_count(notes_content):
+        return None
+
+    content = ""
+    transcript_text = ""
+    if formatter:
+        content = formatter(
+            title=title,
+            doc_id=doc_id,
+            created_at=created_at,
+            updated_at=updated_at,
+            notes_content=notes_content,
+            segments=segments,
+            folders=folders,
+            **({"metadata": metadata} if metadata else {}),
+        )
+        # Transcript is formatted separately for webhooks
+        transcript_text = format_transcript(segments) if segments else ""
+
+    now = datetime.now(timezone.utc)
+    return ExportDoc(
+        id=doc_id,
+        title=title,
+        created_at=parse_timestamp(created_at) or now,
+        updated_at=parse_timestamp(updated_at) or now,
+        content=content,
+        folders=folders,
+        has_notes=has_notes,
+        has_transcript=has_transcript,
+        notes_content=notes_content or "",
+        transcript_content=transcript_text,
+        tags=tags,
+        merged_from=merged_from or [],
+        started_at=parse_timestamp(started_at),
+    )
+
+
+def _document_language(notes: str | None, segments: list[TranscriptSegment]) -> str | None:
+    """Detect the language a meeting was held in: its transcript's, or else its notes'."""
+    return detect_language(" ".join(s.text for s in segments)) or detect_language(notes)
+
+
+def _report_render_error(
+    doc_id: str,
+    title: str,
+    error: Exception,
+    logger: logging.Logger,
+    on_error: Callable[[SyncFailure], None] | None,
+) -> None:
+    """Log a document that failed to render and pass it to on_error."""
+    logger.warning(f"Failed to render '{title}' ({doc_id}): {error}")
+    if on_error:
+        on_error(SyncFailure(doc_id=doc_id, title=title, error=str(error)))
+
+
+def get_notes_content(doc: Document) -> str | None:
+    """Extract Granola AI-generated notes from an API document.
+
+    Priority:
+    1. Notes (ProseMirror) - converted to Markdown (if non-empty)
+    2. LastViewedPanel.Content (ProseMirror) - converted to Markdown
+    3. LastViewedPanel.OriginalContent (HTML)
+    4. Content (raw)
+
+    Note: notes_plain is human-written notes, not Granola AI notes.
+    """
+    # Try Notes (ProseMirror) - Granola AI-generated notes
+    if doc.notes:
+        content = to_markdown(doc.notes)
+        if content and content.strip():
+            return content
+
+    # Try LastViewedPanel.Content (ProseMirror) - also AI-generated
+    if doc.last_viewed_panel and doc.last_viewed_panel.content:
+        return to_markdown(doc.last_viewed_panel.content)
+
+    # Try LastViewedPanel.OriginalContent (HTML - return as-is)
+    if doc.last_viewed_panel and doc.last_viewed_panel.original_content:
+        return doc.last_viewed_panel.original_content
+
+    # Fallback to Content field
+    return doc.content
+
+
+def get_notes_html(doc: Document) -> str:
+    """Render a document's AI-generated notes as HTML.
+
+    Uses the same source priority as get_notes_content.
+    """
+    if doc.notes:
+        content = to_html(doc.notes)
+        if content.strip():
+            return content
+
+    if doc.last_viewed_panel and doc.last_viewed_panel.content:
+        return to_html(doc.last_viewed_panel.content)
+
+    if doc.last_viewed_panel and doc.last_viewed_panel.original_content:
+        return doc.last_viewed_panel.original_content
+
+    if doc.content and doc.content.strip():
+        return f"<p>{html.escape(doc.content)}</p>"
+
+    return ""
+
+
+def get_panels_content(panels: list[LastViewedPanel]) -> str | None:
+    """Combine the notes of several panels, each under a heading with its title."""
+    sections: list[str] = []
+    for panel in panels:
+        if panel.content:
+            content = to_markdown(panel.content)
+        else:
+            content = panel.original_content or ""
+        if not content.strip():
+            continue
+        sections.append(f"### {panel.title or 'Notes'}\n\n{content.strip()}")
+    return "\n\n".join(sections) or None
+
+
+def get_ai_qa_content(
+    questions: list[SuggestedQuestion], lines: list[GeneratedLine]
+) -> str | None:
+    """Render the AI chat's answers and suggested questions as an "## AI Q&A" section.
+
+    Each answered question gets a ### heading with its answer: the lines generated
+    for it, or else the answer recorded with the suggestion. Lines that don't say
+    which question they answer come first, without a heading; questions not asked
+    yet are listed last.
+    """
+    answers: dict[str, list[str]] = {}
+    for line in lines:
+        answers.setdefault(line.question or "", []).append(line.text)
+    for question in questions:
+        if question.answer and question.question not in answers:
+            answers[question.question] = [question.answer.strip()]
+    unanswered = list(dict.fromkeys(q.question for q in questions if q.question not in answers))
+    if not answers and not unanswered:
+        return None
+
+    sections = ["## AI Q&A"]
+    if "" in answers:
+        sections.append("\n".join(answers.pop("")))
+    sections.extend(f"### {question}\n\n" + "\n".join(text) for question, text in answers.items())
+    if unanswered:
+        sections.append(
+            "### Suggested questions\n\n" + "\n".join(f"- {q}" for q in unanswered)
+        )
+    return "\n\n".join(sections)
+
+
+def _get_shared_notes_content(shared_doc: SharedDocument) -> str | None:
+    """Extract notes content from a shared document in the cache.
+
+    Priority:
+    1. notes_markdown - AI-generated notes already in markdown
+    2. last_viewed_panel.content - ProseMirror content
+    """
+    # Try notes_markdown first
+    if shared_doc.notes_markdown and shared_doc.notes_markdown.strip():
+        return shared_doc.notes_markdown
+
+    # Try last_viewed_panel.content (stored as raw dict in cache)
+    if shared_doc.last_viewed_panel:
+        lvp = shared_doc.last_viewed_panel
+        content_data = lvp.get("content")
+        if content_data:
+            # Parse as ProseMirrorDoc and convert to markdown
+            try:
+                pm_doc = ProseMirrorDoc.model_validate(content_data)
+                return to_markdown(pm_doc)
+            except Exception:
+                pass
+
+    return None
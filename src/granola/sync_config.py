@@ -19,6 +19,7 @@ class SyncConfig:
     """Configuration stored in the sync folder."""
 
     excluded_folders: list[str] = field(default_factory=list)
+    included_folders: list[str] = field(default_factory=list)  # allow-list (empty = all)
     updated_at: str = ""  # ISO timestamp
 
     def __post_init__(self):
@@ -43,6 +44,7 @@ def load_sync_config(sync_folder: Path) -> Optional[SyncConfig]:
         data = json.loads(config_path.read_text(encoding="utf-8"))
         return SyncConfig(
             excluded_folders=data.get("excluded_folders", []),
+            included_folders=data.get("included_folders", []),
             updated_at=data.get("updated_at", ""),
         )
     except (json.JSONDecodeError, OSError):
@@ -144,7 +146,10 @@ def get_effective_exclusions(
         local_excluded, local_updated, sync_config
     )
 
-    # Create the config to save back
-    result_config = SyncConfig(excluded_folders=merged_excluded)
+    # Create the config to save back (the allow-list has no local copy to merge)
+    result_config = SyncConfig(
+        excluded_folders=merged_excluded,
+        included_folders=sync_config.included_folders if sync_config else [],
+    )
 
     return merged_excluded, result_config
@@ -0,0 +1,12 @@
+"""Task manager support for Granola CLI: action items as Todoist or Taskwarrior tasks."""
+
+from granola.tasks.client import Task, TaskError, TaskwarriorClient, TodoistClient
+from granola.tasks.ledger import TaskLedger
+
+__all__ = [
+    "Task",
+    "TaskError",
+    "TaskLedger",
+    "TaskwarriorClient",
+    "TodoistClient",
+]
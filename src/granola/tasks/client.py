@@ -0,0 +1,169 @@
+"""Task manager clients: Todoist's REST API and the Taskwarrior CLI."""
+
+import logging
+import re
+import ssl
+import subprocess
+from dataclasses import dataclass, field
+from typing import Any
+
+import certifi
+import httpx
+
+TODOIST_API_URL = "https://api.todoist.com/api/v1"
+TODOIST_TIMEOUT = 30  # seconds
+
+# Tag every created task carries, so they can be filtered in the task manager
+GRANOLA_TAG = "granola"
+
+# "Created task 3f2a...-..." as printed by `task add` with rc.verbose=new-uuid
+_NEW_UUID = re.compile(r"Created task ([0-9a-f-]{36})")
+
+
+class TaskError(Exception):
+    """Raised when a task can't be created."""
+
+
+@dataclass
+class Task:
+    """A task to create from an action item."""
+
+    content: str
+    tags: list[str] = field(default_factory=list)  # e.g. the meeting title and date
+    description: str = ""
+
+
+def _get_ssl_context() -> ssl.SSLContext:
+    """Create an SSL context using certifi's CA bundle."""
+    return ssl.create_default_context(cafile=certifi.where())
+
+
+class TodoistClient:
+    """Client creating tasks through the Todoist API."""
+
+    def __init__(
+        self,
+        token: str,
+        project_id: str | None = None,
+        logger: logging.Logger | None = None,
+    ):
+        """Initialize the client.
+
+        Args:
+            token: Todoist API token (Settings > Integrations > Developer).
+            project_id: Project tasks are added to (default: the Inbox).
+            logger: Optional logger for debug output.
+        """
+        self.project_id = project_id
+        self.logger = logger or logging.getLogger(__name__)
+        self._client = httpx.Client(
+            base_url=TODOIST_API_URL,
+            timeout=TODOIST_TIMEOUT,
+            verify=_get_ssl_context(),
+            headers={"Authorization": f"Bearer {token}"},
+        )
+
+    def close(self) -> None:
+        """Close the underlying HTTP client."""
+        self._client.close()
+
+    def add(self, task: Task) -> str:
+        """Create a task, labelled with its tags; returns its Todoist ID."""
+        payload: dict[str, Any] = {
+            "content": task.content,
+            "description": task.description,
+            "labels": [GRANOLA_TAG, *task.tags],
+        }
+        if self.project_id:
+            payload["project_id"] = self.project_id
+
+        try:
+            response = self._client.post("/tasks", json=payload)
+            response.raise_for_status()
+            task_id = str(response.json()["id"])
+        except httpx.HTTPStatusError as e:
+            body_preview = e.response.text[:200] if e.response.text else ""
+            raise TaskError(
+                f"Todoist request failed: status={e.response.status_code}, body={body_preview}"
+            ) from e
+        except httpx.RequestError as e:
+            raise TaskError(f"Todoist request failed: {e}") from e
+        except (ValueError, KeyError, TypeError) as e:
+            raise TaskError(f"Unexpected Todoist response: {e}") from e
+
+        self.logger.debug(f"Created Todoist task {task_id}: {task.content}")
+        return task_id
+
+
+class TaskwarriorClient:
+    """Client creating tasks with the Taskwarrior command line (`task add`)."""
+
+    def __init__(
+        self,
+        project: str | None = None,
+        command: str = "task",
+        logger: logging.Logger | None = None,
+    ):
+        """Initialize the client.
+
+        Args:
+            project: Project tasks are added to, if any.
+            command: The Taskwarrior executable.
+            logger: Optional logger for debug output.
+        """
+        self.project = project
+        self.command = command
+        self.logger = logger or logging.getLogger(__name__)
+
+    def close(self) -> None:
+        """Nothing to release; here so both clients can be used alike."""
+
+    def add(self, task: Task) -> str:
+        """Create a task with its tags and annotated with its description.
+
+        Taskwarrior tags can't contain spaces or start with a digit, so each tag
+        is turned into a slug (see taskwarrior_tag).
+
+        Returns:
+            The new task's UUID.
+        """
+        tags = [taskwarrior_tag(tag) for tag in (GRANOLA_TAG, *task.tags)]
+        modifications = [f"+{tag}" for tag in tags if tag]
+        if self.project:
+            modifications.insert(0, f"project:{self.project}")
+        # Everything after "--" is description, even if it looks like a modification
+        output = self._run(["add", *modifications, "--", task.content])
+        match = _NEW_UUID.search(output)
+        if not match:
+            raise TaskError(f"Unexpected output from task add: {output.strip()}")
+        uuid = match.group(1)
+        if task.description:
+            self._run([uuid, "annotate", "--", task.description])
+        self.logger.debug(f"Created Taskwarrior task {uuid}: {task.content}")
+        return uuid
+
+    def _run(self, args: list[str]) -> str:
+        """Run a Taskwarrior command non-interactively and return its output."""
+        try:
+            result = subprocess.run(
+                [self.command, "rc.confirmation=off", "rc.verbose=new-uuid", *args],
+                capture_output=True,
+                text=True,
+            )
+        except OSError as e:
+            raise TaskError(f"Failed to run {self.command}: {e}") from e
+        if result.returncode != 0:
+            raise TaskError(result.stderr.strip() or f"exit code {result.returncode}")
+        return result.stdout
+
+
+def taskwarrior_tag(name: str) -> str:
+    """Return a Taskwarrior tag for a name ("Q3 planning" -> "q3-planning").
+
+    Tags must start with a letter, so dates and other names starting with a
+    digit or symbol get an "m" prefix ("2024-06-12" -> "m2024-06-12").
+    """
+    tag = re.sub(r"[^\w-]+", "-", name.lower()).strip("-_")
+    if tag and not tag[0].isalpha():
+        tag = f"m{tag}"
+    return tag
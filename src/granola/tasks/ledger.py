@@ -0,0 +1,80 @@
+"""Record of the tasks already created from action items.
+
+An action item is keyed by its meeting and its text, so re-running the command
+(or re-exporting the meeting) doesn't create the same task again, even after
+the task was completed or deleted in the task manager.
+"""
+
+import hashlib
+import json
+from pathlib import Path
+
+from granola.config.defaults import config_dir
+
+
+def get_default_ledger_path() -> Path:
+    """Return the default ledger path (~/.config/granola/tasks.json)."""
+    return config_dir() / "tasks.json"
+
+
+def item_key(doc_id: str, text: str) -> str:
+    """Return the key of an action item; case and spacing changes keep the key."""
+    normalized = " ".join(text.casefold().split())
+    return hashlib.sha1(f"{doc_id}\x1f{normalized}".encode("utf-8")).hexdigest()[:20]
+
+
+class TaskLedger:
+    """Map of task manager -> action item key -> ID of the task created for it."""
+
+    def __init__(self, path: Path, services: dict[str, dict[str, str]] | None = None):
+        """Initialize the ledger.
+
+        Args:
+            path: File the ledger is stored in.
+            services: Existing entries (task manager -> item key -> task ID).
+        """
+        self.path = path
+        self.services = services or {}
+
+    @classmethod
+    def load(cls, path: Path) -> "TaskLedger":
+        """Load the ledger from a file (empty if missing or invalid)."""
+        try:
+            data = json.loads(path.read_text(encoding="utf-8"))
+            services = data.get("services", {})
+            if not isinstance(services, dict):
+                services = {}
+        except (json.JSONDecodeError, OSError, AttributeError):
+            services = {}
+        return cls(
+            path,
+            {
+                str(service): {str(k): str(v) for k, v in tasks.items()}
+                for service, tasks in services.items()
+                if isinstance(tasks, dict)
+            },
+        )
+
+    def save(self) -> bool:
+        """Write the ledger to its file.
+
+        Returns:
+            True if saved successfully, False otherwise.
+        """
+        try:
+            self.path.parent.mkdir(parents=True, exist_ok=True)
+            self.path.write_text(
+                json.dumps({"version": 1, "services": self.services}, indent=2, sort_keys=True),
+                encoding="utf-8",
+            )
+            return True
+        except OSError:
+            return False
+
+    def created(self, service: str, key: str) -> bool:
+        """Return True if a task was already created for an item in a task manager."""
+        return key in self.services.get(service, {})
+
+    def record(self, service: str, key: str, task_id: str) -> None:
+        """Record the task created for an item."""
+        self.services.setdefault(service, {})[key] = task_id
@@ -1,6 +1,19 @@
 """Utility functions for Granola."""
 
 from granola.utils.paths import resolve_path
-from granola.utils.filename import sanitize_filename, make_unique
+from granola.utils.filename import sanitize_filename, make_unique, slugify, zettel_id
+from granola.utils.dates import parse_timestamp
+from granola.utils.timing import Timings
+from granola.utils.selection import page, sort_documents
 
-__all__ = ["resolve_path", "sanitize_filename", "make_unique"]
+__all__ = [
+    "resolve_path",
+    "sanitize_filename",
+    "make_unique",
+    "slugify",
+    "zettel_id",
+    "parse_timestamp",
+    "Timings",
+    "page",
+    "sort_documents",
+]
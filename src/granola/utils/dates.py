@@ -0,0 +1,233 @@
+"""Timestamp parsing utilities."""
+
+import locale
+import re
+from dataclasses import dataclass
+from datetime import datetime, timedelta, timezone
+from typing import Any, Optional
+
+# Days in each unit of an age (see parse_age)
+_AGE_UNIT_DAYS = {"d": 1, "w": 7, "m": 30, "y": 365}
+
+# Go layout elements and their strftime equivalents, longest first (see to_strftime)
+_GO_LAYOUT = [
+    ("January", "%B"),
+    ("Monday", "%A"),
+    ("-07:00", "%z"),
+    ("-0700", "%z"),
+    ("2006", "%Y"),
+    ("Jan", "%b"),
+    ("Mon", "%a"),
+    ("MST", "%Z"),
+    ("01", "%m"),
+    ("02", "%d"),
+    ("06", "%y"),
+    ("15", "%H"),
+    ("03", "%I"),
+    ("04", "%M"),
+    ("05", "%S"),
+    ("PM", "%p"),
+    # A year after an underscore, not the space-padded day
+    ("_2006", "_%Y"),
+    # Space-padded and unpadded elements; strftime has no portable directives for
+    # these, so they come out zero-padded. They only count on their own (see
+    # _GO_UNPADDED)
+    ("_2", "%d"),
+    ("1", "%m"),
+    ("2", "%d"),
+    ("3", "%I"),
+    ("4", "%M"),
+    ("5", "%S"),
+]
+
+# Elements that are only converted when no letter or digit touches them, so digits
+# in literal text (Q1, v2) are kept
+_GO_UNPADDED = {"_2", "1", "2", "3", "4", "5"}
+
+# Characters a date in a file name must not contain
+_FILENAME_INVALID = re.compile(r'[<>:"/\\|?*\x00-\x1f]')
+
+
+@dataclass(frozen=True)
+class DateFormats:
+    """strftime formats dates and times are shown in ([dates] in the config file)."""
+
+    # Created and updated times in headers and notes frontmatter, in local time;
+    # None keeps the API's ISO 8601 timestamps
+    timestamp: Optional[str] = None
+    # Times of transcript lines
+    time: str = "%H:%M:%S"
+    # Date file names start with
+    filename: str = "%Y-%m-%d"
+
+
+# Formats in use, set from the config file when the CLI starts
+_date_formats = DateFormats()
+
+
+def date_formats() -> DateFormats:
+    """Return the date formats in use."""
+    return _date_formats
+
+
+def set_date_formats(formats: DateFormats) -> None:
+    """Set the date formats used from now on (e.g. by embedding programs)."""
+    global _date_formats
+    _date_formats = formats
+
+
+def load_date_formats(config: dict[str, Any]) -> DateFormats:
+    """Return the date formats of a [dates] config table, and apply its locale.
+
+    Formats are strftime formats (%d %B %Y) or Go layouts (02 January 2006). The
+    locale (e.g. "de_DE.UTF-8", or "" for the system's) names months and days.
+
+    Raises:
+        ValueError: If a format or the locale is invalid.
+    """
+    formats: dict[str, str] = {}
+    for key in ("timestamp", "time", "filename"):
+        value = config.get(key)
+        if value is None:
+            continue
+        if not isinstance(value, str) or not value.strip():
+            raise ValueError(f"{key} in [dates] must be a date format, e.g. \"%Y-%m-%d\"")
+        formats[key] = to_strftime(value)
+
+    if "locale" in config:
+        try:
+            locale.setlocale(locale.LC_TIME, str(config["locale"]))
+        except locale.Error as e:
+            raise ValueError(f"Unknown locale in [dates]: {config['locale']!r} ({e})") from e
+
+    sample = datetime(2024, 1, 2, 15, 4, 5, tzinfo=timezone.utc).strftime(
+        formats.get("filename", DateFormats.filename)
+    )
+    if not sample.strip() or _FILENAME_INVALID.search(sample):
+        raise ValueError(
+            f"filename in [dates] must give a date usable in file names, not {sample!r}"
+        )
+    return DateFormats(**formats)
+
+
+def to_strftime(layout: str) -> str:
+    """Return a date format as a strftime format, converting it if it's a Go layout.
+
+    Formats containing % are taken to be strftime formats already. Go's unpadded
+    and space-padded elements (3:04, Jan 2, Jan _2) come out zero-padded (03:04,
+    Jan 02). A bare digit next to a letter or another digit is literal text, not an
+    element:
+
+        "02 Jan 2006 15:04" -> "%d %b %Y %H:%M"
+        "Jan 2, 3:04PM"     -> "%b %d, %I:%M%p"
+        "Q1 2006"           -> "Q1 %Y"
+        "v2-2006-01-02"     -> "v2-%Y-%m-%d"
+        "Jan2"              -> "%b2" (write "Jan 2" or "Jan02")
+
+    For other literal digits, use a strftime format ("Week 1 of %Y").
+    """
+    if "%" in layout:
+        return layout
+    result = ""
+    i = 0
+    while i < len(layout):
+        for element, directive in _GO_LAYOUT:
+            end = i + len(element)
+            if not layout.startswith(element, i):
+                continue
+            if element in _GO_UNPADDED and (
+                (i > 0 and layout[i - 1].isalnum()) or (end < len(layout) and layout[end].isalnum())
+            ):
+                continue
+            result += directive
+            i = end
+            break
+        else:
+            result += layout[i]
+            i += 1
+    return result
+
+
+def format_timestamp(value: str) -> str:
+    """Format an ISO timestamp for a header, in local time, per the timestamp format.
+
+    Returned unchanged without a timestamp format, or if it isn't a valid timestamp.
+    """
+    fmt = _date_formats.timestamp
+    parsed = parse_timestamp(value) if fmt else None
+    return parsed.astimezone().strftime(fmt) if parsed and fmt else value
+
+
+def format_time(value: datetime) -> str:
+    """Format the time of a transcript line per the time format."""
+    return value.strftime(_date_formats.time)
+
+
+def parse_timestamp(value: Optional[str]) -> Optional[datetime]:
+    """Parse an ISO 8601 timestamp into a timezone-aware datetime.
+
+    Naive timestamps are assumed to be UTC.
+
+    Args:
+        value: Timestamp string (e.g. "2025-01-15T14:00:00.000Z").
+
+    Returns:
+        Parsed datetime, or None if the value is empty or invalid.
+    """
+    if not value:
+        return None
+
+    try:
+        dt = datetime.fromisoformat(value.strip().replace("Z", "+00:00"))
+    except ValueError:
+        return None
+
+    if dt.tzinfo is None:
+        dt = dt.replace(tzinfo=timezone.utc)
+    return dt
+
+
+def latest_timestamp(*values: Optional[str]) -> Optional[str]:
+    """Return the latest of some ISO 8601 timestamps, as given.
+
+    Empty and invalid timestamps are ignored; None is returned if all are.
+    """
+    parsed = [(dt, value) for value in values if value and (dt := parse_timestamp(value))]
+    return max(parsed, key=lambda entry: entry[0])[1] if parsed else None
+
+
+def parse_period(value: str) -> Optional[tuple[datetime, datetime]]:
+    """Parse a year, month or day (2024, 2024-01, 2024-01-15) into a UTC time range.
+
+    Returns:
+        (start, end) of the period, end exclusive, or None if the value is invalid.
+    """
+    parts = value.strip().split("-")
+    try:
+        numbers = [int(part) for part in parts]
+        if len(numbers) == 1:
+            start = datetime(numbers[0], 1, 1, tzinfo=timezone.utc)
+            return start, start.replace(year=start.year + 1)
+        if len(numbers) == 2:
+            start = datetime(numbers[0], numbers[1], 1, tzinfo=timezone.utc)
+            if start.month == 12:
+                return start, start.replace(year=start.year + 1, month=1)
+            return start, start.replace(month=start.month + 1)
+        if len(numbers) == 3:
+            start = datetime(numbers[0], numbers[1], numbers[2], tzinfo=timezone.utc)
+            return start, start + timedelta(days=1)
+    except ValueError:
+        return None
+    return None
+
+
+def parse_age(value: str) -> Optional[timedelta]:
+    """Parse an age such as 90d, 2w, 6m or 1y (a month is 30 days, a year 365).
+
+    Returns:
+        The age, or None if the value is invalid.
+    """
+    match = re.fullmatch(r"\s*(\d+)\s*([dwmy])\s*", value.lower())
+    if not match:
+        return None
+    return timedelta(days=int(match.group(1)) * _AGE_UNIT_DAYS[match.group(2)])
@@ -0,0 +1,67 @@
+"""Finding duplicate documents: one meeting with notes taken more than once (--merge).
+
+Granola starts a new document each time notes are opened from a calendar event
+or a recording is started, so a meeting can end up with several documents.
+"""
+
+import re
+from datetime import datetime, timedelta
+from typing import Callable, Iterable, TypeVar
+
+from granola.utils.dates import parse_timestamp
+
+T = TypeVar("T")
+
+# Documents of the same title created this close together are taken to be one meeting
+DUPLICATE_WINDOW = timedelta(minutes=30)
+
+
+def duplicate_groups(
+    items: Iterable[T],
+    title: Callable[[T], str],
+    created_at: Callable[[T], str],
+    event_uid: Callable[[T], str | None] = lambda item: None,
+    window: timedelta = DUPLICATE_WINDOW,
+) -> list[list[T]]:
+    """Group documents that are duplicates of each other.
+
+    Documents are duplicates if they have the same title (ignoring case and
+    spacing), were created within window of the first of them, and aren't for
+    different calendar events. Untitled documents and documents without a valid
+    created time are never duplicates.
+
+    Args:
+        items: Items to group.
+        title: Returns an item's title.
+        created_at: Returns an item's ISO created time.
+        event_uid: Returns the UID of an item's calendar event, if any.
+        window: How far apart duplicates can be created.
+
+    Returns:
+        Groups of two or more items, each oldest first, in order of their first item.
+    """
+    by_title: dict[str, list[tuple[T, datetime]]] = {}
+    for item in items:
+        key = re.sub(r"\s+", " ", title(item) or "").strip().casefold()
+        created = parse_timestamp(created_at(item))
+        if key and created is not None:
+            by_title.setdefault(key, []).append((item, created))
+
+    groups: list[tuple[datetime, list[T]]] = []
+    for entries in by_title.values():
+        entries.sort(key=lambda entry: entry[1])
+        group: list[T] = []
+        first = entries[0][1]
+        uid: str | None = None
+        for item, created in entries:
+            item_uid = event_uid(item)
+            if group and created - first <= window and not (uid and item_uid and uid != item_uid):
+                group.append(item)
+                uid = uid or item_uid
+                continue
+            if len(group) > 1:
+                groups.append((first, group))
+            group, first, uid = [item], created, item_uid
+        if len(group) > 1:
+            groups.append((first, group))
+    return [group for _, group in sorted(groups, key=lambda entry: entry[0])]
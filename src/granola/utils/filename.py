@@ -1,6 +1,7 @@
 """Filename sanitization utilities."""
 
 import re
+from datetime import datetime
 from typing import Dict
 
 # Characters invalid in filenames on Windows/macOS/Linux
@@ -54,3 +55,35 @@ def make_unique(filename: str, used: Dict[str, int]) -> str:
     if count > 0:
         return f"{filename}_{count + 1}"
     return filename
+
+
+def slugify(name: str, fallback: str = "untitled", max_length: int = 70) -> str:
+    """Convert a title into a lowercase, hyphen-separated URL slug.
+
+    Args:
+        name: The title to convert.
+        fallback: Fallback slug if result is empty.
+        max_length: Maximum slug length.
+
+    Returns:
+        Slug containing only lowercase letters, digits, and hyphens.
+    """
+    slug = re.sub(r"[^\w\s-]", "", name.lower())
+    slug = re.sub(r"[\s_-]+", "-", slug).strip("-")
+
+    if len(slug) > max_length:
+        slug = slug[:max_length].rstrip("-")
+
+    return slug or fallback
+
+
+def zettel_id(dt: datetime) -> str:
+    """Return a timestamp-based Zettelkasten ID (YYYYMMDDHHMM).
+
+    Args:
+        dt: The timestamp to derive the ID from (typically created_at).
+
+    Returns:
+        12-digit zettel ID, e.g. "202406121530".
+    """
+    return dt.strftime("%Y%m%d%H%M")
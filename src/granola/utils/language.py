@@ -0,0 +1,62 @@
+"""Guess the language a meeting was held in, from its most common words."""
+
+import re
+
+# Frequent short words of each language (ISO 639-1 code). Words shared by two
+# languages count for both; the language with the most hits wins.
+STOPWORDS: dict[str, frozenset[str]] = {
+    "en": frozenset(
+        "the and of to is in that it for was on are with as be this have you not but "
+        "they we at what so if or can will from there about would our just do".split()
+    ),
+    "es": frozenset(
+        "el la los las de que y en un una es por con para del se no lo al como más "
+        "pero sus le ya este está son también hay muy porque cuando nosotros".split()
+    ),
+    "fr": frozenset(
+        "le la les de des et est un une du en que qui dans pour pas sur au avec ce "
+        "il nous vous sont mais ou cette été être aussi très faire".split()
+    ),
+    "de": frozenset(
+        "der die das und ist nicht ein eine zu den mit sich des auf für im dem von "
+        "wir ich sie es auch als noch aber wie oder wenn haben werden".split()
+    ),
+    "pt": frozenset(
+        "o a os as de que e do da em um uma para com não é por mais dos das como "
+        "mas ao ele isso está são também muito quando nós você".split()
+    ),
+    "it": frozenset(
+        "il lo la gli le di che e è un una per non con del della sono come ma si "
+        "anche questo più nel alla ci abbiamo essere molto quando".split()
+    ),
+    "nl": frozenset(
+        "de het een en van is dat op te in niet zijn met voor er maar ook als aan "
+        "wij ik je hebben dit wat bij nog naar wordt worden".split()
+    ),
+    "sv": frozenset(
+        "och att det som en på är av för med inte har till den om vi jag de så "
+        "kan ett men eller från också vara när där".split()
+    ),
+}
+
+_WORD = re.compile(r"[^\W\d_]+")
+
+# Enough text to tell languages apart; long transcripts aren't read in full
+_MAX_WORDS = 2000
+
+# Fewer stopword hits than this are too little to go on
+_MIN_HITS = 5
+
+
+def detect_language(text: str | None) -> str | None:
+    """Return the ISO 639-1 code of the dominant language of a text, if recognized.
+
+    Only the languages in STOPWORDS are recognized. Returns None for texts too
+    short (or in another language) to tell.
+    """
+    if not text:
+        return None
+    words = [w.lower() for w in _WORD.findall(text[: _MAX_WORDS * 10])][:_MAX_WORDS]
+    hits = {lang: sum(w in stopwords for w in words) for lang, stopwords in STOPWORDS.items()}
+    lang, count = max(hits.items(), key=lambda item: item[1])
+    return lang if count >= _MIN_HITS else None
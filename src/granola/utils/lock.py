@@ -0,0 +1,122 @@
+"""Advisory lock that keeps concurrent exports out of the same output folder."""
+
+import os
+import sys
+import time
+from pathlib import Path
+from typing import IO, Optional
+
+# Lock file name stored in the output folder root
+LOCK_FILENAME = ".granola.lock"
+
+# Windows locks a byte range rather than the file; the byte locked is past the PID
+# written at the start, so waiting exports can still read who holds the lock
+WINDOWS_LOCK_OFFSET = 1 << 20
+
+# How often a waiting export retries the lock on Windows, which can't block on it
+WINDOWS_RETRY_INTERVAL = 0.5  # seconds
+
+
+class LockError(Exception):
+    """Raised when another process holds the export lock."""
+
+
+class ExportLock:
+    """Exclusive advisory lock on an output directory.
+
+    The lock is held with flock(2) (msvcrt.locking on Windows), so it is released
+    automatically if the process dies; a stale lock file left behind never blocks
+    later runs.
+    """
+
+    def __init__(self, directory: Path):
+        """Initialize the lock.
+
+        Args:
+            directory: Output directory to lock.
+        """
+        self.path = directory / LOCK_FILENAME
+        self._file: Optional[IO[str]] = None
+
+    def acquire(self, wait: bool = False) -> None:
+        """Acquire the lock.
+
+        Args:
+            wait: Block until the lock is free instead of failing immediately.
+
+        Raises:
+            LockError: If the lock is held by another process and wait is False.
+        """
+        self.path.parent.mkdir(parents=True, exist_ok=True)
+        f = open(self.path, "a+", encoding="utf-8")
+        try:
+            _lock(f, wait)
+        except BlockingIOError:
+            f.seek(0)
+            holder = f.read().strip()
+            f.close()
+            owner = f" (pid {holder})" if holder else ""
+            raise LockError(
+                f"Another export{owner} is already running on {self.path.parent}"
+            ) from None
+        except OSError:
+            f.close()
+            raise
+
+        # Record our PID for the error message of the next contender
+        f.seek(0)
+        f.truncate()
+        f.write(str(os.getpid()))
+        f.flush()
+        self._file = f
+
+    def release(self) -> None:
+        """Release the lock if held."""
+        if self._file is None:
+            return
+        try:
+            self._file.seek(0)
+            self._file.truncate()
+            _unlock(self._file)
+        finally:
+            self._file.close()
+            self._file = None
+
+    def __enter__(self) -> "ExportLock":
+        self.acquire()
+        return self
+
+    def __exit__(self, *exc_info: object) -> None:
+        self.release()
+
+
+if sys.platform == "win32":
+    import msvcrt
+
+    def _lock(f: IO[str], wait: bool) -> None:
+        """Lock an open lock file, raising BlockingIOError if it's held (unless waiting)."""
+        f.seek(WINDOWS_LOCK_OFFSET)
+        while True:
+            try:
+                msvcrt.locking(f.fileno(), msvcrt.LK_NBLCK, 1)
+                return
+            except OSError:
+                if not wait:
+                    raise BlockingIOError from None
+            time.sleep(WINDOWS_RETRY_INTERVAL)
+
+    def _unlock(f: IO[str]) -> None:
+        """Unlock a lock file locked by _lock()."""
+        f.seek(WINDOWS_LOCK_OFFSET)
+        msvcrt.locking(f.fileno(), msvcrt.LK_UNLCK, 1)
+
+else:
+    import fcntl
+
+    def _lock(f: IO[str], wait: bool) -> None:
+        """Lock an open lock file, raising BlockingIOError if it's held (unless waiting)."""
+        fcntl.flock(f, fcntl.LOCK_EX if wait else fcntl.LOCK_EX | fcntl.LOCK_NB)
+
+    def _unlock(f: IO[str]) -> None:
+        """Unlock a lock file locked by _lock()."""
+        fcntl.flock(f, fcntl.LOCK_UN)
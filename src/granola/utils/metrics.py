@@ -0,0 +1,205 @@
+"""Export metrics in the Prometheus text format, for monitoring scheduled exports.
+
+Metrics describe the last run. They are written for node_exporter's textfile
+collector (--metrics-file) or pushed to a Prometheus Pushgateway (--metrics-push),
+so an alert on granola_export_last_success_timestamp_seconds catches exports
+that have stopped working.
+"""
+
+import os
+import re
+import time
+from dataclasses import dataclass, field
+from pathlib import Path
+from urllib.parse import urlsplit
+
+import httpx
+
+# Upper bounds (seconds) of the API request latency histogram buckets
+LATENCY_BUCKETS = (0.1, 0.25, 0.5, 1.0, 2.5, 5.0, 10.0, 30.0, 60.0)
+
+# Pushgateway job the metrics are grouped under
+PUSH_JOB = "granola_export"
+
+PUSH_TIMEOUT = 10  # seconds
+
+LAST_SUCCESS = "granola_export_last_success_timestamp_seconds"
+
+
+@dataclass
+class Histogram:
+    """Latency observations, counted into LATENCY_BUCKETS."""
+
+    counts: list[int] = field(default_factory=lambda: [0] * len(LATENCY_BUCKETS))
+    total: float = 0.0
+    count: int = 0
+
+    def observe(self, seconds: float) -> None:
+        """Count one observation."""
+        for i, bound in enumerate(LATENCY_BUCKETS):
+            if seconds <= bound:
+                self.counts[i] += 1
+        self.total += seconds
+        self.count += 1
+
+
+@dataclass
+class ExportMetrics:
+    """What one export run did, as it goes."""
+
+    # Wall-clock start of the run
+    started: float = field(default_factory=time.time)
+    # Whether the run finished; runs that exit early count as failed
+    finished: bool = False
+    # Documents fetched from the API (own and shared)
+    documents_fetched: int = 0
+    # Files per sync action (added, updated, moved, deleted, skipped)
+    files: dict[str, int] = field(default_factory=dict)
+    # Documents that failed to render or write
+    errors: int = 0
+    # API request latencies per endpoint path
+    latencies: dict[str, Histogram] = field(default_factory=dict)
+
+    @property
+    def success(self) -> bool:
+        """Whether the run finished with every document exported."""
+        return self.finished and not self.errors
+
+    def observe_request(self, url: str, seconds: float, status_code: int) -> None:
+        """Record an API request's latency (a GranolaClient on_response hook)."""
+        path = urlsplit(url).path
+        self.latencies.setdefault(path, Histogram()).observe(seconds)
+
+    def render(self, last_success: float | None = None) -> str:
+        """Return the metrics in the Prometheus text format.
+
+        Args:
+            last_success: When the last successful run finished, if not this run.
+                Left out if neither is known.
+        """
+        now = time.time()
+        if self.success:
+            last_success = now
+        lines: list[str] = []
+
+        def metric(name: str, kind: str, text: str, samples: list[tuple[str, float]]) -> None:
+            lines.append(f"# HELP {name} {text}")
+            lines.append(f"# TYPE {name} {kind}")
+            lines.extend(f"{name}{labels} {_number(value)}" for labels, value in samples)
+
+        metric(
+            "granola_export_last_run_timestamp_seconds",
+            "gauge",
+            "When the last export finished.",
+            [("", now)],
+        )
+        if last_success is not None:
+            metric(
+                LAST_SUCCESS,
+                "gauge",
+                "When the last export that exported every document finished.",
+                [("", last_success)],
+            )
+        metric(
+            "granola_export_last_run_success",
+            "gauge",
+            "Whether the last export finished and exported every document.",
+            [("", int(self.success))],
+        )
+        metric(
+            "granola_export_last_run_duration_seconds",
+            "gauge",
+            "How long the last export took.",
+            [("", now - self.started)],
+        )
+        metric(
+            "granola_export_documents_fetched",
+            "gauge",
+            "Documents fetched from the Granola API by the last export.",
+            [("", self.documents_fetched)],
+        )
+        metric(
+            "granola_export_files",
+            "gauge",
+            "Files the last export added, updated, moved, deleted or skipped.",
+            [(f'{{action="{action}"}}', count) for action, count in self.files.items()],
+        )
+        metric(
+            "granola_export_errors",
+            "gauge",
+            "Documents the last export failed to render or write.",
+            [("", self.errors)],
+        )
+
+        samples: list[tuple[str, float]] = []
+        for path, histogram in sorted(self.latencies.items()):
+            endpoint = _label(path)
+            for bound, count in zip(LATENCY_BUCKETS, histogram.counts):
+                samples.append((f'_bucket{{endpoint="{endpoint}",le="{bound}"}}', count))
+            samples.append((f'_bucket{{endpoint="{endpoint}",le="+Inf"}}', histogram.count))
+            samples.append((f'_sum{{endpoint="{endpoint}"}}', histogram.total))
+            samples.append((f'_count{{endpoint="{endpoint}"}}', histogram.count))
+        metric(
+            "granola_api_request_duration_seconds",
+            "histogram",
+            "Latency of the last export's Granola API requests.",
+            samples,
+        )
+        return "\n".join(lines) + "\n"
+
+
+def write_textfile(metrics: ExportMetrics, path: Path) -> None:
+    """Write metrics to a file for node_exporter's textfile collector.
+
+    The file is replaced atomically, so the collector never reads half of it. A
+    failed run keeps the last successful run's time from the previous file.
+
+    Raises:
+        OSError: If the file can't be written.
+    """
+    text = metrics.render(last_success=_read_last_success(path))
+    path.parent.mkdir(parents=True, exist_ok=True)
+    tmp_path = path.with_name(f".{path.name}.tmp")
+    tmp_path.write_text(text)
+    os.replace(tmp_path, path)
+
+
+def push(metrics: ExportMetrics, url: str) -> None:
+    """Push metrics to a Prometheus Pushgateway.
+
+    Metrics are POSTed, so a failed run leaves the last success time pushed
+    before it in place.
+
+    Raises:
+        httpx.HTTPError: If the push fails.
+    """
+    response = httpx.post(
+        f"{url.rstrip('/')}/metrics/job/{PUSH_JOB}",
+        content=metrics.render(),
+        headers={"Content-Type": "text/plain; version=0.0.4"},
+        timeout=PUSH_TIMEOUT,
+    )
+    response.raise_for_status()
+
+
+def _read_last_success(path: Path) -> float | None:
+    """Return the last success time in a metrics file written before, if any."""
+    try:
+        text = path.read_text()
+    except OSError:
+        return None
+    match = re.search(rf"^{LAST_SUCCESS} (\S+)$", text, re.MULTILINE)
+    try:
+        return float(match.group(1)) if match else None
+    except ValueError:
+        return None
+
+
+def _number(value: float) -> str:
+    """Format a sample value: integers without a fraction."""
+    return str(int(value)) if float(value).is_integer() else f"{value:.3f}"
+
+
+def _label(value: str) -> str:
+    """Escape a label value."""
+    return value.replace("\\", "\\\\").replace('"', '\\"').replace("\n", "\\n")
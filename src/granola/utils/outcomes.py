@@ -0,0 +1,164 @@
+"""Decisions and action items written down in meeting notes.
+
+Notes rarely mark them up, so they are found the way people write them: as list
+items under a heading such as "Decisions" or "Next steps", as lines starting
+with "Decision:" or "TODO:", or as Markdown task checkboxes ("- [ ] ...").
+Lines carrying a marker of the user's own (e.g. "JIRA: ...") are found too.
+"""
+
+import re
+from dataclasses import dataclass, field
+
+# Headings of sections listing decisions and action items (matched case-insensitively)
+_DECISION_HEADING = re.compile(
+    r"^(key\s+)?(decisions?(\s+made)?|decided|agreements?|agreed|outcomes?)$", re.IGNORECASE
+)
+_ACTION_HEADING = re.compile(
+    r"^(action\s+items?|actions|next\s+steps|to-?\s?dos?|follow[-\s]?ups?|tasks)$",
+    re.IGNORECASE,
+)
+
+# Inline markers, e.g. "Decision: ship on Friday" or "TODO: send the deck"
+_DECISION_LINE = re.compile(r"^(?:decision|decided|agreed)\s*[:\-–]\s*(.+)$", re.IGNORECASE)
+_ACTION_LINE = re.compile(
+    r"^(?:action(?:\s+item)?|todo|to-do|follow[-\s]?up)\s*[:\-–]\s*(.+)$", re.IGNORECASE
+)
+
+_HEADING = re.compile(r"^(#{1,6})\s+(.*?)\s*#*\s*$")
+_LIST_ITEM = re.compile(r"^[ \t]*(?:[-*+]|\d+[.)])\s+(.*)$")
+_CHECKBOX = re.compile(r"^\[( |x|X)\]\s+(.*)$")
+_EMPHASIS = re.compile(r"(\*\*|__|\*)(.+?)\1")
+
+
+@dataclass
+class Outcomes:
+    """Decisions and action items found in a meeting's notes."""
+
+    decisions: list[str] = field(default_factory=list)
+    actions: list[str] = field(default_factory=list)  # open action items
+    done: list[str] = field(default_factory=list)  # ticked checkboxes
+
+    def __bool__(self) -> bool:
+        return bool(self.decisions or self.actions or self.done)
+
+
+@dataclass
+class MarkedLine:
+    """A line of the notes matching a marker pattern (e.g. "JIRA: ...")."""
+
+    text: str  # what the marker introduces
+    line: str  # the whole line, without list or emphasis markup
+    section: str | None = None  # heading the line is under, if any
+
+
+def extract_outcomes(notes: str | None) -> Outcomes:
+    """Find the decisions and action items in Markdown notes.
+
+    Args:
+        notes: Markdown notes content.
+
+    Returns:
+        The decisions and action items, in the order they appear, without
+        duplicates. Ticked checkboxes are listed apart, as done action items.
+    """
+    outcomes = Outcomes()
+    if not notes:
+        return outcomes
+
+    # Kind of the section being read ("decision", "action" or None) and its level
+    section: str | None = None
+    section_level = 0
+    for line in notes.splitlines():
+        heading = _HEADING.match(line)
+        if heading:
+            level = len(heading.group(1))
+            title = _plain(heading.group(2)).rstrip(":")
+            kind = _section_kind(title)
+            if kind:
+                section, section_level = kind, level
+            elif section and level <= section_level:
+                section = None
+            continue
+
+        text = line.strip()
+        item = _LIST_ITEM.match(line)
+        if item:
+            text = item.group(1).strip()
+        elif not text:
+            continue
+
+        checkbox = _CHECKBOX.match(text)
+        if checkbox:
+            done = checkbox.group(1) != " "
+            _add(outcomes.done if done else outcomes.actions, checkbox.group(2))
+            continue
+        plain = _plain(text)
+        decision = _DECISION_LINE.match(plain)
+        action = _ACTION_LINE.match(plain)
+        if decision:
+            _add(outcomes.decisions, decision.group(1))
+        elif action:
+            _add(outcomes.actions, action.group(1))
+        elif item and section == "decision":
+            _add(outcomes.decisions, text)
+        elif item and section == "action":
+            _add(outcomes.actions, text)
+        elif not item and _section_kind(plain.rstrip(":")):
+            # A bold "Next steps:" line instead of a heading; ends at the next heading
+            section = _section_kind(plain.rstrip(":"))
+            section_level = 7
+    return outcomes
+
+
+def find_marked_lines(notes: str | None, pattern: re.Pattern[str]) -> list[MarkedLine]:
+    """Find the lines of Markdown notes that match a marker pattern.
+
+    Lines are matched without their list markers and bold or italic markup. The
+    text a line is filed under is the pattern's first group, or else whatever
+    follows the match.
+
+    Args:
+        notes: Markdown notes content.
+        pattern: Pattern to search lines for, e.g. one for lines starting "JIRA:".
+
+    Returns:
+        The matching lines, in the order they appear, without duplicates.
+    """
+    marked: list[MarkedLine] = []
+    section: str | None = None
+    for line in (notes or "").splitlines():
+        heading = _HEADING.match(line)
+        if heading:
+            section = _plain(heading.group(2)) or None
+            continue
+        item = _LIST_ITEM.match(line)
+        plain = _plain(item.group(1) if item else line)
+        match = pattern.search(plain)
+        if not match:
+            continue
+        text = match.group(1) if pattern.groups else plain[match.end() :]
+        text = (text or "").strip()
+        if text and all(m.text != text for m in marked):
+            marked.append(MarkedLine(text, plain, section))
+    return marked
+
+
+def _section_kind(title: str) -> str | None:
+    """Return "decision" or "action" if a heading introduces such a list."""
+    if _DECISION_HEADING.match(title):
+        return "decision"
+    if _ACTION_HEADING.match(title):
+        return "action"
+    return None
+
+
+def _plain(text: str) -> str:
+    """Strip bold and italic markup from a line."""
+    return _EMPHASIS.sub(r"\2", text).strip()
+
+
+def _add(items: list[str], text: str) -> None:
+    """Add an item unless it is empty or already listed."""
+    text = _plain(text)
+    if text and text not in items:
+        items.append(text)
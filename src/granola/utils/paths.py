@@ -1,6 +1,7 @@
 """Path resolution utilities."""
 
 import os
+from datetime import datetime
 from pathlib import Path
 from typing import Optional, Union
 
@@ -28,3 +29,32 @@ def resolve_path(input_path: Optional[Union[str, Path]]) -> Optional[Path]:
     path = Path(path_str).expanduser()
 
     return path.resolve()
+
+
+def split_output_template(output: str) -> tuple[str, Optional[str]]:
+    """Split an output directory into its fixed part and a per-document template.
+
+    The template starts at the first path component with a placeholder (see
+    format_output_template): ~/Notes/Meetings/{year}/{month} splits into
+    ("~/Notes/Meetings", "{year}/{month}"). An output without placeholders has
+    no template.
+    """
+    parts = Path(output).parts
+    for i, part in enumerate(parts):
+        if "{" in part:
+            return str(Path(*parts[:i])) if i else ".", "/".join(parts[i:])
+    return output, None
+
+
+def format_output_template(template: str, when: datetime) -> str:
+    """Fill in an output directory template's {year}, {month} and {day} for a date.
+
+    Raises:
+        ValueError: If the template uses other placeholders or is malformed.
+    """
+    try:
+        return template.format(year=f"{when:%Y}", month=f"{when:%m}", day=f"{when:%d}")
+    except KeyError as e:
+        raise ValueError(f"Unknown placeholder {{{e.args[0]}}} (use {{year}}, {{month}}, {{day}})")
+    except (IndexError, ValueError) as e:
+        raise ValueError(f"Invalid output template '{template}': {e}")
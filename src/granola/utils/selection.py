@@ -0,0 +1,78 @@
+"""Selecting and ordering documents (--match, --sort, --order, --limit, --skip)."""
+
+import re
+from datetime import datetime, timezone
+from typing import Any, Callable, Iterable, TypeVar
+
+from granola.utils.dates import parse_timestamp
+
+T = TypeVar("T")
+
+# Fields documents can be sorted by (--sort) and sort directions (--order)
+SORT_FIELDS = ("created", "updated", "title")
+ORDERS = ("asc", "desc")
+
+_EPOCH = datetime.min.replace(tzinfo=timezone.utc)
+
+
+def default_order(sort: str) -> str:
+    """Return the default direction for a sort field: newest first, or A-Z for titles."""
+    return "asc" if sort == "title" else "desc"
+
+
+def sort_documents(
+    items: Iterable[T],
+    sort: str,
+    order: str | None = None,
+    doc: Callable[[T], Any] = lambda item: item,
+) -> list[T]:
+    """Sort documents by created_at, updated_at, or title.
+
+    Args:
+        items: Items to sort.
+        sort: Field to sort by (one of SORT_FIELDS).
+        order: "asc" or "desc" (default: see default_order).
+        doc: Returns the document of an item (anything with created_at, updated_at
+            and title), for items that wrap one.
+
+    Returns:
+        The sorted items. Ties keep their original order; unparseable times sort
+        as the oldest.
+    """
+    reverse = (order or default_order(sort)) == "desc"
+    return sorted(items, key=lambda item: _sort_value(doc(item), sort), reverse=reverse)
+
+
+def _sort_value(document: Any, sort: str) -> Any:
+    """Return the value a document is sorted by."""
+    if sort == "title":
+        return (document.title or "").casefold()
+    return parse_timestamp(getattr(document, f"{sort}_at")) or _EPOCH
+
+
+def title_selected(
+    title: str | None,
+    match: re.Pattern[str] | None = None,
+    exclude_match: re.Pattern[str] | None = None,
+    skip_untitled: bool = False,
+) -> bool:
+    """Return True if a title passes the --match and --exclude-match patterns.
+
+    Patterns are searched for anywhere in the title (anchor them with ^ and $).
+    With skip_untitled (--skip-untitled), blank titles and Granola's "Untitled"
+    don't pass.
+    """
+    title = title or ""
+    if skip_untitled and title.strip().casefold() in ("", "untitled"):
+        return False
+    if match is not None and not match.search(title):
+        return False
+    if exclude_match is not None and exclude_match.search(title):
+        return False
+    return True
+
+
+def page(items: list[T], limit: int | None, skip: int) -> list[T]:
+    """Return the items after the first skip, at most limit of them (all if None)."""
+    end = skip + limit if limit is not None else None
+    return items[skip:end]
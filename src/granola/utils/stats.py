@@ -0,0 +1,52 @@
+"""Size statistics of a meeting's notes and transcript."""
+
+import math
+import re
+
+from granola.cache.reader import TranscriptSegment
+from granola.utils.dates import parse_timestamp
+
+# A word: letters or digits, with inner apostrophes and hyphens (don't, follow-up)
+_WORD = re.compile(r"\w+(?:['’-]\w+)*")
+
+# Markdown (# Heading) and HTML (<h2>Heading</h2>) headings
+_HEADING = re.compile(
+    r"^ {0,3}#{1,6}(?:[ \t][^\n]*)?$|<h[1-6][^>]*>(?s:.*?)</h[1-6]>", re.MULTILINE
+)
+
+# Typical silent reading speed of prose, in words per minute
+DEFAULT_READING_WPM = 200
+
+
+def word_count(text: str | None) -> int:
+    """Count the words in a text, ignoring Markdown and HTML markup."""
+    if not text:
+        return 0
+    text = re.sub(r"<[^>]+>", " ", text)
+    return len(_WORD.findall(text))
+
+
+def content_word_count(text: str | None) -> int:
+    """Count the words in a text outside its headings.
+
+    Notes of just a template's headings ("## Action Items") and empty list items
+    have no content words, though word_count() counts the headings' words.
+    """
+    if not text:
+        return 0
+    return word_count(_HEADING.sub(" ", text))
+
+
+def transcript_minutes(segments: list[TranscriptSegment]) -> int:
+    """Return how long a transcript runs, in minutes (first to last segment, rounded)."""
+    starts = [parse_timestamp(s.start_timestamp) for s in segments]
+    ends = [parse_timestamp(s.end_timestamp or s.start_timestamp) for s in segments]
+    times = [t for t in (*starts, *ends) if t]
+    if not times:
+        return 0
+    return round((max(times) - min(times)).total_seconds() / 60)
+
+
+def reading_minutes(words: int, wpm: int = DEFAULT_READING_WPM) -> int:
+    """Estimate how long reading a number of words takes, in whole minutes (at least 1)."""
+    return max(1, math.ceil(words / wpm)) if words else 0
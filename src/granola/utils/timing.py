@@ -0,0 +1,38 @@
+"""Per-phase timing instrumentation."""
+
+import time
+from contextlib import contextmanager
+from typing import Iterator
+
+from granola.utils.tracing import span
+
+
+class Timings:
+    """Accumulates wall-clock time spent in named phases.
+
+    Phases can be entered many times (e.g. once per document); their durations
+    are summed. Phases are reported in the order they were first entered. Each
+    time a phase is entered is also a span when tracing (--trace).
+    """
+
+    def __init__(self) -> None:
+        self.phases: dict[str, float] = {}
+
+    @contextmanager
+    def phase(self, name: str) -> Iterator[None]:
+        """Time the enclosed block and add it to a phase."""
+        start = time.perf_counter()
+        try:
+            with span(name):
+                yield
+        finally:
+            self.add(name, time.perf_counter() - start)
+
+    def add(self, name: str, seconds: float) -> None:
+        """Add a duration to a phase."""
+        self.phases[name] = self.phases.get(name, 0.0) + seconds
+
+    def format(self) -> list[str]:
+        """Return one aligned "phase  1.23s" line per phase."""
+        width = max((len(name) for name in self.phases), default=0)
+        return [f"{name:<{width}}  {seconds:7.2f}s" for name, seconds in self.phases.items()]
@@ -0,0 +1,75 @@
+"""Optional OpenTelemetry tracing of a command and its phases (--trace).
+
+Each Timings phase (API fetch, cache parse, render, write, prune) becomes a span
+under one span for the command, exported over OTLP/HTTP to a collector such as
+Jaeger or Grafana Tempo. Needs the tracing extra: pip install 'granola-cli[tracing]'.
+"""
+
+from contextlib import AbstractContextManager, nullcontext
+from typing import Any, Callable
+
+from granola import __version__
+
+# OTLP/HTTP path traces are sent to, added to endpoints given without it
+TRACES_PATH = "/v1/traces"
+
+SERVICE_NAME = "granola"
+
+# Tracer spans are started with; None while tracing is off
+_tracer: Any = None
+
+
+class TracingError(Exception):
+    """Raised when tracing can't be started."""
+
+    pass
+
+
+def start_tracing(endpoint: str) -> Callable[[], None]:
+    """Start sending spans to an OTLP/HTTP endpoint.
+
+    Args:
+        endpoint: Collector URL, e.g. http://localhost:4318 (TRACES_PATH is added
+            if missing).
+
+    Returns:
+        A function that stops tracing and flushes the spans not yet sent.
+
+    Raises:
+        TracingError: If the OpenTelemetry packages aren't installed.
+    """
+    global _tracer
+    try:
+        from opentelemetry.exporter.otlp.proto.http.trace_exporter import OTLPSpanExporter
+        from opentelemetry.sdk.resources import Resource
+        from opentelemetry.sdk.trace import TracerProvider
+        from opentelemetry.sdk.trace.export import BatchSpanProcessor
+    except ImportError as e:
+        raise TracingError(
+            "Tracing needs OpenTelemetry: pip install 'granola-cli[tracing]'"
+        ) from e
+
+    endpoint = endpoint.rstrip("/")
+    if not endpoint.endswith(TRACES_PATH):
+        endpoint += TRACES_PATH
+    resource = Resource.create({"service.name": SERVICE_NAME, "service.version": __version__})
+    provider = TracerProvider(resource=resource)
+    provider.add_span_processor(BatchSpanProcessor(OTLPSpanExporter(endpoint=endpoint)))
+    _tracer = provider.get_tracer("granola")
+
+    def stop() -> None:
+        global _tracer
+        _tracer = None
+        provider.shutdown()
+
+    return stop
+
+
+def span(name: str, **attributes: Any) -> AbstractContextManager[Any]:
+    """Return a context manager that traces the enclosed block as a span.
+
+    Does nothing while tracing is off, so it's cheap to use around any phase.
+    """
+    if _tracer is None:
+        return nullcontext()
+    return _tracer.start_as_current_span(name, attributes=attributes or None)
@@ -1,12 +1,25 @@
 """File writers for Granola exports."""
 
 from granola.writers.file_writer import write_documents, should_update_file
-from granola.writers.sync_writer import SyncWriter, SyncStats, ExportDoc
+from granola.writers.sync_writer import SyncWriter, SyncStats, SyncFailure, ExportDoc
+from granola.writers.feed import FeedEntry, write_atom_feed
+from granola.writers.archive import write_tar_archive, write_zip_archive
+from granola.writers.manifest import Manifest
+from granola.writers.run_state import RunState
+from granola.writers.obsidian import find_obsidian_vault
 
 __all__ = [
     "write_documents",
     "should_update_file",
     "SyncWriter",
     "SyncStats",
+    "SyncFailure",
     "ExportDoc",
+    "FeedEntry",
+    "write_atom_feed",
+    "write_zip_archive",
+    "write_tar_archive",
+    "Manifest",
+    "RunState",
+    "find_obsidian_vault",
 ]
@@ -0,0 +1,155 @@
+"""Apple Notes writer: notes are created and updated with AppleScript (macOS).
+
+Each note ends with its Granola document ID and updated time, so re-running an
+export finds the note a document was written to, updates it only if the
+document changed, and moves it when the document moves to another folder.
+Notes are never deleted.
+"""
+
+import logging
+import shutil
+import subprocess
+from typing import Iterable
+
+from granola.writers.sync_writer import ExportDoc, SyncFailure, SyncStats, folder_ancestry
+
+# Destination format name, and the Notes folder meetings go under by default
+APPLE_NOTES_FORMAT = "apple-notes"
+DEFAULT_NOTES_FOLDER = "Granola"
+
+# Finds the note carrying a document's marker outside Recently Deleted, then
+# updates it (unless it carries the same stamp and force isn't "force"), moves it
+# to the target folder, or creates it there. Folders are created as needed.
+_SCRIPT = """
+on run argv
+    set {folderPath, marker, stamp, noteBody, force} to argv
+    set folderNames to paragraphs of folderPath
+    tell application "Notes"
+        set target to missing value
+        repeat with i from 1 to count of folderNames
+            set folderName to item i of folderNames
+            if target is missing value then
+                if not (exists folder folderName) then make new folder with properties {name:folderName}
+                set target to folder folderName
+            else
+                if not (exists folder folderName of target) then
+                    make new folder at target with properties {name:folderName}
+                end if
+                set target to folder folderName of target
+            end if
+        end repeat
+        repeat with found in (notes whose body contains marker)
+            if name of container of found is not "Recently Deleted" then
+                if id of container of found is not id of target then move found to target
+                if force is not "force" and body of found contains stamp then return "skipped"
+                set body of found to noteBody
+                return "updated"
+            end if
+        end repeat
+        make new note at target with properties {body:noteBody}
+        return "added"
+    end tell
+end run
+"""
+
+
+class AppleNotesError(Exception):
+    """Raised when Apple Notes can't be written."""
+
+
+class AppleNotesWriter:
+    """Writes documents as notes in Apple Notes, mirroring the Granola folders.
+
+    Documents go in a subfolder of root_folder per Granola folder (nested like
+    the Granola folders); a document in several folders goes in the first.
+    """
+
+    def __init__(
+        self,
+        root_folder: str = DEFAULT_NOTES_FOLDER,
+        logger: logging.Logger | None = None,
+        folder_parents: dict[str, str] | None = None,
+        osascript: str = "osascript",
+        force: bool = False,
+    ):
+        """Initialize the writer.
+
+        Args:
+            root_folder: Notes folder that meeting folders are created in.
+            logger: Optional logger for debug output.
+            folder_parents: Map of folder name -> parent folder name.
+            osascript: Name or path of the osascript executable.
+            force: Update every note, even those of documents not updated since.
+
+        Raises:
+            AppleNotesError: If osascript isn't available (not macOS).
+        """
+        executable = shutil.which(osascript)
+        if executable is None:
+            raise AppleNotesError("osascript not found; Apple Notes export requires macOS")
+        self.root_folder = root_folder
+        self.logger = logger or logging.getLogger(__name__)
+        self.folder_parents = folder_parents or {}
+        self.executable = executable
+        self.force = force
+        self.failures: list[SyncFailure] = []
+
+    def sync(self, docs: Iterable[ExportDoc]) -> SyncStats:
+        """Write documents (rendered as HTML) to Apple Notes.
+
+        A document that fails to write is recorded in self.failures (and counted
+        in stats.failed) and the sync carries on with the next one.
+        """
+        stats = SyncStats()
+        self.failures = []
+        for doc in docs:
+            try:
+                action = self.write(doc)
+            except AppleNotesError as e:
+                self.logger.warning(f"Failed to write '{doc.title}' ({doc.id}) to Notes: {e}")
+                self.failures.append(SyncFailure(doc_id=doc.id, title=doc.title, error=str(e)))
+                stats.failed += 1
+                continue
+            self.logger.debug(f"Apple Notes: {action} '{doc.title}'")
+            if action == "added":
+                stats.added += 1
+            elif action == "updated":
+                stats.updated += 1
+            else:
+                stats.skipped += 1
+        return stats
+
+    def write(self, doc: ExportDoc) -> str:
+        """Create or update a document's note.
+
+        Returns:
+            "added", "updated" or "skipped" (unchanged).
+
+        Raises:
+            AppleNotesError: If the script fails.
+        """
+        marker = f"Granola ID: {doc.id}"
+        stamp = f"Updated: {doc.updated_at.isoformat()}"
+        body = f"{doc.content}\n<div><br></div>\n<div>{marker}<br>{stamp}</div>"
+        folder = doc.folders[0] if doc.folders else "Uncategorized"
+        path = [self.root_folder, *reversed(folder_ancestry(folder, self.folder_parents))]
+        try:
+            result = subprocess.run(
+                [
+                    self.executable,
+                    "-e",
+                    _SCRIPT,
+                    "\n".join(path),
+                    marker,
+                    stamp,
+                    body,
+                    "force" if self.force else "",
+                ],
+                capture_output=True,
+                text=True,
+            )
+        except OSError as e:
+            raise AppleNotesError(f"Failed to run osascript: {e}") from e
+        if result.returncode != 0:
+            raise AppleNotesError(result.stderr.strip() or f"exit code {result.returncode}")
+        return result.stdout.strip()
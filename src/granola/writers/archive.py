@@ -0,0 +1,88 @@
+"""Zip and gzipped tar archive writers for exports."""
+
+import io
+import os
+import tarfile
+import tempfile
+import time
+import zipfile
+from pathlib import Path
+from typing import Iterable
+
+
+def write_zip_archive(archive_path: Path, files: Iterable[tuple[Path, str | bytes]]) -> int:
+    """Write exported files into a zip archive, replacing any existing archive.
+
+    The archive is written to a temporary file first and then moved into place,
+    so an interrupted export never leaves a truncated archive behind.
+
+    Args:
+        archive_path: Path of the .zip file to write.
+        files: (path inside the archive, file content) pairs, written as they are
+            consumed so a generator keeps memory use flat. Repeated paths are skipped.
+
+    Returns:
+        Number of files written to the archive.
+    """
+    archive_path.parent.mkdir(parents=True, exist_ok=True)
+
+    written: set[str] = set()
+    fd, tmp_name = tempfile.mkstemp(dir=archive_path.parent, suffix=".zip.tmp")
+    try:
+        with os.fdopen(fd, "wb") as f, zipfile.ZipFile(f, "w", zipfile.ZIP_DEFLATED) as zf:
+            for path, content in files:
+                name = path.as_posix()
+                if name in written:
+                    continue
+                zf.writestr(name, content)
+                written.add(name)
+        os.replace(tmp_name, archive_path)
+    except BaseException:
+        Path(tmp_name).unlink(missing_ok=True)
+        raise
+
+    return len(written)
+
+
+def write_tar_archive(archive_path: Path, files: Iterable[tuple[Path, str | bytes]]) -> int:
+    """Write exported files into a gzip-compressed tar archive (.tar.gz).
+
+    Works like write_zip_archive; gzip usually compresses a large export of
+    transcripts better than zip, which compresses each file on its own.
+
+    Args:
+        archive_path: Path of the .tar.gz (or .tgz) file to write.
+        files: (path inside the archive, file content) pairs.
+
+    Returns:
+        Number of files written to the archive.
+    """
+    archive_path.parent.mkdir(parents=True, exist_ok=True)
+
+    written: set[str] = set()
+    now = time.time()
+    fd, tmp_name = tempfile.mkstemp(dir=archive_path.parent, suffix=".tar.gz.tmp")
+    try:
+        with os.fdopen(fd, "wb") as f, tarfile.open(fileobj=f, mode="w:gz") as tf:
+            for path, content in files:
+                name = path.as_posix()
+                if name in written:
+                    continue
+                data = content.encode("utf-8") if isinstance(content, str) else content
+                info = tarfile.TarInfo(name)
+                info.size = len(data)
+                info.mtime = int(now)
+                tf.addfile(info, io.BytesIO(data))
+                written.add(name)
+        os.replace(tmp_name, archive_path)
+    except BaseException:
+        Path(tmp_name).unlink(missing_ok=True)
+        raise
+
+    return len(written)
+
+
+def is_tar_archive(path: Path) -> bool:
+    """Return True if a path names a gzipped tar archive (.tar.gz or .tgz)."""
+    name = path.name.lower()
+    return name.endswith(".tar.gz") or name.endswith(".tgz")
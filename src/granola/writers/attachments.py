@@ -0,0 +1,124 @@
+"""Local copies of the images and files notes link to.
+
+Notes reference images and attachments on Granola's CDN, which may not keep
+them forever. Attachments downloads them into an attachments/ directory in the
+output and points the exported files at the copies.
+"""
+
+import hashlib
+import logging
+import os
+import re
+from pathlib import Path
+from urllib.parse import unquote, urlparse
+
+import httpx
+
+from granola.utils.filename import sanitize_filename
+from granola.writers.sync_writer import ExportDoc
+
+# Directory under the output directory that attachments are downloaded to
+ATTACHMENTS_DIR = "attachments"
+
+# Links to files with these extensions are attachments (images are always downloaded)
+ATTACHMENT_EXTENSIONS = frozenset(
+    ".pdf .png .jpg .jpeg .gif .webp .svg .heic "
+    ".doc .docx .xls .xlsx .ppt .pptx .csv .zip".split()
+)
+
+# Markdown images and links, and HTML images and links, to http(s) URLs
+MARKDOWN_LINK = re.compile(r"(!?)\[([^\]]*)\]\((https?://[^)\s]+)\)")
+_HTML_LINK = re.compile(r"""(<(img|a)\s[^>]*?\b(?:src|href)=["'])(https?://[^"']+)(["'])""")
+
+
+class Attachments:
+    """Downloads the attachments of exported documents (a SyncWriter content filter).
+
+    Called with each document and the path it's written to, it returns the
+    document's content with attachment URLs replaced by paths relative to that
+    file. Each URL is downloaded once; one that fails to download keeps its URL.
+    """
+
+    def __init__(
+        self,
+        output_dir: Path,
+        timeout: int = 120,
+        transport: httpx.BaseTransport | None = None,
+        logger: logging.Logger | None = None,
+    ):
+        """Initialize the downloader.
+
+        Args:
+            output_dir: Export output directory (attachments go in its attachments/).
+            timeout: HTTP timeout in seconds.
+            transport: Optional httpx transport downloads are sent through.
+            logger: Optional logger for failed downloads.
+        """
+        self.directory = output_dir / ATTACHMENTS_DIR
+        self.timeout = timeout
+        self.transport = transport
+        self.logger = logger or logging.getLogger(__name__)
+        self.downloaded: dict[str, Path | None] = {}  # URL -> local copy (None: failed)
+
+    def __call__(self, doc: ExportDoc, target_path: Path) -> str:
+        """Return a document's content with its attachments pointing at local copies."""
+
+        def local(url: str) -> str | None:
+            path = self._download(url)
+            if path is None:
+                return None
+            return Path(os.path.relpath(path, target_path.parent)).as_posix()
+
+        def markdown_link(match: re.Match[str]) -> str:
+            image, text, url = match.groups()
+            if not image and not is_attachment(url):
+                return match.group()
+            path = local(url)
+            return f"{image}[{text}]({path})" if path else match.group()
+
+        def html_link(match: re.Match[str]) -> str:
+            prefix, tag, url, quote = match.groups()
+            if tag.lower() == "a" and not is_attachment(url):
+                return match.group()
+            path = local(url)
+            return f"{prefix}{path}{quote}" if path else match.group()
+
+        content = MARKDOWN_LINK.sub(markdown_link, doc.content)
+        return _HTML_LINK.sub(html_link, content)
+
+    def _download(self, url: str) -> Path | None:
+        """Download a URL into the attachments directory (once), returning the copy."""
+        if url in self.downloaded:
+            return self.downloaded[url]
+
+        path: Path | None = self.directory / attachment_name(url)
+        if not path.exists():
+            try:
+                with httpx.Client(
+                    timeout=self.timeout, transport=self.transport, follow_redirects=True
+                ) as client:
+                    response = client.get(url)
+                    response.raise_for_status()
+                path.parent.mkdir(parents=True, exist_ok=True)
+                path.write_bytes(response.content)
+            except (httpx.HTTPError, OSError) as e:
+                self.logger.warning(f"Failed to download attachment {url}: {e}")
+                path = None
+        self.downloaded[url] = path
+        return path
+
+
+def is_attachment(url: str) -> bool:
+    """Return True if a link points at a file worth downloading."""
+    return Path(urlparse(url).path).suffix.lower() in ATTACHMENT_EXTENSIONS
+
+
+def attachment_name(url: str) -> str:
+    """Return the file name of a URL's local copy: its name plus a hash of the URL.
+
+    The hash keeps different files with the same name (image.png) apart.
+    """
+    name = Path(unquote(urlparse(url).path)).name or "attachment"
+    stem, suffix = os.path.splitext(name)
+    digest = hashlib.sha1(url.encode("utf-8")).hexdigest()[:8]
+    return f"{sanitize_filename(stem, fallback='attachment')}-{digest}{suffix.lower()}"
@@ -0,0 +1,42 @@
+"""Copies of meetings' local audio recordings, kept next to the exported notes."""
+
+import logging
+import shutil
+from pathlib import Path
+
+# Directory under the output directory that recordings are copied to
+AUDIO_DIR = "audio"
+
+
+def copy_recordings(
+    recordings: dict[str, Path],
+    output_dir: Path,
+    logger: logging.Logger | None = None,
+) -> dict[str, Path]:
+    """Copy recordings into the output's audio directory, named by document ID.
+
+    A recording already copied (same size) is not copied again. One that can't
+    be copied is logged and left out.
+
+    Args:
+        recordings: Document ID -> local recording.
+        output_dir: Export output directory.
+        logger: Optional logger for failures.
+
+    Returns:
+        Document ID -> copy, relative to output_dir.
+    """
+    logger = logger or logging.getLogger(__name__)
+    copies: dict[str, Path] = {}
+    for doc_id, recording in recordings.items():
+        relative = Path(AUDIO_DIR) / f"{doc_id}{recording.suffix}"
+        target = output_dir / relative
+        try:
+            if not target.exists() or target.stat().st_size != recording.stat().st_size:
+                target.parent.mkdir(parents=True, exist_ok=True)
+                shutil.copy2(recording, target)
+        except OSError as e:
+            logger.warning(f"Failed to copy audio of {doc_id} from {recording}: {e}")
+            continue
+        copies[doc_id] = relative
+    return copies
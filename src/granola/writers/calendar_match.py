@@ -0,0 +1,93 @@
+"""Match an export's notes with the events of a calendar, by iCalendar UID.
+
+Exported notes record the UID of their meeting's calendar event (event_uid)
+when Granola's calendar data has it. Matching those against a calendar export
+finds the meetings that have no notes.
+"""
+
+import re
+from dataclasses import dataclass, field
+from datetime import datetime
+from pathlib import Path
+
+from granola.formatters.ical import CalendarEvent
+from granola.writers.manifest import Manifest
+
+# The event_uid field as the formats write it: YAML (event_uid: x), the text
+# header (Event UID: x), AsciiDoc (:event-uid: x), reST (:event_uid: x), ...
+_EVENT_UID_FIELD = re.compile(
+    r"""\bevent[ _-]uid\b["']?:?\s*[:=]?\s*["']?([^\s"'<>,]+)""", re.IGNORECASE
+)
+
+
+@dataclass
+class MatchReport:
+    """Calendar events with and without notes in an export."""
+
+    matched: dict[str, list[Path]] = field(default_factory=dict)  # event UID -> notes
+    missing: list[CalendarEvent] = field(default_factory=list)  # past events without notes
+    unlisted: dict[str, list[Path]] = field(default_factory=dict)  # UIDs not in the calendar
+    unlinked: int = 0  # exported notes without an event UID
+
+
+def exported_event_uids(output_dir: Path) -> tuple[dict[str, list[Path]], int]:
+    """Read the event UIDs recorded in an export's files.
+
+    Returns:
+        The files recording each event UID, and the number of files without one
+        (or whose format doesn't record it, like compressed or encrypted files).
+    """
+    manifest = Manifest.load(output_dir)
+    uids: dict[str, list[Path]] = {}
+    unlinked = 0
+    for path in sorted(manifest.paths()):
+        try:
+            text = path.read_text(encoding="utf-8", errors="replace")
+        except OSError:
+            continue
+        match = _EVENT_UID_FIELD.search(text)
+        if match:
+            uids.setdefault(match.group(1), []).append(path)
+        else:
+            unlinked += 1
+    return uids, unlinked
+
+
+def match_events(
+    events: list[CalendarEvent],
+    output_dir: Path,
+    start: datetime | None = None,
+    end: datetime | None = None,
+    now: datetime | None = None,
+) -> MatchReport:
+    """Match calendar events with an export's notes.
+
+    A recurring event's occurrences share its UID, so a series counts as having
+    notes if any of its meetings has.
+
+    Args:
+        events: Events of the calendar (see parse_ical).
+        output_dir: Export output directory.
+        start: Only events starting at or after this time (and series still running).
+        end: Only events starting before this time.
+        now: Current time; later events can't have notes yet and are left out.
+    """
+    now = now or datetime.now().astimezone()
+    uids, unlinked = exported_event_uids(output_dir)
+    report = MatchReport(unlinked=unlinked)
+
+    calendar_uids: set[str] = set()
+    for event in sorted(events, key=lambda e: e.start):
+        calendar_uids.add(event.uid)
+        if event.start > now or (end and event.start >= end):
+            continue
+        # A series started before `start` may still have meetings after it
+        if start and event.start < start and not event.recurring:
+            continue
+        if event.uid in uids:
+            report.matched[event.uid] = uids[event.uid]
+        elif all(missing.uid != event.uid for missing in report.missing):
+            report.missing.append(event)
+
+    report.unlisted = {uid: paths for uid, paths in uids.items() if uid not in calendar_uids}
+    return report
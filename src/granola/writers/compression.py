@@ -0,0 +1,20 @@
+"""Gzip compression of exported files."""
+
+import gzip
+
+# Suffix appended to the names of compressed files (Standup_0f1e2d3c.txt.gz)
+GZIP_SUFFIX = ".gz"
+
+
+def gzip_content(content: str) -> bytes:
+    """Return content gzip-compressed (a SyncWriter encoder).
+
+    The gzip header's timestamp is left out, so the same content always
+    compresses to the same bytes.
+    """
+    return gzip_bytes(content.encode("utf-8"))
+
+
+def gzip_bytes(data: bytes) -> bytes:
+    """Return data gzip-compressed, without a timestamp (see gzip_content)."""
+    return gzip.compress(data, mtime=0)
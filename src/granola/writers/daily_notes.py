@@ -0,0 +1,106 @@
+"""Links to exported meetings in Obsidian daily notes."""
+
+import os
+import re
+from dataclasses import dataclass
+from datetime import date, datetime
+from pathlib import Path
+
+# Default daily note name, as in Obsidian's Daily notes plugin (strftime format)
+DEFAULT_DAILY_NOTE_FORMAT = "%Y-%m-%d"
+
+# The links granola maintains are kept between these markers; the rest of the
+# note is never touched
+BLOCK_START = "<!-- granola:meetings -->"
+BLOCK_END = "<!-- /granola:meetings -->"
+
+_BLOCK = re.compile(re.escape(BLOCK_START) + r"\n?(.*?)" + re.escape(BLOCK_END), re.DOTALL)
+
+
+@dataclass
+class DailyNoteEntry:
+    """A meeting to link from the daily note of the day it took place."""
+
+    title: str
+    started: datetime  # local start of the meeting (see ExportDoc.local_start)
+    file_path: Path  # exported file
+
+
+def daily_note_path(notes_dir: Path, day: date, name_format: str) -> Path:
+    """Return the daily note for a day (the format may include folders: %Y/%m/%Y-%m-%d)."""
+    return notes_dir / f"{day.strftime(name_format)}.md"
+
+
+def link_daily_notes(
+    entries: list[DailyNoteEntry],
+    notes_dir: Path,
+    name_format: str = DEFAULT_DAILY_NOTE_FORMAT,
+    vault: Path | None = None,
+) -> int:
+    """Add links to meetings to the daily notes of the days they took place.
+
+    Each daily note gets a block of links to that day's meetings, appended to the
+    note (or in a new note with just a heading, if there's none for the day).
+    Links already in the block are kept, so repeated exports only add new
+    meetings.
+
+    Args:
+        entries: Exported meetings.
+        notes_dir: Daily notes folder.
+        name_format: strftime format of daily note names, without .md.
+        vault: Obsidian vault root that links are relative to (default: notes_dir).
+
+    Returns:
+        Number of daily notes created or changed.
+
+    Raises:
+        OSError: If a daily note can't be read or written.
+    """
+    by_day: dict[date, list[DailyNoteEntry]] = {}
+    for entry in entries:
+        # Meetings belong to the day they took place on locally
+        by_day.setdefault(entry.started.date(), []).append(entry)
+
+    changed = 0
+    for day, day_entries in sorted(by_day.items()):
+        path = daily_note_path(notes_dir, day, name_format)
+        existing = path.read_text(encoding="utf-8") if path.exists() else None
+
+        links = _block_links(existing or "")
+        for entry in sorted(day_entries, key=lambda e: e.started):
+            link = _wikilink(entry, vault or notes_dir)
+            if link not in links:
+                links.append(link)
+        block = "\n".join([BLOCK_START, *links, BLOCK_END])
+
+        if existing is None:
+            content = f"# {day.isoformat()}\n\n## Meetings\n\n{block}\n"
+        elif _BLOCK.search(existing):
+            content = _BLOCK.sub(lambda _: block, existing, count=1)
+        else:
+            content = existing.rstrip("\n") + f"\n\n## Meetings\n\n{block}\n"
+
+        if content != existing:
+            path.parent.mkdir(parents=True, exist_ok=True)
+            path.write_text(content, encoding="utf-8")
+            changed += 1
+
+    return changed
+
+
+def _block_links(content: str) -> list[str]:
+    """Return the link lines in a note's granola block."""
+    match = _BLOCK.search(content)
+    if not match:
+        return []
+    return [line for line in match.group(1).splitlines() if line.strip()]
+
+
+def _wikilink(entry: DailyNoteEntry, vault: Path) -> str:
+    """Return a list item linking to an exported file, relative to the vault."""
+    target = Path(os.path.relpath(entry.file_path, vault)).as_posix()
+    # Obsidian resolves Markdown notes without their extension
+    if target.endswith(".md"):
+        target = target[: -len(".md")]
+    title = re.sub(r"[\[\]|#^]", "", entry.title or "Untitled").strip()
+    return f"- [[{target}|{title}]]"
@@ -0,0 +1,74 @@
+"""Encryption of exported files with age (https://age-encryption.org).
+
+Files are encrypted to the recipients' public keys by the age command-line
+tool, so only the holders of the matching identities can read them; the output
+folder can live on a shared or cloud drive.
+"""
+
+import shutil
+import subprocess
+
+# Suffix appended to the names of encrypted files (Standup_0f1e2d3c.md.age)
+AGE_SUFFIX = ".age"
+
+# Recipient types age accepts on the command line
+_RECIPIENT_PREFIXES = ("age1", "ssh-ed25519 ", "ssh-rsa ")
+
+
+class EncryptionError(Exception):
+    """Raised when files can't be encrypted."""
+
+
+class AgeEncryptor:
+    """Encrypts content to age recipients (a SyncWriter encoder)."""
+
+    def __init__(self, recipients: list[str], age_command: str = "age"):
+        """Initialize the encryptor.
+
+        Args:
+            recipients: age public keys (age1...) or SSH public keys.
+            age_command: Name or path of the age executable.
+
+        Raises:
+            EncryptionError: If a recipient is malformed or age isn't installed.
+        """
+        if not recipients:
+            raise EncryptionError("No age recipients given")
+        invalid = [r for r in recipients if not r.strip().startswith(_RECIPIENT_PREFIXES)]
+        if invalid:
+            raise EncryptionError(
+                f"Invalid age recipient {invalid[0]!r} (expected an age1... or SSH public key)"
+            )
+        executable = shutil.which(age_command)
+        if executable is None:
+            raise EncryptionError(
+                f"{age_command} not found; install age (e.g. brew install age) to encrypt exports"
+            )
+        self.recipients = [r.strip() for r in recipients]
+        self.executable = executable
+
+    def __call__(self, content: str) -> bytes:
+        """Return content encrypted to the recipients.
+
+        Raises:
+            EncryptionError: If age fails.
+        """
+        return self.encrypt(content.encode("utf-8"))
+
+    def encrypt(self, data: bytes) -> bytes:
+        """Return data encrypted to the recipients.
+
+        Raises:
+            EncryptionError: If age fails.
+        """
+        args = [self.executable]
+        for recipient in self.recipients:
+            args += ["--recipient", recipient]
+        try:
+            result = subprocess.run(args, input=data, capture_output=True)
+        except OSError as e:
+            raise EncryptionError(f"Failed to run age: {e}") from e
+        if result.returncode != 0:
+            message = result.stderr.decode("utf-8", "replace").strip()
+            raise EncryptionError(f"age failed: {message or f'exit code {result.returncode}'}")
+        return result.stdout
@@ -0,0 +1,100 @@
+"""Atom feed generation for exported meetings."""
+
+import xml.etree.ElementTree as ET
+from dataclasses import dataclass
+from datetime import datetime, timezone
+from pathlib import Path
+from urllib.parse import quote
+
+ATOM_NS = "http://www.w3.org/2005/Atom"
+
+# Feed file name written to the output directory root
+FEED_FILENAME = "index.xml"
+
+# Keep the feed small enough for feed readers to poll cheaply
+MAX_FEED_ENTRIES = 100
+
+# Length of the plain text summary shown for each entry
+SUMMARY_LENGTH = 280
+
+
+@dataclass
+class FeedEntry:
+    """A single meeting entry in the feed."""
+
+    id: str
+    title: str
+    created_at: datetime
+    updated_at: datetime
+    summary: str
+    file_path: Path  # exported file, relative to the feed location
+
+
+def write_atom_feed(
+    output_dir: Path,
+    entries: list[FeedEntry],
+    title: str = "Granola Meetings",
+) -> Path:
+    """Write an Atom feed of exported meetings to the output directory.
+
+    Entries are ordered newest first and capped at MAX_FEED_ENTRIES.
+
+    Args:
+        output_dir: Directory the feed is written to (export root).
+        entries: Feed entries to include.
+        title: Feed title.
+
+    Returns:
+        Path to the written feed file.
+    """
+    ET.register_namespace("", ATOM_NS)
+
+    entries = sorted(entries, key=lambda e: e.created_at, reverse=True)[:MAX_FEED_ENTRIES]
+    feed_updated = max((e.updated_at for e in entries), default=datetime.now(timezone.utc))
+
+    feed = ET.Element(f"{{{ATOM_NS}}}feed")
+    ET.SubElement(feed, f"{{{ATOM_NS}}}title").text = title
+    ET.SubElement(feed, f"{{{ATOM_NS}}}id").text = "urn:granola:meetings"
+    ET.SubElement(feed, f"{{{ATOM_NS}}}updated").text = _format_date(feed_updated)
+    ET.SubElement(feed, f"{{{ATOM_NS}}}link", href=FEED_FILENAME, rel="self")
+    author = ET.SubElement(feed, f"{{{ATOM_NS}}}author")
+    ET.SubElement(author, f"{{{ATOM_NS}}}name").text = "Granola"
+
+    for entry in entries:
+        item = ET.SubElement(feed, f"{{{ATOM_NS}}}entry")
+        ET.SubElement(item, f"{{{ATOM_NS}}}title").text = entry.title or "Untitled"
+        ET.SubElement(item, f"{{{ATOM_NS}}}id").text = f"urn:granola:{entry.id}"
+        ET.SubElement(item, f"{{{ATOM_NS}}}published").text = _format_date(entry.created_at)
+        ET.SubElement(item, f"{{{ATOM_NS}}}updated").text = _format_date(entry.updated_at)
+        ET.SubElement(
+            item,
+            f"{{{ATOM_NS}}}link",
+            href=quote(entry.file_path.as_posix()),
+            rel="alternate",
+        )
+        if entry.summary:
+            ET.SubElement(item, f"{{{ATOM_NS}}}summary").text = entry.summary
+
+    output_dir.mkdir(parents=True, exist_ok=True)
+    feed_path = output_dir / FEED_FILENAME
+    tree = ET.ElementTree(feed)
+    ET.indent(tree)
+    tree.write(feed_path, encoding="utf-8", xml_declaration=True)
+    return feed_path
+
+
+def make_summary(text: str, length: int = SUMMARY_LENGTH) -> str:
+    """Collapse text to a single line and truncate it for use as a summary."""
+    summary = " ".join(
+        line.lstrip("#-*> \t").strip() for line in text.splitlines() if line.strip()
+    )
+    if len(summary) > length:
+        summary = summary[: length - 1].rstrip() + "…"
+    return summary
+
+
+def _format_date(dt: datetime) -> str:
+    """Format a datetime as RFC 3339 in UTC."""
+    if dt.tzinfo is None:
+        dt = dt.replace(tzinfo=timezone.utc)
+    return dt.astimezone(timezone.utc).strftime("%Y-%m-%dT%H:%M:%SZ")
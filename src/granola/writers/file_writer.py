@@ -5,7 +5,8 @@ from pathlib import Path
 from typing import Callable, TypeVar
 
 from granola.api.models import Document
-from granola.utils.filename import make_unique, sanitize_filename
+from granola.utils.filename import make_unique, sanitize_filename, zettel_id
+from granola.writers.sync_writer import SyncFailure
 
 T = TypeVar("T")
 
@@ -15,6 +16,9 @@ def write_documents(
     output_dir: Path,
     converter: Callable[[Document], str],
     extension: str = ".md",
+    zettel: bool = False,
+    force: bool = False,
+    on_error: Callable[[SyncFailure], None] | None = None,
 ) -> int:
     """Write documents to files with incremental updates.
 
@@ -23,6 +27,10 @@ def write_documents(
         output_dir: Directory to write files to.
         converter: Function to convert document to string content.
         extension: File extension (default: .md).
+        zettel: Prefix filenames with a timestamp-based zettel ID (YYYYMMDDHHMM).
+        force: Write every file, even if its document wasn't updated since.
+        on_error: Called with each document that fails to convert or write, after
+            which the rest are still written. Without it, the first failure is raised.
 
     Returns:
         Number of files written.
@@ -35,18 +43,27 @@ def write_documents(
     for doc in docs:
         # Generate unique filename
         filename = sanitize_filename(doc.title or doc.id, fallback=doc.id)
+        started = doc.local_start
+        if zettel and started:
+            filename = f"{zettel_id(started)} {filename}"
         filename = make_unique(filename, used_filenames)
         used_filenames[filename] = used_filenames.get(filename, 0) + 1
 
         file_path = output_dir / f"{filename}{extension}"
 
         # Check if file needs updating
-        if not should_update_file(file_path, doc.updated_at):
+        if not force and not should_update_file(file_path, doc.content_updated_at):
             continue
 
         # Convert and write
-        content = converter(doc)
-        file_path.write_text(content)
+        try:
+            content = converter(doc)
+            file_path.write_text(content)
+        except Exception as e:
+            if on_error is None:
+                raise
+            on_error(SyncFailure(doc_id=doc.id, title=doc.title or doc.id, error=str(e)))
+            continue
         written += 1
 
     return written
@@ -0,0 +1,167 @@
+"""Manifest of files written by the sync writer.
+
+The manifest lets the sync writer tell its own files apart from anything else
+in the output directory, so it never deletes or moves files it didn't create.
+It also records a checksum of each file as written, so changes made to the files
+outside granola can be found (granola verify), and which documents were merged
+into each file of duplicates (export --merge).
+"""
+
+import hashlib
+import json
+from pathlib import Path
+
+# Manifest file name stored in the output folder root
+MANIFEST_FILENAME = ".granola-manifest.json"
+
+
+class Manifest:
+    """Map of exported file path -> Granola document ID (and the file's checksum)."""
+
+    def __init__(
+        self,
+        root: Path,
+        files: dict[str, str] | None = None,
+        checksums: dict[str, str] | None = None,
+        merged: dict[str, list[str]] | None = None,
+    ):
+        """Initialize the manifest.
+
+        Args:
+            root: Output directory the manifest is stored in. Paths inside it are
+                recorded relative to it, so the folder can move between computers.
+            files: Existing entries (manifest key -> document ID).
+            checksums: SHA-256 of each file as last written (manifest key -> hex
+                digest). Manifests written by older versions have none.
+            merged: Documents merged into each document's files (document ID ->
+                IDs of the duplicates merged, including itself).
+        """
+        self.root = root
+        self.files = files or {}
+        self.checksums = checksums or {}
+        self.merged = merged or {}
+
+    @classmethod
+    def load(cls, root: Path) -> "Manifest":
+        """Load the manifest from an output directory (empty if missing or invalid)."""
+        try:
+            data = json.loads((root / MANIFEST_FILENAME).read_text(encoding="utf-8"))
+            files = data.get("files", {})
+            if not isinstance(files, dict):
+                files = {}
+            checksums = data.get("checksums", {})
+            if not isinstance(checksums, dict):
+                checksums = {}
+            merged = data.get("merged", {})
+            if not isinstance(merged, dict):
+                merged = {}
+        except (json.JSONDecodeError, OSError, AttributeError):
+            files, checksums, merged = {}, {}, {}
+        return cls(
+            root,
+            {str(k): str(v) for k, v in files.items()},
+            {str(k): str(v) for k, v in checksums.items() if k in files},
+            {str(k): [str(i) for i in v] for k, v in merged.items() if isinstance(v, list)},
+        )
+
+    def save(self) -> bool:
+        """Write the manifest to the output directory.
+
+        Returns:
+            True if saved successfully, False otherwise.
+        """
+        try:
+            self.root.mkdir(parents=True, exist_ok=True)
+            (self.root / MANIFEST_FILENAME).write_text(
+                json.dumps(
+                    {
+                        "version": 2,
+                        "files": dict(sorted(self.files.items())),
+                        "checksums": dict(sorted(self.checksums.items())),
+                        "merged": dict(sorted(self.merged.items())),
+                    },
+                    indent=2,
+                ),
+                encoding="utf-8",
+            )
+            return True
+        except OSError:
+            return False
+
+    def doc_id(self, path: Path) -> str | None:
+        """Return the document ID a file was written for, or None if unknown."""
+        return self.files.get(self._key(path))
+
+    def checksum(self, path: Path) -> str | None:
+        """Return the checksum of a file as last written, or None if not recorded."""
+        return self.checksums.get(self._key(path))
+
+    def paths(self) -> list[Path]:
+        """Return the paths of all files in the manifest."""
+        return [self._path(key) for key in self.files]
+
+    def record(self, path: Path, doc_id: str, checksum: str | None = None) -> None:
+        """Record that a file was written for a document (with its checksum, if known)."""
+        key = self._key(path)
+        self.files[key] = doc_id
+        if checksum:
+            self.checksums[key] = checksum
+
+    def record_merge(self, doc_id: str, sources: list[str]) -> None:
+        """Record the duplicates merged into a document's files (none to forget a merge)."""
+        if sources:
+            self.merged[doc_id] = sorted(sources)
+        else:
+            self.merged.pop(doc_id, None)
+
+    def is_merged(self, path: Path) -> bool:
+        """Return True if a file was written for duplicates merged together."""
+        return self.doc_id(path) in self.merged
+
+    def forget(self, path: Path) -> None:
+        """Remove a file from the manifest."""
+        key = self._key(path)
+        self.files.pop(key, None)
+        self.checksums.pop(key, None)
+
+    def move_dir(self, old_dir: Path, new_dir: Path) -> None:
+        """Update entries for files moved by renaming a directory."""
+        for key, doc_id in list(self.files.items()):
+            path = self._path(key)
+            if path.is_relative_to(old_dir):
+                del self.files[key]
+                new_key = self._key(new_dir / path.relative_to(old_dir))
+                self.files[new_key] = doc_id
+                if key in self.checksums:
+                    self.checksums[new_key] = self.checksums.pop(key)
+
+    def prune(self) -> None:
+        """Drop entries for files that no longer exist."""
+        self.files = {k: v for k, v in self.files.items() if self._path(k).is_file()}
+        self.checksums = {k: v for k, v in self.checksums.items() if k in self.files}
+        doc_ids = set(self.files.values())
+        self.merged = {k: v for k, v in self.merged.items() if k in doc_ids}
+
+    def _key(self, path: Path) -> str:
+        """Return the manifest key for a path."""
+        if path.is_relative_to(self.root):
+            return path.relative_to(self.root).as_posix()
+        return str(path)
+
+    def _path(self, key: str) -> Path:
+        """Return the path for a manifest key."""
+        path = Path(key)
+        return path if path.is_absolute() else self.root / path
+
+
+def file_checksum(path: Path) -> str:
+    """Return the SHA-256 hex digest of a file's contents.
+
+    Raises:
+        OSError: If the file can't be read.
+    """
+    digest = hashlib.sha256()
+    with path.open("rb") as f:
+        for chunk in iter(lambda: f.read(1 << 16), b""):
+            digest.update(chunk)
+    return digest.hexdigest()
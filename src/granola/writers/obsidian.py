@@ -0,0 +1,46 @@
+"""Obsidian vault detection, so exports never touch a vault's own files."""
+
+import json
+import logging
+from pathlib import Path
+
+# Directories Obsidian keeps its config and deleted notes in
+VAULT_DIRS = (".obsidian", ".trash")
+
+
+def find_obsidian_vault(path: Path) -> Path | None:
+    """Return the root of the Obsidian vault containing path, if any.
+
+    A vault is a directory with an .obsidian directory in it.
+    """
+    for directory in (path, *path.parents):
+        if (directory / ".obsidian").is_dir():
+            return directory
+    return None
+
+
+def vault_protected_dirs(vault: Path, logger: logging.Logger | None = None) -> list[Path]:
+    """Return the vault directories an export must not scan, prune, or clean up.
+
+    These are the config and trash directories and the template folders of the
+    core Templates plugin and the Templater plugin.
+    """
+    logger = logger or logging.getLogger(__name__)
+    dirs = [vault / name for name in VAULT_DIRS]
+
+    settings = (
+        (vault / ".obsidian" / "templates.json", "folder"),
+        (vault / ".obsidian" / "plugins" / "templater-obsidian" / "data.json", "templates_folder"),
+    )
+    for path, key in settings:
+        if not path.is_file():
+            continue
+        try:
+            folder = json.loads(path.read_text(encoding="utf-8")).get(key)
+        except (OSError, ValueError, AttributeError) as e:
+            logger.debug(f"Failed to read {path}: {e}")
+            continue
+        if isinstance(folder, str) and folder.strip("/ "):
+            dirs.append(vault / folder.strip("/ "))
+
+    return dirs
@@ -0,0 +1,75 @@
+"""State of the last successful export run, stored in the output folder.
+
+The state records the newest updated_at seen for each exported document, so a
+later run can skip the ones that haven't changed since (--changed-only) without
+relying on the exported files' modification times.
+"""
+
+import json
+from pathlib import Path
+
+from granola.utils.dates import parse_timestamp
+
+# State file name stored in the output folder root
+STATE_FILENAME = ".granola-state.json"
+
+
+class RunState:
+    """Map of document ID -> newest updated_at (ISO 8601) exported."""
+
+    def __init__(self, root: Path, documents: dict[str, str] | None = None):
+        """Initialize the state.
+
+        Args:
+            root: Output directory the state is stored in.
+            documents: Existing entries (document ID -> updated_at).
+        """
+        self.root = root
+        self.documents = documents or {}
+
+    @classmethod
+    def load(cls, root: Path) -> "RunState":
+        """Load the state from an output directory (empty if missing or invalid)."""
+        try:
+            data = json.loads((root / STATE_FILENAME).read_text(encoding="utf-8"))
+            documents = data.get("documents", {})
+            if not isinstance(documents, dict):
+                documents = {}
+        except (json.JSONDecodeError, OSError, AttributeError):
+            documents = {}
+        return cls(root, {str(k): str(v) for k, v in documents.items()})
+
+    def save(self) -> bool:
+        """Write the state to the output directory.
+
+        Returns:
+            True if saved successfully, False otherwise.
+        """
+        try:
+            self.root.mkdir(parents=True, exist_ok=True)
+            (self.root / STATE_FILENAME).write_text(
+                json.dumps(
+                    {"version": 1, "documents": dict(sorted(self.documents.items()))}, indent=2
+                ),
+                encoding="utf-8",
+            )
+            return True
+        except OSError:
+            return False
+
+    def changed(self, doc_id: str, updated_at: str) -> bool:
+        """Return True if a document was updated after the version last exported.
+
+        Documents never exported, and ones whose times can't be compared, count
+        as changed.
+        """
+        previous = parse_timestamp(self.documents.get(doc_id, ""))
+        current = parse_timestamp(updated_at)
+        if previous is None or current is None:
+            return True
+        return current > previous
+
+    def record(self, doc_id: str, updated_at: str) -> None:
+        """Record that a document was exported as of updated_at (keeping the newest)."""
+        if self.changed(doc_id, updated_at):
+            self.documents[doc_id] = updated_at
@@ -1,13 +1,35 @@
 """Advanced sync writer with folder structure support."""
 
 import logging
+import os
 import re
-from dataclasses import dataclass, field
+import time
+from dataclasses import dataclass, field, replace
 from datetime import datetime, timezone
 from pathlib import Path
+from typing import Callable, Iterable
+
+from granola.utils.dates import date_formats
+from granola.utils.filename import zettel_id
+from granola.utils.paths import format_output_template
+from granola.utils.timing import Timings
+from granola.writers.compression import GZIP_SUFFIX
+from granola.writers.encryption import AGE_SUFFIX
+from granola.writers.manifest import Manifest, file_checksum
+from granola.writers.obsidian import VAULT_DIRS, find_obsidian_vault, vault_protected_dirs
 
 INVALID_CHARS = re.compile(r'[<>:"/\\|?*\x00-\x1f]')
 
+# Supported directory layouts for the default (non-custom) layout
+ORGANIZE_BY = ("folder", "date", "folder-date", "tag")
+
+# Directory old meetings are moved into (Archive/YYYY/...; see SyncWriter's archive_before)
+ARCHIVE_DIR = "Archive"
+
+# Suffixes appended to the names of encoded files (see SyncWriter's encode):
+# compressed, encrypted, or both; longest first
+ENCODED_SUFFIXES = (GZIP_SUFFIX + AGE_SUFFIX, GZIP_SUFFIX, AGE_SUFFIX)
+
 
 @dataclass
 class ExportDoc:
@@ -23,6 +45,24 @@ class ExportDoc:
     has_transcript: bool = False  # whether document has transcript content
     notes_content: str = ""  # just the notes section (for webhooks)
     transcript_content: str = ""  # just the transcript section (for webhooks)
+    tags: list[str] = field(default_factory=list)  # document tags (for --organize-by tag)
+    # IDs of the duplicates merged into this document, including its own (--merge)
+    merged_from: list[str] = field(default_factory=list)
+    # Start of the meeting's calendar event, in the event's timezone (if known)
+    started_at: datetime | None = None
+
+    @property
+    def local_start(self) -> datetime:
+        """When the meeting started, in local time, for dates in file and folder names.
+
+        That's the calendar event's start in its own timezone, or else created_at in
+        the system timezone, so evening meetings aren't filed under the next (UTC) day.
+        """
+        return self.started_at or self.created_at.astimezone()
+
+
+# What a sync can do with a document's file, as counted in SyncStats
+SYNC_ACTIONS = ("added", "updated", "moved", "deleted", "skipped", "failed")
 
 
 @dataclass
@@ -34,6 +74,44 @@ class SyncStats:
     moved: int = 0
     deleted: int = 0
     skipped: int = 0
+    failed: int = 0
+    # Counts per destination folder (relative to the output directory) and action,
+    # e.g. {"Client X": {"updated": 4}}. Failed documents have no folder.
+    folders: dict[str, dict[str, int]] = field(default_factory=dict)
+
+    def count(self, action: str, folder: str) -> None:
+        """Count one file under an action (one of SYNC_ACTIONS), in total and for its folder."""
+        setattr(self, action, getattr(self, action) + 1)
+        counts = self.folders.setdefault(folder, {})
+        counts[action] = counts.get(action, 0) + 1
+
+    def add(self, other: "SyncStats") -> None:
+        """Add another set of statistics (e.g. one document's) to these."""
+        for action in SYNC_ACTIONS:
+            setattr(self, action, getattr(self, action) + getattr(other, action))
+        for folder, counts in other.folders.items():
+            own = self.folders.setdefault(folder, {})
+            for action, n in counts.items():
+                own[action] = own.get(action, 0) + n
+
+
+@dataclass
+class SyncFailure:
+    """A document that could not be exported."""
+
+    doc_id: str
+    title: str
+    error: str
+
+
+@dataclass
+class PlannedChange:
+    """A change to a file that a dry-run sync would make (see SyncWriter's dry_run)."""
+
+    action: str  # "added" | "updated" | "moved" | "deleted"
+    path: Path
+    # Where a moved file (or directory, for a renamed folder) is moved from
+    source: Path | None = None
 
 
 @dataclass
@@ -53,6 +131,21 @@ class SyncWriter:
         output_dir: Path,
         logger: logging.Logger | None = None,
         excluded_folders: list[str] | None = None,
+        included_folders: list[str] | None = None,
+        extension: str = ".txt",
+        layout: Callable[[ExportDoc], list[Path]] | None = None,
+        zettel: bool = False,
+        organize_by: str = "folder",
+        folder_paths: dict[str, Path] | None = None,
+        folder_parents: dict[str, str] | None = None,
+        timings: Timings | None = None,
+        content_filter: Callable[[ExportDoc, Path], str] | None = None,
+        encode: Callable[[str], bytes] | None = None,
+        encoded_suffix: str = "",
+        archive_before: datetime | None = None,
+        subdir_template: str | None = None,
+        force: bool = False,
+        dry_run: bool = False,
     ):
         """Initialize the sync writer.
 
@@ -60,114 +153,373 @@ class SyncWriter:
             output_dir: Root directory for exported files.
             logger: Optional logger for debug output.
             excluded_folders: Folder names to exclude from sync (files will be deleted).
+            included_folders: If set, only these folders are written to.
+            extension: File extension of exported files (used for naming and scanning).
+            layout: Optional function returning a document's target paths relative to
+                output_dir. Defaults to one file per Granola folder. Paths must end in
+                the short document ID (or be an index file in a directory that does).
+            zettel: Prefix filenames with a zettel ID (YYYYMMDDHHMM) instead of the date.
+            organize_by: Directory layout: "folder" (Granola folders), "date" (YYYY/MM),
+                "folder-date" (Granola folders, then YYYY/MM), or "tag" (one directory
+                per document tag).
+            folder_paths: Map of Granola folder name -> directory to write that folder's
+                documents to instead of output_dir/<folder> (may be outside output_dir).
+            folder_parents: Map of folder name -> parent folder name. Nested folders
+                are written under their parents (Clients/Acme/...), and excluding or
+                including a folder applies to its subfolders too.
+            timings: Optional accumulator for time spent writing and pruning files.
+            content_filter: Optional function returning the content to write for a
+                document to a target path, e.g. to make links relative to the file.
+            encode: Optional function turning content into the bytes written, e.g. to
+                compress or encrypt it. Encoded files can't be compared with new content, so
+                moved files are always rewritten.
+            encoded_suffix: Suffix appended to the names of encoded files (one of
+                ENCODED_SUFFIXES). Files named with or without it are recognized,
+                so turning encoding on or off converts existing files.
+            archive_before: Documents created before this are written under
+                Archive/YYYY/ in output_dir instead (keeping the rest of their
+                path), and their files elsewhere moved there.
+            subdir_template: Directory under output_dir to write each document's
+                files in, filled in with its local start date (e.g. "{year}/{month}";
+                see format_output_template).
+            force: Rewrite every file, even those of documents not updated since
+                they were written (e.g. after changing a template or format).
+            dry_run: Only plan the sync: sync() works out what it would add, update,
+                move and delete, and records it in self.plan, but no file, folder
+                or manifest is written, moved or deleted.
         """
+        if organize_by not in ORGANIZE_BY:
+            raise ValueError(f"Unknown organize_by '{organize_by}'")
+
         self.output_dir = output_dir
         self.logger = logger or logging.getLogger(__name__)
         self.excluded_folders = set(excluded_folders or [])
+        self.included_folders = set(included_folders or [])
+        self.extension = extension
+        self.layout = layout
+        self.zettel = zettel
+        self.organize_by = organize_by
+        self.folder_paths = folder_paths or {}
+        self.folder_parents = folder_parents or {}
+        self.timings = timings or Timings()
+        self.content_filter = content_filter
+        self.encode = encode
+        self.encoded_suffix = encoded_suffix
+        self.archive_before = archive_before
+        self.subdir_template = subdir_template
+        self.force = force
+        self.dry_run = dry_run
+        self.manifest = Manifest(output_dir)
+        # Inside an Obsidian vault, its config, trash and template folders are never
+        # scanned, pruned or cleaned up (unless the output is inside one of them)
+        self.vault = find_obsidian_vault(output_dir)
+        self.protected_dirs = [
+            d
+            for d in (vault_protected_dirs(self.vault, self.logger) if self.vault else [])
+            if not output_dir.is_relative_to(d)
+        ]
+        self.failures: list[SyncFailure] = []
+        # Changes the last sync would make (dry_run only)
+        self.plan: list[PlannedChange] = []
+        self.interrupted = False
+        self._stop_requested = False
 
     def sync(
-        self, docs: list[ExportDoc], all_doc_ids: set[str]
+        self,
+        docs: Iterable[ExportDoc],
+        all_doc_ids: set[str],
+        outline: Iterable[ExportDoc] | None = None,
+        on_result: Callable[[SyncResult], None] | None = None,
+        prune: bool = True,
+        on_failure: Callable[[SyncFailure], None] | None = None,
     ) -> tuple[SyncStats, list[SyncResult]]:
         """Synchronize documents to the output directory with folder structure.
 
         Handles adding, updating, moving, and deleting files as needed.
         Respects excluded_folders - files in excluded folders are deleted.
 
+        Only files recorded in the output directory's manifest, or named after one
+        of all_doc_ids, are ever deleted or moved; anything else is left alone.
+
+        Documents are written one at a time as they are consumed from docs, so a
+        generator keeps memory use flat regardless of the number of documents.
+
         Args:
-            docs: Documents to sync.
+            docs: Documents to sync (any iterable, consumed once).
             all_doc_ids: Set of all valid document IDs (for orphan detection).
+            outline: The same documents without content, used to detect renamed
+                folders before anything is written. Defaults to docs if it is a list;
+                otherwise folder renames are handled file by file.
+            on_result: Called with each per-document result as it happens. When
+                set, results are not collected and the returned list is empty.
+            prune: Delete orphaned files and empty folders after writing. Without
+                it, the sync only adds (see prune()).
+            on_failure: Called with each document that fails to write, e.g. to
+                request_stop() after too many.
+
+        A document that fails to write is recorded in self.failures (and counted in
+        stats.failed) and the sync carries on with the next one.
+
+        If request_stop() is called during the sync, it stops after the current
+        document, skips deleting orphans, saves the manifest, and sets
+        self.interrupted.
+
+        With dry_run set, stats and results are those of a real sync, and each
+        file change is recorded in self.plan instead of being made.
 
         Returns:
             Tuple of (statistics, list of per-document results).
         """
         stats = SyncStats()
         results: list[SyncResult] = []
+        self.failures = []
+        self.plan = []
+        self.interrupted = False
+        self._stop_requested = False
 
         # Create output directory if it doesn't exist
-        self.output_dir.mkdir(parents=True, exist_ok=True)
+        if not self.dry_run:
+            self.output_dir.mkdir(parents=True, exist_ok=True)
+
+        # Load the record of files written by previous syncs
+        self.manifest = Manifest.load(self.output_dir)
 
-        # Step 1: Delete all files in excluded folders
-        # This ensures exclusions sync across computers - we "own" the sync folder
-        stats.deleted += self._delete_excluded_folders()
+        if self.vault is not None:
+            self._check_vault(self.vault)
+
+        # Step 1: Delete our files in excluded folders
+        # This ensures exclusions sync across computers
+        with self.timings.phase("prune"):
+            for path in self._delete_excluded_folders(all_doc_ids):
+                stats.count("deleted", self._stats_folder(path))
 
         # Step 2: Scan existing files and build ID -> paths mapping
         existing_files = self._scan_existing_files()
 
-        # Step 3: Process each document (filtering out excluded folders)
+        # Step 3: Rename directories of folders renamed in Granola, so cloud sync
+        # sees a move instead of every file being deleted and re-added
+        renamed: set[Path] = set()
+        if outline is None and isinstance(docs, list):
+            outline = docs
+        if outline is not None:
+            with self.timings.phase("write"):
+                renamed = self._rename_folders(
+                    (replace(doc, folders=self._filter_folders(doc.folders)) for doc in outline),
+                    existing_files,
+                    all_doc_ids,
+                )
+
+        # Step 4: Process each document, filtering out excluded (and non-included) folders
+        # If doc was ONLY in excluded folders, it now has no folders
+        # (will go to Uncategorized, but we might want to skip it entirely)
+        # For now, we keep it in Uncategorized - user can exclude that too
         for doc in docs:
-            # Filter out excluded folders from the doc's folder list
-            filtered_folders = [
-                f for f in doc.folders if f not in self.excluded_folders
-            ]
-
-            # If doc was ONLY in excluded folders, it now has no folders
-            # (will go to Uncategorized, but we might want to skip it entirely)
-            # For now, we keep it in Uncategorized - user can exclude that too
-
-            # Create a copy of doc with filtered folders
-            filtered_doc = ExportDoc(
-                id=doc.id,
-                title=doc.title,
-                created_at=doc.created_at,
-                updated_at=doc.updated_at,
-                content=doc.content,
-                folders=filtered_folders,
-                has_notes=doc.has_notes,
-                has_transcript=doc.has_transcript,
-                notes_content=doc.notes_content,
-                transcript_content=doc.transcript_content,
-            )
+            if self._stop_requested:
+                break
+            doc = replace(doc, folders=self._filter_folders(doc.folders))
+            try:
+                with self.timings.phase("write"):
+                    doc_stats, doc_results = self._process_document(doc, existing_files, renamed)
+            except Exception as e:
+                # One unwritable document shouldn't abort the whole sync
+                self.logger.warning(f"Failed to write '{doc.title}' ({doc.id}): {e}")
+                failure = SyncFailure(doc_id=doc.id, title=doc.title, error=str(e))
+                self.failures.append(failure)
+                stats.failed += 1
+                if on_failure:
+                    on_failure(failure)
+                continue
+            stats.add(doc_stats)
+            if on_result:
+                for result in doc_results:
+                    on_result(result)
+            else:
+                results.extend(doc_results)
+
+        # A stopped sync only finishes the file it was writing: skip the deletion phase
+        self.interrupted = self._stop_requested
+        prune_start = time.perf_counter()
+        if not self.interrupted and prune:
+            # Step 5: Delete orphaned files (files whose doc IDs are not in all_doc_ids)
+            for path in self._find_orphans(existing_files, all_doc_ids):
+                if self._delete_orphan(path):
+                    stats.count("deleted", self._stats_folder(path))
+
+            # Step 6: Clean up empty folders
+            if not self.dry_run:
+                self._clean_empty_folders()
+        self.timings.add("prune", time.perf_counter() - prune_start)
+
+        if self.dry_run:
+            return stats, results
+
+        # Step 7: Save the manifest of files we own
+        self.manifest.prune()
+        if not self.manifest.save():
+            self.logger.warning(f"Failed to save manifest to {self.output_dir}")
 
-            doc_stats, doc_results = self._process_document(filtered_doc, existing_files)
-            stats.added += doc_stats.added
-            stats.updated += doc_stats.updated
-            stats.moved += doc_stats.moved
-            stats.deleted += doc_stats.deleted
-            stats.skipped += doc_stats.skipped
-            results.extend(doc_results)
+        return stats, results
+
+    def prune(
+        self, all_doc_ids: set[str], dry_run: bool = False
+    ) -> tuple[list[Path], list[Path]]:
+        """Delete orphaned files and empty folders, without writing anything (granola clean).
+
+        Orphans are found as in sync(): files in the manifest whose document isn't
+        one of all_doc_ids. Folders are removed if they are empty, or left empty by
+        deleting the orphans (e.g. by moves and renames of export --no-prune).
+
+        Args:
+            all_doc_ids: Set of all valid document IDs.
+            dry_run: Only find the orphans and folders; nothing is deleted.
+
+        Returns:
+            The orphaned files and empty folders deleted (or to be deleted, with dry_run).
+        """
+        self.manifest = Manifest.load(self.output_dir)
+        if self.vault is not None:
+            self._check_vault(self.vault)
+        orphans = self._find_orphans(self._scan_existing_files(), all_doc_ids)
+        if dry_run:
+            return orphans, self.empty_folders(orphans)
+
+        with self.timings.phase("prune"):
+            deleted = [path for path in orphans if self._delete_orphan(path)]
+            removed = self._clean_empty_folders()
+        self.manifest.prune()
+        if not self.manifest.save():
+            self.logger.warning(f"Failed to save manifest to {self.output_dir}")
+        return deleted, removed
+
+    def touch(self, docs: Iterable[ExportDoc]) -> list[Path]:
+        """Set documents' files' modification times to when they were updated, writing nothing.
+
+        Repairs an output folder restored from a backup (export --touch-only): each
+        existing file at a document's target path gets the document's updated time
+        as its mtime, and is recorded in the manifest as it is, so later syncs treat
+        it as up to date and as theirs. Missing files aren't written, and files
+        elsewhere aren't moved.
+
+        Args:
+            docs: Documents whose files to touch (content isn't needed).
 
-        # Step 4: Delete orphaned files (files whose doc IDs are not in all_doc_ids)
+        Returns:
+            The files touched.
+        """
+        self.manifest = Manifest.load(self.output_dir)
+        touched: list[Path] = []
+        for doc in docs:
+            stamp = doc.updated_at.timestamp()
+            for path in self.get_target_paths(doc):
+                if not path.is_file():
+                    continue
+                try:
+                    os.utime(path, (stamp, stamp))
+                except OSError as e:
+                    self.logger.warning(f"Failed to touch {path}: {e}")
+                    continue
+                self.manifest.record(path, doc.id, file_checksum(path))
+                touched.append(path)
+        if not self.manifest.save():
+            self.logger.warning(f"Failed to save manifest to {self.output_dir}")
+        return touched
+
+    def request_stop(self) -> None:
+        """Ask a running sync to stop after the document it is writing.
+
+        Safe to call from a signal handler.
+        """
+        self._stop_requested = True
+
+    def _check_vault(self, vault: Path) -> None:
+        """Warn about output directories that put an Obsidian vault's own files at risk."""
+        self.logger.info(
+            f"Output is in the Obsidian vault {vault}; skipping "
+            f"{', '.join(str(d.relative_to(vault)) for d in self.protected_dirs)}"
+        )
+        if self.output_dir == vault:
+            self.logger.warning(
+                f"Exporting to the root of the Obsidian vault {vault}: deleted meetings "
+                "are pruned anywhere in the vault. Consider exporting to a subfolder."
+            )
+        for name in VAULT_DIRS:
+            if self.output_dir.is_relative_to(vault / name):
+                self.logger.warning(
+                    f"Exporting into the Obsidian vault's {name} folder ({self.output_dir}); "
+                    "empty folders there will be removed and vault files may be overwritten"
+                )
+
+    def _is_protected(self, path: Path) -> bool:
+        """Return True if a path is in a directory an export must leave alone."""
+        return any(path.is_relative_to(d) for d in self.protected_dirs)
+
+    def _find_orphans(
+        self, existing_files: dict[str, list[Path]], all_doc_ids: set[str]
+    ) -> list[Path]:
+        """Return our files whose documents are not in all_doc_ids.
+
+        Files not in the manifest are never orphans (with a warning), and neither
+        are files of merged duplicates (see ExportDoc.merged_from).
+        """
+        orphans = []
         for doc_id, paths in existing_files.items():
             # Use short ID matching (first 8 chars)
-            if not any(full_id.startswith(doc_id) for full_id in all_doc_ids):
-                for path in paths:
-                    self.logger.debug(f"Deleting orphan: {path} (id: {doc_id})")
-                    try:
-                        path.unlink()
-                        stats.deleted += 1
-                    except OSError as e:
-                        self.logger.warning(f"Failed to delete orphan {path}: {e}")
-
-        # Step 5: Clean up empty folders
-        self._clean_empty_folders()
+            if any(full_id.startswith(doc_id) for full_id in all_doc_ids):
+                continue
+            for path in paths:
+                if self.manifest.is_merged(path):
+                    # Outlives the duplicates it was merged from
+                    continue
+                if self.manifest.doc_id(path) is None:
+                    self.logger.warning(
+                        f"Not deleting {path}: it looks like an export but was not "
+                        f"written by granola (not in the manifest)"
+                    )
+                    continue
+                orphans.append(path)
+        return orphans
+
+    def _delete_orphan(self, path: Path) -> bool:
+        """Delete an orphaned file, returning True if it was deleted."""
+        self.logger.debug(f"Deleting orphan: {path}")
+        try:
+            self._unlink(path)
+        except OSError as e:
+            self.logger.warning(f"Failed to delete orphan {path}: {e}")
+            return False
+        return True
 
-        return stats, results
+    def _delete_excluded_folders(self, all_doc_ids: set[str]) -> list[Path]:
+        """Delete our files in excluded folders.
 
-    def _delete_excluded_folders(self) -> int:
-        """Delete all contents of excluded folders.
+        Files we can't attribute to a Granola document are skipped.
 
         Returns:
-            Number of files deleted.
+            The files deleted.
         """
-        deleted_count = 0
+        deleted: list[Path] = []
 
         for folder_name in self.excluded_folders:
-            sanitized_name = _sanitize_folder_name(folder_name)
-            folder_path = self.output_dir / sanitized_name
+            folder_path = self.output_dir / self._nested_folder_path(folder_name)
 
             if folder_path.exists() and folder_path.is_dir():
                 self.logger.debug(f"Deleting excluded folder: {folder_path}")
                 # Delete all files in the folder
                 for file_path in folder_path.rglob("*"):
-                    if file_path.is_file():
+                    if file_path.is_file() and not self._is_protected(file_path):
+                        if not self._is_owned(file_path, all_doc_ids):
+                            self.logger.warning(
+                                f"Not deleting {file_path}: not written by granola"
+                            )
+                            continue
                         try:
-                            file_path.unlink()
-                            deleted_count += 1
+                            self._unlink(file_path)
+                            deleted.append(file_path)
                             self.logger.debug(f"Deleted: {file_path}")
                         except OSError as e:
                             self.logger.warning(f"Failed to delete {file_path}: {e}")
 
-        return deleted_count
+        return deleted
 
     def _scan_existing_files(self) -> dict[str, list[Path]]:
         """Walk the output directory and build a map of doc ID -> file paths.
@@ -176,73 +528,150 @@ class SyncWriter:
         """
         existing_files: dict[str, list[Path]] = {}
 
-        for path in self.output_dir.rglob("*.txt"):
-            if path.is_file():
-                doc_id = _extract_id_from_filename(path.name)
-                if doc_id:
-                    if doc_id not in existing_files:
-                        existing_files[doc_id] = []
-                    existing_files[doc_id].append(path)
+        # Mapped folder directories may live outside the output directory
+        roots = [self.output_dir]
+        for mapped_dir in self.folder_paths.values():
+            if not mapped_dir.is_relative_to(self.output_dir):
+                roots.append(mapped_dir)
+
+        # Files written with and without encoding both belong to their documents
+        patterns = [f"*{self.extension}"] + [f"*{self.extension}{s}" for s in ENCODED_SUFFIXES]
+        for root in roots:
+            for path in (p for pattern in patterns for p in root.rglob(pattern)):
+                if path.is_file() and not self._is_protected(path):
+                    doc_id = extract_id_from_path(path)
+                    if doc_id:
+                        if doc_id not in existing_files:
+                            existing_files[doc_id] = []
+                        if path not in existing_files[doc_id]:
+                            existing_files[doc_id].append(path)
 
         return existing_files
 
     def _process_document(
-        self, doc: ExportDoc, existing_files: dict[str, list[Path]]
+        self,
+        doc: ExportDoc,
+        existing_files: dict[str, list[Path]],
+        renamed: set[Path] | None = None,
     ) -> tuple[SyncStats, list[SyncResult]]:
         """Handle a single document: writes to appropriate folders.
 
-        Removes from folders it no longer belongs to.
+        Files in folders the document no longer belongs to are moved to its new
+        folders where possible, and removed otherwise.
+
+        Args:
+            doc: Document to write (with filtered folders).
+            existing_files: Map of short doc ID -> existing file paths.
+            renamed: Paths that were moved by a directory rename this sync.
 
         Returns:
             Tuple of (stats, list of results for each file written).
         """
         stats = SyncStats()
         results: list[SyncResult] = []
-
-        filename = self._generate_filename(doc.title, doc.id, doc.created_at)
+        renamed = renamed if renamed is not None else set()
 
         # Get short ID for matching
         short_id = doc.id[:8] if len(doc.id) >= 8 else doc.id
         existing_paths = existing_files.get(short_id, [])
 
-        # Determine target paths based on folders
-        target_paths = self._get_target_paths(doc.folders, filename)
+        # Determine target paths based on folders (or the configured layout)
+        target_paths = self.get_target_paths(doc)
 
         # Build sets for quick lookup
         existing_path_set = set(existing_paths)
         target_path_set = set(target_paths)
 
+        # Existing files that are no longer wanted can be moved to new targets
+        stale_paths = [p for p in existing_paths if p not in target_path_set]
+
+        # Files merged from different duplicates than last time are rewritten, even
+        # if none of the duplicates changed
+        merge_changed = self.manifest.merged.get(doc.id, []) != sorted(doc.merged_from)
+
         # Write to each target path
         for target_path in target_paths:
             # Create folder if needed
-            target_path.parent.mkdir(parents=True, exist_ok=True)
-
-            if target_path in existing_path_set:
+            if not self.dry_run:
+                target_path.parent.mkdir(parents=True, exist_ok=True)
+            content = self.content_filter(doc, target_path) if self.content_filter else doc.content
+            # Checksum of the file as written by this sync (files left alone keep theirs)
+            checksum: str | None = None
+
+            if target_path in renamed:
+                # Moved by a folder rename; content may still name the old folder
+                if self._needs_rewrite(target_path, content):
+                    self._write(target_path, content)
+                    checksum = self._checksum(target_path)
+                stats.count("moved", self._stats_folder(target_path))
+                results.append(SyncResult(doc=doc, action="moved", file_path=target_path))
+            elif target_path in existing_path_set:
                 # File exists at this path - check if we need to update
-                if self._should_update_file(target_path, doc.updated_at):
-                    target_path.write_text(doc.content)
+                if (
+                    self.force
+                    or merge_changed
+                    or self._should_update_file(target_path, doc.updated_at)
+                ):
+                    self._write(target_path, content)
+                    checksum = self._checksum(target_path)
+                    self._record_change("updated", target_path)
                     self.logger.debug(f"Updated: {target_path}")
-                    stats.updated += 1
+                    stats.count("updated", self._stats_folder(target_path))
                     results.append(SyncResult(doc=doc, action="updated", file_path=target_path))
                 else:
-                    stats.skipped += 1
+                    stats.count("skipped", self._stats_folder(target_path))
                     # Don't add skipped to results - only interested in changes
+            elif stale_paths and self._move_file(stale_paths[0], target_path):
+                # Moved from a folder it no longer belongs to
+                old_path = stale_paths.pop(0)
+                checksum = self.manifest.checksum(old_path)
+                self.manifest.forget(old_path)
+                self._record_change("moved", target_path, old_path)
+                if not self.dry_run and self._needs_rewrite(target_path, content):
+                    self._write(target_path, content)
+                    checksum = file_checksum(target_path)
+                stats.count("moved", self._stats_folder(target_path))
+                results.append(SyncResult(doc=doc, action="moved", file_path=target_path))
             else:
                 # New path - write the file
-                target_path.write_text(doc.content)
+                self._write(target_path, content)
+                checksum = self._checksum(target_path)
+                self._record_change("added", target_path)
                 self.logger.debug(f"Added: {target_path}")
-                stats.added += 1
+                stats.count("added", self._stats_folder(target_path))
                 results.append(SyncResult(doc=doc, action="added", file_path=target_path))
 
-        # Remove files from folders they no longer belong to
-        for existing_path in existing_paths:
-            if existing_path not in target_path_set:
-                self.logger.debug(f"Removing from old folder: {existing_path}")
+            # Files from before checksums were recorded are taken as they are
+            if self.dry_run:
+                continue
+            if checksum is None and self.manifest.checksum(target_path) is None:
+                checksum = file_checksum(target_path)
+            self.manifest.record(target_path, doc.id, checksum)
+        self.manifest.record_merge(doc.id, doc.merged_from)
+
+        # Files of duplicates merged into this document are replaced by its files
+        for source_id in doc.merged_from:
+            source_short_id = source_id[:8]
+            if source_short_id == short_id:
+                continue
+            for source_path in existing_files.pop(source_short_id, []):
+                if self.manifest.doc_id(source_path) is None:
+                    continue
+                self.logger.debug(f"Removing merged duplicate: {source_path}")
                 try:
-                    existing_path.unlink()
-                    stats.moved += 1
+                    self._unlink(source_path)
+                    stats.count("deleted", self._stats_folder(source_path))
                 except OSError as e:
-                    self.logger.warning(f"Failed to remove old file {existing_path}: {e}")
+                    self.logger.warning(f"Failed to remove merged duplicate {source_path}: {e}")
+
+        # Remove files from folders they no longer belong to
+        for existing_path in stale_paths:
+            self.logger.debug(f"Removing from old folder: {existing_path}")
+            try:
+                self._unlink(existing_path)
+                stats.count("moved", self._stats_folder(existing_path))
+            except OSError as e:
+                self.logger.warning(f"Failed to remove old file {existing_path}: {e}")
 
         # Clear processed paths from existing_files to avoid double-deletion
         if short_id in existing_files:
@@ -250,25 +679,245 @@ class SyncWriter:
 
         return stats, results
 
-    def _get_target_paths(self, folders: list[str], filename: str) -> list[Path]:
+    def _stats_folder(self, path: Path) -> str:
+        """Return the folder a file is counted under in SyncStats (see SyncStats.folders)."""
+        if path.parent.is_relative_to(self.output_dir):
+            return path.parent.relative_to(self.output_dir).as_posix()
+        # Mapped folders (folder_paths) may be outside the output directory
+        return str(path.parent)
+
+    def _record_change(self, action: str, path: Path, source: Path | None = None) -> None:
+        """Record a change to a file in the plan (dry_run only)."""
+        if self.dry_run:
+            self.plan.append(PlannedChange(action=action, path=path, source=source))
+
+    def _unlink(self, path: Path) -> None:
+        """Delete one of our files and forget it (just plan it, with dry_run).
+
+        Raises:
+            OSError: If the file can't be deleted.
+        """
+        self._record_change("deleted", path)
+        if not self.dry_run:
+            path.unlink()
+        self.manifest.forget(path)
+
+    def _checksum(self, path: Path) -> str | None:
+        """Return the checksum of a file just written (None with dry_run, as it wasn't)."""
+        return None if self.dry_run else file_checksum(path)
+
+    def _write(self, path: Path, content: str) -> None:
+        """Write a file's content (encoded, if set). Nothing is written with dry_run."""
+        if self.dry_run:
+            return
+        if self.encode:
+            path.write_bytes(self.encode(content))
+        else:
+            path.write_text(content)
+
+    def _needs_rewrite(self, path: Path, content: str) -> bool:
+        """Return True if a file needs rewriting to hold the given content."""
+        return self.force or self.encode is not None or _content_differs(path, content)
+
+    def _is_owned(self, path: Path, all_doc_ids: set[str]) -> bool:
+        """Return True if a file is in the manifest or named after a known document."""
+        if self.manifest.doc_id(path) is not None:
+            return True
+        short_id = extract_id_from_path(path)
+        return bool(short_id) and any(full_id.startswith(short_id) for full_id in all_doc_ids)
+
+    def _move_file(self, source: Path, target: Path) -> bool:
+        """Move an existing file to a new path, returning False on failure."""
+        if self.dry_run:
+            return True
+        try:
+            source.rename(target)
+        except OSError as e:
+            self.logger.warning(f"Failed to move {source} to {target}: {e}")
+            return False
+        self.logger.debug(f"Moved: {source} -> {target}")
+        return True
+
+    def _rename_folders(
+        self,
+        docs: Iterable[ExportDoc],
+        existing_files: dict[str, list[Path]],
+        all_doc_ids: set[str],
+    ) -> set[Path]:
+        """Rename directories whose documents have all moved to one new directory.
+
+        A directory is renamed when every document that has files in it now
+        targets the same, not yet existing, directory instead, no document still
+        targets the old one, and it holds nothing but our files. existing_files is
+        updated in place.
+
+        Returns:
+            The new paths of all files moved by directory renames.
+        """
+        votes: dict[Path, set[Path]] = {}
+        still_used: set[Path] = set()
+
+        for doc in docs:
+            short_id = doc.id[:8] if len(doc.id) >= 8 else doc.id
+            existing_paths = existing_files.get(short_id, [])
+            if not existing_paths:
+                continue
+
+            target_paths = self.get_target_paths(doc)
+            still_used.update(p.parent for p in target_paths)
+            new_dirs = {p.parent for p in target_paths if p not in existing_paths}
+
+            for path in existing_paths:
+                if path in target_paths:
+                    continue
+                # Ambiguous moves (several new directories) block the rename
+                votes.setdefault(path.parent, set()).update(new_dirs or {path.parent})
+
+        renamed: set[Path] = set()
+        taken: set[Path] = set()
+        for old_dir, new_dirs in votes.items():
+            if len(new_dirs) != 1 or old_dir in still_used or old_dir == self.output_dir:
+                continue
+            new_dir = next(iter(new_dirs))
+            # Directories renamed to in a dry run don't exist, but are taken all the same
+            if new_dir.exists() or new_dir in taken or new_dir.is_relative_to(old_dir):
+                continue
+            if not all(
+                self._is_owned(p, all_doc_ids) for p in old_dir.rglob("*") if p.is_file()
+            ):
+                self.logger.debug(f"Not renaming {old_dir}: it contains other files")
+                continue
+
+            try:
+                if not self.dry_run:
+                    new_dir.parent.mkdir(parents=True, exist_ok=True)
+                    old_dir.rename(new_dir)
+            except OSError as e:
+                self.logger.warning(f"Failed to rename folder {old_dir} to {new_dir}: {e}")
+                continue
+            taken.add(new_dir)
+            self._record_change("moved", new_dir, old_dir)
+            renamed_verb = "Would rename" if self.dry_run else "Renamed"
+            self.logger.info(f"{renamed_verb} folder: {old_dir} -> {new_dir}")
+            self.manifest.move_dir(old_dir, new_dir)
+
+            for paths in existing_files.values():
+                for i, path in enumerate(paths):
+                    if path.is_relative_to(old_dir):
+                        paths[i] = new_dir / path.relative_to(old_dir)
+                        renamed.add(paths[i])
+
+        return renamed
+
+    def get_target_paths(self, doc: ExportDoc) -> list[Path]:
+        """Return the paths a document is (or will be) written to.
+
+        Applies the same folder exclusions as sync().
+        """
+        folders = self._filter_folders(doc.folders)
+        if self.layout:
+            paths = [self.output_dir / path for path in self.layout(replace(doc, folders=folders))]
+        else:
+            filename = self._generate_filename(doc.title, doc.id, doc.local_start)
+            if self.organize_by == "tag":
+                paths = self._get_tag_paths(doc.tags, filename)
+            else:
+                paths = self._get_target_paths(folders, filename, doc.local_start)
+        if self.archive_before and doc.created_at < self.archive_before:
+            # The archive has its own year directories, in place of subdir_template's
+            paths = self._rebase(paths, self.output_dir / ARCHIVE_DIR / f"{doc.local_start:%Y}")
+        elif self.subdir_template:
+            subdir = format_output_template(self.subdir_template, doc.local_start)
+            paths = self._rebase(paths, self.output_dir / subdir)
+        if self.encode and self.encoded_suffix:
+            paths = [path.with_name(path.name + self.encoded_suffix) for path in paths]
+        return paths
+
+    def _rebase(self, paths: list[Path], directory: Path) -> list[Path]:
+        """Move paths in output_dir under a directory (e.g. Archive/YYYY/<path>).
+
+        Paths outside output_dir (mapped folders) are left where they are.
+        """
+        return [
+            directory / path.relative_to(self.output_dir)
+            if path.is_relative_to(self.output_dir)
+            else path
+            for path in paths
+        ]
+
+    def _filter_folders(self, folders: list[str]) -> list[str]:
+        """Drop excluded folders and, if an allow-list is set, non-included folders.
+
+        A folder counts as excluded (or included) if it or any ancestor is.
+        """
+        filtered = []
+        for folder in folders:
+            ancestry = folder_ancestry(folder, self.folder_parents)
+            if any(f in self.excluded_folders for f in ancestry):
+                continue
+            if self.included_folders and not any(f in self.included_folders for f in ancestry):
+                continue
+            filtered.append(folder)
+        return filtered
+
+    def _folder_dir(self, folder: str) -> Path:
+        """Return the directory a folder's documents are written to.
+
+        A folder_paths mapping for the folder (or its nearest mapped ancestor) wins;
+        otherwise the folder is nested under its ancestors in output_dir.
+        """
+        ancestry = folder_ancestry(folder, self.folder_parents)
+        for depth, name in enumerate(ancestry):
+            if name in self.folder_paths:
+                nested = [_sanitize_folder_name(f) for f in reversed(ancestry[:depth])]
+                return self.folder_paths[name].joinpath(*nested)
+        return self.output_dir / self._nested_folder_path(folder)
+
+    def _nested_folder_path(self, folder: str) -> Path:
+        """Return a folder's path relative to output_dir, e.g. Clients/Acme."""
+        ancestry = folder_ancestry(folder, self.folder_parents)
+        return Path(*(_sanitize_folder_name(f) for f in reversed(ancestry)))
+
+    def _get_target_paths(
+        self, folders: list[str], filename: str, started: datetime
+    ) -> list[Path]:
         """Return the full paths where the document should be written."""
+        date_dir = Path(started.strftime("%Y")) / started.strftime("%m")
+
+        if self.organize_by == "date":
+            return [self.output_dir / date_dir / filename]
+
         if not folders:
             # No folders - place in "Uncategorized" folder
-            return [self.output_dir / "Uncategorized" / filename]
+            folders = ["Uncategorized"]
 
-        paths = []
-        for folder in folders:
-            sanitized_folder = _sanitize_folder_name(folder)
-            paths.append(self.output_dir / sanitized_folder / filename)
-        return paths
+        folder_dirs = [self._folder_dir(folder) for folder in folders]
 
-    def _generate_filename(self, title: str, doc_id: str, created_at: datetime) -> str:
+        if self.organize_by == "folder-date":
+            folder_dirs = [folder_dir / date_dir for folder_dir in folder_dirs]
+
+        return [folder_dir / filename for folder_dir in folder_dirs]
+
+    def _get_tag_paths(self, tags: list[str], filename: str) -> list[Path]:
+        """Return one path per tag; documents with several tags are duplicated."""
+        if not tags:
+            return [self.output_dir / "Untagged" / filename]
+
+        # Deduplicate tags that sanitize to the same directory name
+        tag_dirs = dict.fromkeys(_sanitize_folder_name(tag) for tag in tags)
+        return [self.output_dir / tag_dir / filename for tag_dir in tag_dirs]
+
+    def _generate_filename(self, title: str, doc_id: str, started: datetime) -> str:
         """Create a filename from date, title, and ID.
 
-        Format: {YYYY-MM-DD}_{sanitized_title}_{short_id}.txt
+        Format: {YYYY-MM-DD}_{sanitized_title}_{short_id}{extension}, the date per
+        the filename date format
+        (or {YYYYMMDDHHMM}_... in zettel mode)
         """
-        # Format date as YYYY-MM-DD
-        date_prefix = created_at.strftime("%Y-%m-%d")
+        # Format the local start date as YYYY-MM-DD (the filename date format, or a zettel ID)
+        date_prefix = (
+            zettel_id(started) if self.zettel else started.strftime(date_formats().filename)
+        )
 
         name = title.strip() if title else "untitled"
 
@@ -287,7 +936,7 @@ class SyncWriter:
         # Use first 8 chars of ID
         short_id = doc_id[:8] if len(doc_id) >= 8 else doc_id
 
-        return f"{date_prefix}_{name}_{short_id}.txt"
+        return f"{date_prefix}_{name}_{short_id}{self.extension}"
 
     def _should_update_file(self, file_path: Path, doc_updated_at: datetime) -> bool:
         """Check if a file should be updated based on timestamps."""
@@ -303,35 +952,105 @@ class SyncWriter:
 
         return doc_updated_at > file_updated_at
 
-    def _clean_empty_folders(self) -> None:
-        """Remove empty directories from the output directory."""
-        # Walk in reverse order (deepest first) to clean nested empty folders
+    def empty_folders(self, deleted: Iterable[Path] = ()) -> list[Path]:
+        """Return the empty directories in the output directory, deepest first.
+
+        Args:
+            deleted: Files about to be deleted; directories holding nothing else
+                (or only directories that will be empty) count as empty too.
+        """
+        gone = set(deleted)
+        empty: list[Path] = []
+        # Walk in reverse order (deepest first) to find nested empty folders
         for path in sorted(self.output_dir.rglob("*"), reverse=True):
-            if path.is_dir() and path != self.output_dir:
+            if path.is_dir() and path != self.output_dir and not self._is_protected(path):
                 try:
-                    # Check if directory is empty
-                    if not any(path.iterdir()):
-                        self.logger.debug(f"Removing empty folder: {path}")
-                        path.rmdir()
+                    if all(child in gone for child in path.iterdir()):
+                        empty.append(path)
+                        gone.add(path)
                 except OSError:
                     pass  # Ignore errors
+        return empty
+
+    def _clean_empty_folders(self) -> list[Path]:
+        """Remove empty directories from the output directory, returning those removed."""
+        removed: list[Path] = []
+        for path in self.empty_folders():
+            try:
+                self.logger.debug(f"Removing empty folder: {path}")
+                path.rmdir()
+                removed.append(path)
+            except OSError:
+                pass  # Ignore errors
+        return removed
+
+
+def _content_differs(path: Path, content: str) -> bool:
+    """Return True if a file's content differs from the given content."""
+    try:
+        return path.read_text() != content
+    except (OSError, UnicodeDecodeError):
+        return True
+
+
+def archived_doc_ids(output_dir: Path) -> set[str]:
+    """Return the IDs of documents with files in an output directory's archive."""
+    manifest = Manifest.load(output_dir)
+    archive_dir = output_dir / ARCHIVE_DIR
+    return {
+        doc_id
+        for path in manifest.paths()
+        if path.is_relative_to(archive_dir) and (doc_id := manifest.doc_id(path))
+    }
+
+
+def folder_ancestry(folder: str, parents: dict[str, str]) -> list[str]:
+    """Return a folder followed by its ancestors, nearest first.
+
+    Args:
+        folder: Folder name.
+        parents: Map of folder name -> parent folder name.
+
+    Returns:
+        List like ["Acme", "Clients"]. Cycles in the parent map are cut off.
+    """
+    ancestry = [folder]
+    while ancestry[-1] in parents and parents[ancestry[-1]] not in ancestry:
+        ancestry.append(parents[ancestry[-1]])
+    return ancestry
+
+
+def extract_id_from_path(path: Path) -> str:
+    """Extract the document ID from an exported file path.
+
+    Expected formats: title_shortid.txt, or slug-shortid/index.md for
+    page-bundle layouts where the directory carries the ID, optionally followed
+    by one of ENCODED_SUFFIXES.
+    """
+    for suffix in ENCODED_SUFFIXES:
+        if path.name.endswith(suffix):
+            path = path.with_name(path.name[: -len(suffix)])
+            break
+    if path.stem == "index":
+        return _extract_id_from_filename(path.parent.name)
+    return _extract_id_from_filename(path.name)
 
 
 def _extract_id_from_filename(filename: str) -> str:
     """Extract the document ID from a filename.
 
-    Expected format: title_shortid.txt
+    Expected format: title_shortid.txt (or slug-shortid.md)
     """
-    # Remove .txt extension
-    name = filename.removesuffix(".txt")
+    # Remove the extension
+    name = filename.rsplit(".", 1)[0] if "." in filename else filename
 
-    # Find the last underscore
-    last_underscore = name.rfind("_")
-    if last_underscore == -1 or last_underscore == len(name) - 1:
+    # Find the last separator
+    last_sep = max(name.rfind("_"), name.rfind("-"))
+    if last_sep == -1 or last_sep == len(name) - 1:
         return ""
 
     # Extract the ID portion (should be 8 chars for short ID)
-    doc_id = name[last_underscore + 1 :]
+    doc_id = name[last_sep + 1 :]
     if len(doc_id) >= 8:
         return doc_id[:8]  # Return just the short ID for matching
 
@@ -0,0 +1,105 @@
+"""TextPack packaging: a note and its images in one file, for Bear, Ulysses and Craft.
+
+A TextPack is a zipped TextBundle (https://textbundle.org): a folder holding
+the note as text.md, an info.json, and the files it links to in assets/.
+"""
+
+import io
+import json
+import logging
+import re
+import zipfile
+
+import httpx
+
+from granola.utils.filename import sanitize_filename
+from granola.writers.attachments import MARKDOWN_LINK, attachment_name, is_attachment
+
+# Extension of TextPack files
+TEXTPACK_EXTENSION = ".textpack"
+
+# Written into info.json as the bundle's creator
+CREATOR_IDENTIFIER = "ai.granola.export"
+
+# Zip entries get a fixed timestamp, so the same note always packs to the same bytes
+_ZIP_DATE = (1980, 1, 1, 0, 0, 0)
+
+_TITLE = re.compile(r"^#\s+(.+)$", re.MULTILINE)
+
+
+class TextPacker:
+    """Packs Markdown into a TextPack, downloading what it links to (a SyncWriter encoder).
+
+    Images, and links to files such as PDFs, are downloaded into the bundle's
+    assets/ and the note links to the copies. A file that fails to download
+    keeps its URL.
+    """
+
+    def __init__(
+        self,
+        timeout: int = 120,
+        transport: httpx.BaseTransport | None = None,
+        logger: logging.Logger | None = None,
+    ):
+        """Initialize the packer.
+
+        Args:
+            timeout: HTTP timeout in seconds for downloads.
+            transport: Optional httpx transport downloads are sent through.
+            logger: Optional logger for failed downloads.
+        """
+        self.timeout = timeout
+        self.transport = transport
+        self.logger = logger or logging.getLogger(__name__)
+
+    def __call__(self, content: str) -> bytes:
+        """Return a TextPack of the Markdown content and its assets."""
+        assets: dict[str, bytes] = {}
+
+        def local(match: re.Match[str]) -> str:
+            image, text, url = match.groups()
+            if not image and not is_attachment(url):
+                return match.group()
+            name = attachment_name(url)
+            if name not in assets:
+                data = self._download(url)
+                if data is None:
+                    return match.group()
+                assets[name] = data
+            return f"{image}[{text}](assets/{name})"
+
+        text = MARKDOWN_LINK.sub(local, content)
+        title = _TITLE.search(text)
+        bundle = sanitize_filename(title.group(1) if title else "", fallback="note")
+        info = {
+            "version": 2,
+            "type": "net.daringfireball.markdown",
+            "transient": False,
+            "creatorIdentifier": CREATOR_IDENTIFIER,
+        }
+
+        buffer = io.BytesIO()
+        with zipfile.ZipFile(buffer, "w", zipfile.ZIP_DEFLATED) as zf:
+            _add(zf, f"{bundle}.textbundle/info.json", json.dumps(info, indent=2).encode())
+            _add(zf, f"{bundle}.textbundle/text.md", text.encode("utf-8"))
+            for name, data in sorted(assets.items()):
+                _add(zf, f"{bundle}.textbundle/assets/{name}", data)
+        return buffer.getvalue()
+
+    def _download(self, url: str) -> bytes | None:
+        """Download a URL, returning None (and logging) if it fails."""
+        try:
+            with httpx.Client(
+                timeout=self.timeout, transport=self.transport, follow_redirects=True
+            ) as client:
+                response = client.get(url)
+                response.raise_for_status()
+        except httpx.HTTPError as e:
+            self.logger.warning(f"Failed to download attachment {url}: {e}")
+            return None
+        return response.content
+
+
+def _add(zf: zipfile.ZipFile, name: str, data: bytes) -> None:
+    """Add a file to a zip with a fixed timestamp."""
+    zf.writestr(zipfile.ZipInfo(name, date_time=_ZIP_DATE), data, zipfile.ZIP_DEFLATED)
@@ -0,0 +1,60 @@
+"""Check an export's files against the checksums in its manifest.
+
+Files changed outside granola (a cloud-sync conflict, an accidental edit) are
+overwritten by the next export that updates them; verifying first finds them.
+"""
+
+from dataclasses import dataclass, field
+from pathlib import Path
+
+from granola.writers.manifest import Manifest, file_checksum
+from granola.writers.sync_writer import extract_id_from_path
+
+
+@dataclass
+class VerifyReport:
+    """Differences between an output directory and its manifest."""
+
+    modified: list[Path] = field(default_factory=list)  # content differs from the export
+    missing: list[Path] = field(default_factory=list)  # in the manifest, but gone
+    added: list[Path] = field(default_factory=list)  # named like an export, not in the manifest
+    unchecked: list[Path] = field(default_factory=list)  # no checksum recorded
+    checked: int = 0  # files whose checksum matched
+
+    @property
+    def in_sync(self) -> bool:
+        """Return True if no file was modified, removed or added."""
+        return not (self.modified or self.missing or self.added)
+
+
+def verify_output(output_dir: Path) -> VerifyReport:
+    """Compare the files in an output directory with its manifest.
+
+    Files added out-of-band are ones named like an export (ending in a document
+    ID, with the extension of the exported files) that the manifest doesn't list.
+    Hidden directories (.obsidian, .trash, ...) are not searched.
+    """
+    manifest = Manifest.load(output_dir)
+    report = VerifyReport()
+    known: set[Path] = set()
+    for path in sorted(manifest.paths()):
+        known.add(path)
+        expected = manifest.checksum(path)
+        if not path.is_file():
+            report.missing.append(path)
+        elif expected is None:
+            report.unchecked.append(path)
+        elif file_checksum(path) != expected:
+            report.modified.append(path)
+        else:
+            report.checked += 1
+
+    extensions = {path.suffix for path in known if path.suffix}
+    for extension in sorted(extensions):
+        for path in sorted(output_dir.rglob(f"*{extension}")):
+            relative = path.relative_to(output_dir)
+            if any(part.startswith(".") for part in relative.parts):
+                continue
+            if path.is_file() and path not in known and extract_id_from_path(path):
+                report.added.append(path)
+    return report
@@ -0,0 +1,26 @@
+"""Tests for converting Go date layouts to strftime formats."""
+
+import pytest
+
+from granola.utils.dates import to_strftime
+
+
+@pytest.mark.parametrize(
+    ("layout", "expected"),
+    [
+        ("2006-01-02", "%Y-%m-%d"),
+        ("02 Jan 2006 15:04", "%d %b %Y %H:%M"),
+        ("Jan 2, 3:04PM", "%b %d, %I:%M%p"),
+        ("Jan _2 2006", "%b %d %Y"),
+        ("Monday, January 2", "%A, %B %d"),
+        ("Q1 2006", "Q1 %Y"),
+        ("v2-2006-01-02", "v2-%Y-%m-%d"),
+        ("Jan2", "%b2"),
+    ],
+)
+def test_converts_go_layouts(layout: str, expected: str) -> None:
+    assert to_strftime(layout) == expected
+
+
+def test_leaves_strftime_formats_alone() -> None:
+    assert to_strftime("Week 1 of %Y") == "Week 1 of %Y"
@@ -0,0 +1,34 @@
+"""Tests for filtering documents by folder (--folder, --exclude-folder)."""
+
+from granola.source import is_filtered_out
+
+# Clients > Acme > Acme Weekly, and a top-level Personal folder
+PARENTS = {"Acme": "Clients", "Acme Weekly": "Acme"}
+
+
+def test_no_filters_keeps_everything() -> None:
+    assert not is_filtered_out([], set(), set())
+    assert not is_filtered_out(["Personal"], set(), set(), PARENTS)
+
+
+def test_excluded_folder() -> None:
+    assert is_filtered_out(["Personal"], {"Personal"}, set())
+    assert is_filtered_out(["Acme", "Personal"], {"Personal"}, set())
+    assert not is_filtered_out(["Acme"], {"Personal"}, set())
+
+
+def test_included_folders_are_an_allow_list() -> None:
+    assert not is_filtered_out(["Acme"], set(), {"Acme"})
+    assert is_filtered_out(["Personal"], set(), {"Acme"})
+    assert is_filtered_out([], set(), {"Acme"})
+
+
+def test_subfolders_count_as_their_parents() -> None:
+    assert not is_filtered_out(["Acme Weekly"], set(), {"Clients"}, PARENTS)
+    assert is_filtered_out(["Acme Weekly"], {"Clients"}, set(), PARENTS)
+    # Without the parent map, a subfolder is only itself
+    assert is_filtered_out(["Acme Weekly"], set(), {"Clients"})
+
+
+def test_exclusion_wins_over_inclusion() -> None:
+    assert is_filtered_out(["Acme"], {"Acme"}, {"Clients"}, PARENTS)
@@ -0,0 +1,57 @@
+"""Tests for selecting and ordering documents (--match, --sort, --limit, --skip)."""
+
+import re
+from types import SimpleNamespace
+
+from granola.utils.selection import page, sort_documents, title_selected
+
+
+def _doc(title: str, created_at: str, updated_at: str | None = None) -> SimpleNamespace:
+    return SimpleNamespace(title=title, created_at=created_at, updated_at=updated_at or created_at)
+
+
+def test_page_skips_then_limits() -> None:
+    items = list(range(10))
+    assert page(items, 3, 0) == [0, 1, 2]
+    assert page(items, 3, 4) == [4, 5, 6]
+    assert page(items, None, 7) == [7, 8, 9]
+    assert page(items, 5, 8) == [8, 9]
+    assert page(items, 0, 0) == []
+
+
+def test_sort_by_created_defaults_to_newest_first() -> None:
+    docs = [
+        _doc("a", "2024-01-01T10:00:00Z"),
+        _doc("b", "2024-03-01T10:00:00Z"),
+        _doc("c", "2024-02-01T10:00:00Z"),
+    ]
+    assert [d.title for d in sort_documents(docs, "created")] == ["b", "c", "a"]
+    assert [d.title for d in sort_documents(docs, "created", "asc")] == ["a", "c", "b"]
+
+
+def test_sort_by_title_is_case_insensitive_and_a_to_z() -> None:
+    docs = [_doc("beta", "2024-01-01"), _doc("Alpha", "2024-01-01"), _doc("", "2024-01-01")]
+    assert [d.title for d in sort_documents(docs, "title")] == ["", "Alpha", "beta"]
+
+
+def test_sort_puts_unparseable_times_oldest() -> None:
+    docs = [_doc("bad", "not a date"), _doc("good", "2024-01-01T10:00:00Z")]
+    assert [d.title for d in sort_documents(docs, "updated")] == ["good", "bad"]
+
+
+def test_sort_keeps_ties_in_order_and_unwraps_items() -> None:
+    items = [("x", _doc("same", "2024-01-01")), ("y", _doc("same", "2024-01-01"))]
+    sorted_items = sort_documents(items, "title", doc=lambda item: item[1])
+    assert [key for key, _ in sorted_items] == ["x", "y"]
+
+
+def test_title_selected() -> None:
+    match = re.compile("standup", re.IGNORECASE)
+    exclude = re.compile("^Cancelled")
+    assert title_selected("Daily Standup", match)
+    assert not title_selected("Retro", match)
+    assert not title_selected("Cancelled standup", match, exclude)
+    assert title_selected(None)
+    assert not title_selected("Untitled", skip_untitled=True)
+    assert not title_selected("  ", skip_untitled=True)
+    assert title_selected("Untitled meeting notes", skip_untitled=True)
@@ -0,0 +1,98 @@
+"""Tests for SyncWriter's dry run and prune."""
+
+from datetime import datetime, timezone
+from pathlib import Path
+
+from granola.writers.sync_writer import ExportDoc, SyncWriter
+
+UPDATED = datetime(2024, 1, 2, 9, 0, tzinfo=timezone.utc)
+
+
+def _doc(doc_id: str, title: str, folders: list[str]) -> ExportDoc:
+    return ExportDoc(
+        id=doc_id,
+        title=title,
+        created_at=UPDATED,
+        updated_at=UPDATED,
+        content=f"# {title}\n",
+        folders=folders,
+    )
+
+
+def _files(directory: Path) -> list[Path]:
+    return sorted(p.relative_to(directory) for p in directory.rglob("*") if p.is_file())
+
+
+STANDUP = _doc("aaaa1111-2222", "Standup", ["Team"])
+RETRO = _doc("bbbb3333-4444", "Retro", [])
+
+
+def test_dry_run_plans_without_writing(tmp_path: Path) -> None:
+    output = tmp_path / "out"
+    writer = SyncWriter(output, dry_run=True)
+
+    stats, results = writer.sync([STANDUP], {STANDUP.id})
+
+    assert stats.added == 1
+    assert [r.action for r in results] == ["added"]
+    assert [(c.action, c.path) for c in writer.plan] == [("added", results[0].file_path)]
+    assert not output.exists()
+
+
+def test_dry_run_plans_renames_and_deletions(tmp_path: Path) -> None:
+    SyncWriter(tmp_path).sync([STANDUP, RETRO], {STANDUP.id, RETRO.id})
+    before = _files(tmp_path)
+    writer = SyncWriter(tmp_path, dry_run=True)
+
+    # Team renamed to Leads in Granola, and Retro deleted
+    moved = _doc(STANDUP.id, STANDUP.title, ["Leads"])
+    stats, _ = writer.sync([moved], {moved.id})
+
+    assert (stats.moved, stats.deleted) == (1, 1)
+    actions = {c.action: c for c in writer.plan}
+    assert (actions["moved"].source, actions["moved"].path) == (
+        tmp_path / "Team",
+        tmp_path / "Leads",
+    )
+    assert actions["deleted"].path.parent == tmp_path / "Uncategorized"
+    assert RETRO.id[:8] in actions["deleted"].path.name
+    assert _files(tmp_path) == before
+
+
+def test_prune_dry_run_lists_orphans_and_empty_folders(tmp_path: Path) -> None:
+    writer = SyncWriter(tmp_path)
+    _, results = writer.sync([STANDUP, RETRO], {STANDUP.id, RETRO.id})
+    standup_path = next(r.file_path for r in results if r.doc.id == STANDUP.id)
+    before = _files(tmp_path)
+
+    orphans, folders = writer.prune({RETRO.id}, dry_run=True)
+
+    assert orphans == [standup_path]
+    assert folders == [tmp_path / "Team"]
+    assert _files(tmp_path) == before
+
+
+def test_prune_deletes_orphans_and_empty_folders(tmp_path: Path) -> None:
+    writer = SyncWriter(tmp_path)
+    _, results = writer.sync([STANDUP, RETRO], {STANDUP.id, RETRO.id})
+    retro_path = next(r.file_path for r in results if r.doc.id == RETRO.id)
+
+    deleted, removed = writer.prune({RETRO.id})
+
+    assert [p.name for p in deleted] == [f"2024-01-02_Standup_{STANDUP.id[:8]}.txt"]
+    assert removed == [tmp_path / "Team"]
+    assert retro_path.exists()
+    assert not (tmp_path / "Team").exists()
+
+
+def test_prune_leaves_files_it_did_not_write(tmp_path: Path) -> None:
+    writer = SyncWriter(tmp_path)
+    writer.sync([STANDUP], {STANDUP.id})
+    notes = tmp_path / "Team" / "my notes.txt"
+    notes.write_text("mine")
+
+    deleted, removed = writer.prune(set())
+
+    assert len(deleted) == 1
+    assert removed == []
+    assert notes.exists()